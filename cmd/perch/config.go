@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/config"
+	"github.com/andyrewlee/perch/internal/tui"
+)
+
+// loadConfig layers the built-in defaults, the config file and environment
+// variables. An empty path reads $PERCH_CONFIG, falling back to the default
+// location; only a file that was asked for explicitly must exist.
+func loadConfig(path string, getenv func(string) string) (config.Config, error) {
+	if path == "" {
+		path = getenv("PERCH_CONFIG")
+	}
+	required := path != ""
+	if path == "" {
+		var err error
+		if path, err = config.DefaultPath(); err != nil {
+			cfg := config.Default()
+			return cfg, cfg.ApplyEnv(getenv)
+		}
+	}
+
+	cfg, err := config.Load(path, required)
+	if err != nil {
+		return cfg, err
+	}
+	if err := cfg.ApplyEnv(getenv); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// tuiFlags are the command-line overrides for the TUI, applied over the
+// config file and environment.
+type tuiFlags struct {
+	config   string
+	town     string
+	remote   string
+	refresh  time.Duration
+	dryRun   bool
+	readOnly bool
+}
+
+// parseTUIFlags parses `perch [--config FILE] [--town DIR|NAME]
+// [--remote USER@HOST:DIR] [--refresh D] [--dry-run] [--read-only]`.
+func parseTUIFlags(fs *flag.FlagSet, args []string) (tuiFlags, error) {
+	var f tuiFlags
+	fs.StringVar(&f.config, "config", "", configFlagUsage)
+	fs.StringVar(&f.town, "town", "", "Gas Town workspace or registered town name (overrides town_root and GT_ROOT)")
+	fs.StringVar(&f.remote, "remote", "", "watch a town on another machine over ssh, as user@host:/path/to/gt")
+	fs.DurationVar(&f.refresh, "refresh", 0, "auto-refresh interval (overrides refresh.interval)")
+	fs.BoolVar(&f.dryRun, "dry-run", false, "preview the gt/bd commands actions would run without running them")
+	fs.BoolVar(&f.readOnly, "read-only", false, "disable every action, for wall displays and observers")
+	if err := fs.Parse(args); err != nil {
+		return f, err
+	}
+	if fs.NArg() > 0 {
+		return f, fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+	if f.remote != "" {
+		if f.town != "" {
+			return f, fmt.Errorf("--town and --remote cannot be used together")
+		}
+		if _, _, err := data.ParseRemote(f.remote); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
+
+// load reads the config and applies the flags on top. The TUI calls it
+// again to reload at runtime, so flags keep precedence over file edits.
+func (f tuiFlags) load(getenv func(string) string) (config.Config, error) {
+	cfg, err := loadConfig(f.config, getenv)
+	if err != nil {
+		return cfg, err
+	}
+	if f.town != "" {
+		cfg.TownRoot = cfg.ResolveTown(f.town)
+	}
+	if f.remote != "" {
+		cfg.Remote, cfg.TownRoot, _ = data.ParseRemote(f.remote)
+	}
+	if f.refresh != 0 {
+		cfg.RefreshInterval = f.refresh
+	}
+	cfg.DryRun = f.dryRun
+	cfg.ReadOnly = f.readOnly
+	if _, err := tui.NewKeyMap(cfg.Keys); err != nil {
+		return cfg, fmt.Errorf("%s: %w", cfg.Path, err)
+	}
+	return cfg, cfg.Validate()
+}
+
+// newLoader creates a loader for the headless subcommands, over SSH for a
+// remote town and on the configured refresh schedule.
+func newLoader(cfg config.Config) *data.Loader {
+	loader := data.NewLoader(cfg.TownRoot)
+	if cfg.Remote != "" {
+		loader = data.NewRemoteLoader(cfg.Remote, cfg.TownRoot)
+	}
+	loader.LifecycleEvents = cfg.LifecycleEvents
+	loader.Schedule = schedule(cfg)
+	return loader
+}
+
+// schedule returns the refresh schedule cfg configures.
+func schedule(cfg config.Config) data.Schedule {
+	return data.DefaultSchedule(cfg.RefreshInterval).WithLiveInterval(cfg.StoreRefreshInterval)
+}
+
+// opener reads the config file at path, or the default one when path is
+// empty, and creates a loader for its town. Headless subcommands call it
+// with their --config flag.
+type opener func(path string) (config.Config, *data.Loader, error)
+
+// openTown is the opener headless subcommands run with.
+func openTown(path string) (config.Config, *data.Loader, error) {
+	cfg, err := loadConfig(path, os.Getenv)
+	if err != nil {
+		return cfg, nil, err
+	}
+	return cfg, newLoader(cfg), nil
+}
+
+// configFlagUsage describes the --config flag every command accepts.
+const configFlagUsage = "config file (default $PERCH_CONFIG or ~/.config/perch/config.toml)"
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/config"
+)
+
+func TestLoadConfigLayers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(path, []byte("town_root = \"/file/gt\"\n[refresh]\ninterval = \"20s\"\n"), 0o644)
+
+	env := map[string]string{"PERCH_CONFIG": path}
+	getenv := func(k string) string { return env[k] }
+
+	cfg, err := loadConfig("", getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TownRoot != "/file/gt" || cfg.RefreshInterval != 20*time.Second {
+		t.Errorf("expected file settings, got %q %s", cfg.TownRoot, cfg.RefreshInterval)
+	}
+
+	env["GT_ROOT"] = "/env/gt"
+	if cfg, _ = loadConfig("", getenv); cfg.TownRoot != "/env/gt" {
+		t.Errorf("expected GT_ROOT over the file, got %q", cfg.TownRoot)
+	}
+
+	fs := flag.NewFlagSet("perch", flag.ContinueOnError)
+	flags, err := parseTUIFlags(fs, []string{"--town", "/flag/gt", "--refresh", "3s", "--dry-run", "--read-only"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err = flags.load(getenv); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TownRoot != "/flag/gt" || cfg.RefreshInterval != 3*time.Second || !cfg.DryRun || !cfg.ReadOnly {
+		t.Errorf("expected flags over env and file, got %q %s dry run %t read-only %t", cfg.TownRoot, cfg.RefreshInterval, cfg.DryRun, cfg.ReadOnly)
+	}
+}
+
+func TestTownFlagTakesRegisteredNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(path, []byte("[[towns]]\nname = \"web\"\nroot = \"/srv/gt-web\"\n"), 0o644)
+	getenv := func(string) string { return "" }
+
+	for town, want := range map[string]string{"web": "/srv/gt-web", "/srv/other": "/srv/other"} {
+		flags, err := parseTUIFlags(flag.NewFlagSet("perch", flag.ContinueOnError), []string{"--config", path, "--town", town})
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := flags.load(getenv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.TownRoot != want {
+			t.Errorf("--town %s: expected %q, got %q", town, want, cfg.TownRoot)
+		}
+	}
+}
+
+func TestRemoteFlag(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	getenv := func(string) string { return "" }
+
+	flags, err := parseTUIFlags(flag.NewFlagSet("perch", flag.ContinueOnError), []string{"--remote", "deploy@build-01:/srv/gt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := flags.load(getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Remote != "deploy@build-01" || cfg.TownRoot != "/srv/gt" {
+		t.Errorf("expected the remote host and root, got %q %q", cfg.Remote, cfg.TownRoot)
+	}
+
+	for _, args := range [][]string{
+		{"--remote", "build-01"},
+		{"--remote", "build-01:gt"},
+		{"--remote", "build-01:/srv/gt", "--town", "/srv/gt"},
+	} {
+		fs := flag.NewFlagSet("perch", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		if _, err := parseTUIFlags(fs, args); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
+
+func TestLoadConfigMissingFiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	none := func(string) string { return "" }
+	if _, err := loadConfig("", none); err != nil {
+		t.Errorf("expected defaults without a config file, got %v", err)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.toml")
+	if _, err := loadConfig(missing, none); err == nil {
+		t.Error("expected an error for an explicit missing --config")
+	}
+	if _, err := loadConfig("", func(k string) string {
+		if k == "PERCH_CONFIG" {
+			return missing
+		}
+		return ""
+	}); err == nil {
+		t.Error("expected an error for a missing $PERCH_CONFIG")
+	}
+}
+
+func TestTUIFlagsValidate(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	fs := flag.NewFlagSet("perch", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	flags, err := parseTUIFlags(fs, []string{"--refresh", "-1s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = flags.load(func(string) string { return "" })
+	if err == nil || !strings.Contains(err.Error(), "refresh.interval must be positive") {
+		t.Errorf("expected refresh validation error, got %v", err)
+	}
+
+	fs = flag.NewFlagSet("perch", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if _, err := parseTUIFlags(fs, []string{"statsu"}); err == nil {
+		t.Error("expected an error for an unknown command")
+	}
+}
+
+func TestTUIFlagsRejectKeyConflicts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(path, []byte("[keys]\nrefresh = \"q\"\n"), 0o644)
+
+	fs := flag.NewFlagSet("perch", flag.ContinueOnError)
+	flags, err := parseTUIFlags(fs, []string{"--config", path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = flags.load(func(string) string { return "" })
+	if err == nil || !strings.Contains(err.Error(), path) || !strings.Contains(err.Error(), "bound to both") {
+		t.Errorf("expected key conflict error naming the file, got %v", err)
+	}
+}
+
+func TestNewLoaderFollowsConfig(t *testing.T) {
+	cfg := config.Default()
+	cfg.Remote, cfg.TownRoot = "deploy@build-01", "/srv/gt"
+	cfg.RefreshInterval = 20 * time.Second
+	cfg.StoreRefreshInterval = 2 * time.Second
+
+	loader := newLoader(cfg)
+	if _, ok := loader.FS.(data.RemoteFS); !ok || loader.TownRoot != "/srv/gt" {
+		t.Errorf("expected a remote loader for /srv/gt, got %#v", loader)
+	}
+	if got := loader.Schedule.Policy(data.SourceIssues).Interval; got != 20*time.Second {
+		t.Errorf("expected issues every refresh.interval, got %s", got)
+	}
+	if got := loader.Schedule.Policy(data.SourceTownStatus).Interval; got != 2*time.Second {
+		t.Errorf("expected town status every refresh.store_interval, got %s", got)
+	}
+}
+
+func TestOpenTown(t *testing.T) {
+	t.Setenv("GT_ROOT", "")
+	path := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(path, []byte("town_root = \"/file/gt\"\n[refresh]\nload_timeout = \"5s\"\n"), 0o644)
+
+	cfg, loader, err := openTown(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loader.TownRoot != "/file/gt" || cfg.LoadTimeout != 5*time.Second {
+		t.Errorf("expected the file's town and timeout, got %q %s", loader.TownRoot, cfg.LoadTimeout)
+	}
+	if _, _, err := openTown(filepath.Join(t.TempDir(), "missing.toml")); err == nil {
+		t.Error("expected an error for a missing --config file")
+	}
+}
@@ -6,39 +6,91 @@
 //
 // Usage:
 //
-//	perch
+//	perch [--config FILE] [--town DIR|NAME]           Run the TUI
+//	      [--remote USER@HOST:DIR]
+//	      [--refresh D] [--dry-run] [--read-only]
+//	perch status [--config FILE] [--json|--text]      Print a one-shot health summary
+//	             [--rig NAME]
+//	perch serve [--config FILE] [--addr ADDR]         Serve the town over HTTP/JSON
+//	            [--read-only]
+//	perch metrics [--config FILE]                     Print Prometheus metrics once
+//
+// perch status exits 0 when the town is healthy and 1 when operational
+// issues or doctor errors are detected, so it can be used in scripts and CI.
+// perch serve refuses to listen beyond loopback without a token.
+//
+// Settings are read from ~/.config/perch/config.toml (see package
+// internal/config). Environment variables override the file and flags
+// override both. README.md describes the dashboard's features.
 //
 // Environment Variables:
 //
-//	GT_ROOT - Path to the Gas Town workspace (default: ~/gt)
+//	PERCH_CONFIG        - Config file (default: ~/.config/perch/config.toml)
+//	GT_ROOT             - Path to the Gas Town workspace (default: ~/gt)
+//	PERCH_TOKEN         - Bearer token for perch serve (overridden by --token)
+//	PERCH_NOTIFY        - Notification methods for the TUI: osc9, osc777, bell
+//	PERCH_NOTIFY_CMD    - Notification command hook, e.g. "notify-send"
+//	PERCH_NOTIFY_EVENTS - Notification conditions to enable (default all)
+//	PERCH_NOTIFY_QUIET  - Notification quiet hours, e.g. 22:00-07:00
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/andyrewlee/perch/internal/config"
 	"github.com/andyrewlee/perch/internal/tui"
 )
 
 // main is the entry point for the perch TUI.
-// It initializes the town root, creates the Bubble Tea program,
+// It loads the config, creates the Bubble Tea program,
 // and runs it with an alternate screen.
 func main() {
-	// Default to ~/gt as town root, can be overridden via GT_ROOT env
-	townRoot := os.Getenv("GT_ROOT")
-	if townRoot == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error getting home dir: %v\n", err)
-			os.Exit(1)
+	// Headless subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "status":
+			os.Exit(runStatus(openTown, os.Args[2:], os.Stdout, os.Stderr))
+		case "serve":
+			os.Exit(runServe(openTown, os.Args[2:], os.Stdout, os.Stderr))
+		case "metrics":
+			os.Exit(runMetrics(openTown, os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
+
+	flags, err := parseTUIFlags(flag.NewFlagSet("perch", flag.ContinueOnError), os.Args[1:])
+	if err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		}
-		townRoot = filepath.Join(home, "gt")
+		os.Exit(exitUsage)
 	}
+	reload := func() (config.Config, error) { return flags.load(os.Getenv) }
+	cfg, err := reload()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitUsage)
+	}
+
+	p := tea.NewProgram(tui.NewWithConfig(cfg, reload), tea.WithAltScreen())
+
+	// SIGHUP rereads the config file, like ctrl+r
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			p.Send(tui.ReloadConfigMsg{})
+		}
+	}()
 
-	p := tea.NewProgram(tui.NewWithTownRoot(townRoot), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	_, err = p.Run()
+	signal.Stop(hup)
+	close(hup)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/andyrewlee/perch/internal/metrics"
+)
+
+// runMetrics implements `perch metrics [--config FILE]`.
+// It loads a single snapshot and prints its gauges in the Prometheus text
+// format, for use with the node exporter's textfile collector or similar.
+func runMetrics(open opener, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("metrics", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", configFlagUsage)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", fs.Arg(0))
+		return exitUsage
+	}
+
+	cfg, loader, err := open(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.LoadTimeout)
+	defer cancel()
+
+	snap := loader.LoadAll(ctx)
+	if err := metrics.Write(stdout, snap, time.Now()); err != nil {
+		fmt.Fprintf(stderr, "Error: writing metrics: %v\n", err)
+		return exitUnhealthy
+	}
+	return exitOK
+}
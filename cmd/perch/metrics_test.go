@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+func TestRunMetrics(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	loader := data.NewLoaderWithRunner("/tmp/town", newStatusMock())
+	if code := runMetrics(openLoader(loader), nil, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+	out := stdout.String()
+	for _, line := range []string{
+		"# TYPE perch_merge_queue_depth gauge",
+		`perch_merge_queue_depth{rig="perch"} 2`,
+		`perch_merge_queue_depth{rig="sidekick"} 1`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %q in output:\n%s", line, out)
+		}
+	}
+}
+
+func TestRunMetricsUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	loader := data.NewLoaderWithRunner("/tmp/town", newStatusMock())
+	if code := runMetrics(openLoader(loader), []string{"extra"}, &stdout, &stderr); code != exitUsage {
+		t.Errorf("expected exit %d, got %d", exitUsage, code)
+	}
+}
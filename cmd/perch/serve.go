@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/server"
+	"github.com/andyrewlee/perch/internal/tui"
+	"github.com/andyrewlee/perch/internal/webhook"
+)
+
+// serveShutdownTimeout bounds graceful shutdown of in-flight requests.
+const serveShutdownTimeout = 5 * time.Second
+
+// runServe implements `perch serve [--config FILE] [--addr ADDR] [--token TOKEN]
+// [--read-only] [--interval D] [--webhooks FILE]`. It refreshes the store in the background
+// and serves it until interrupted. Actions run through the loader's command
+// runner and files, so reads and writes reach the same town.
+// Webhooks are posted from serve rather than the TUI so that each town has
+// one sender no matter how many operators have perch open.
+func runServe(open opener, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", configFlagUsage)
+	addr := fs.String("addr", "127.0.0.1:7777", "address to listen on")
+	token := fs.String("token", os.Getenv("PERCH_TOKEN"), "bearer token required on every request (default $PERCH_TOKEN)")
+	readOnly := fs.Bool("read-only", false, "reject all write endpoints")
+	interval := fs.Duration("interval", 0, "refresh interval (default refresh.interval)")
+	webhooks := fs.String("webhooks", "", "webhook rules file (default ~/.perch/webhooks.json if present)")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *interval < 0 {
+		fmt.Fprintln(stderr, "Error: --interval must be positive")
+		return exitUsage
+	}
+	if *token == "" && !isLoopback(*addr) {
+		fmt.Fprintf(stderr, "Error: refusing to serve on %s without --token\n", *addr)
+		return exitUsage
+	}
+
+	cfg, loader, err := open(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+	if *interval > 0 {
+		cfg.RefreshInterval = *interval
+		loader.Schedule = schedule(cfg)
+	}
+
+	hooks, err := loadWebhooks(*webhooks)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	store := data.NewStoreWithLoader(loader)
+	store.RefreshInterval = cfg.RefreshInterval
+	actions := tui.NewActionRunnerWithRunner(loader.TownRoot, loader.Runner)
+	actions.FS = loader.FS
+	handler := server.New(store, actions, server.Options{Token: *token, ReadOnly: *readOnly})
+	if hooks != nil {
+		dispatcher := webhook.NewDispatcher(hooks)
+		dispatcher.OnError = func(rule string, err error) {
+			fmt.Fprintf(stderr, "webhook %s: %v\n", rule, err)
+		}
+		dispatcher.Attach(store)
+		dispatcher.Start(ctx)
+		defer dispatcher.Stop()
+		fmt.Fprintf(stdout, "perch posting webhooks for %d rules\n", len(hooks.Rules))
+	}
+	store.StartAutoRefresh(ctx)
+	defer store.Stop()
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		// Cancel long-lived event streams on shutdown
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServe() }()
+
+	mode := "read-write"
+	if *readOnly {
+		mode = "read-only"
+	}
+	fmt.Fprintf(stdout, "perch serving %s on http://%s (%s)\n", loader.TownRoot, *addr, mode)
+
+	select {
+	case err := <-errCh:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return exitUnhealthy
+		}
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			fmt.Fprintf(stderr, "Error: shutting down: %v\n", err)
+			return exitUnhealthy
+		}
+	}
+	return exitOK
+}
+
+// loadWebhooks loads the webhook rules at path. With no path it tries the
+// default location and returns nil if nothing is configured there.
+func loadWebhooks(path string) (*webhook.Config, error) {
+	if path != "" {
+		return webhook.LoadConfig(path)
+	}
+	path, err := webhook.DefaultConfigPath()
+	if err != nil {
+		return nil, nil
+	}
+	cfg, err := webhook.LoadConfig(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return cfg, err
+}
+
+// isLoopback reports whether addr only listens on a loopback interface.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+func TestIsLoopback(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"127.0.0.1:7777", true},
+		{"localhost:7777", true},
+		{"[::1]:7777", true},
+		{"0.0.0.0:7777", false},
+		{":7777", false},
+		{"10.0.0.5:7777", false},
+		{"bogus", false},
+	}
+	for _, tt := range tests {
+		if got := isLoopback(tt.addr); got != tt.want {
+			t.Errorf("isLoopback(%q) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestRunServeRequiresTokenOffLoopback(t *testing.T) {
+	t.Setenv("PERCH_TOKEN", "")
+	var stdout, stderr bytes.Buffer
+	code := runServe(openLoader(data.NewLoader(t.TempDir())), []string{"--addr", "0.0.0.0:0"}, &stdout, &stderr)
+	if code != exitUsage {
+		t.Errorf("expected exit %d, got %d", exitUsage, code)
+	}
+	if !strings.Contains(stderr.String(), "without --token") {
+		t.Errorf("expected token error, got %q", stderr.String())
+	}
+}
+
+func TestLoadWebhooks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := loadWebhooks("")
+	if err != nil || cfg != nil {
+		t.Errorf("expected no webhooks without a default file, got %v, %v", cfg, err)
+	}
+
+	if _, err := loadWebhooks(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for an explicit missing file")
+	}
+
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	os.WriteFile(path, []byte(`{"rules": [{"name": "chat", "url": "http://127.0.0.1:9/hook"}]}`), 0o644)
+	cfg, err = loadWebhooks(path)
+	if err != nil || cfg == nil || len(cfg.Rules) != 1 {
+		t.Errorf("expected one rule, got %v, %v", cfg, err)
+	}
+}
+
+func TestRunServeRejectsBadWebhooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	os.WriteFile(path, []byte(`{"rules": [{"url": "not a url"}]}`), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	code := runServe(openLoader(data.NewLoader(t.TempDir())), []string{"--webhooks", path}, &stdout, &stderr)
+	if code != exitUsage {
+		t.Errorf("expected exit %d, got %d", exitUsage, code)
+	}
+	if !strings.Contains(stderr.String(), "url must be") {
+		t.Errorf("expected url error, got %q", stderr.String())
+	}
+}
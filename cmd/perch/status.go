@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// Exit codes for headless subcommands.
+const (
+	exitOK        = 0 // Town is healthy
+	exitUnhealthy = 1 // Operational issues, doctor errors, or town status failed to load
+	exitUsage     = 2 // Bad flags or unknown rig
+)
+
+// statusReport is the machine-readable summary printed by `perch status`.
+type statusReport struct {
+	Town        string                  `json:"town"`
+	Location    string                  `json:"location,omitempty"`
+	LoadedAt    time.Time               `json:"loaded_at"`
+	Healthy     bool                    `json:"healthy"`
+	State       string                  `json:"state"`
+	Summary     *data.Summary           `json:"summary,omitempty"`
+	Issues      []string                `json:"issues,omitempty"`
+	Doctor      *doctorSummary          `json:"doctor,omitempty"`
+	MergeQueues map[string]queueSummary `json:"merge_queues"`
+	LoadErrors  []data.LoadError        `json:"load_errors,omitempty"`
+}
+
+// doctorSummary condenses a DoctorReport to counts plus failing checks.
+type doctorSummary struct {
+	Passed   int      `json:"passed"`
+	Warnings int      `json:"warnings"`
+	Errors   int      `json:"errors"`
+	Failing  []string `json:"failing,omitempty"` // Names of checks with error status
+}
+
+// queueSummary condenses a rig's merge queue.
+type queueSummary struct {
+	Total       int `json:"total"`
+	Conflicts   int `json:"conflicts"`
+	NeedsRebase int `json:"needs_rebase"`
+}
+
+// runStatus implements `perch status [--config FILE] [--json|--text] [--rig NAME]`.
+// It loads a single snapshot within refresh.load_timeout, prints a summary,
+// and returns the exit code.
+func runStatus(open opener, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", configFlagUsage)
+	asJSON := fs.Bool("json", false, "print status as JSON")
+	asText := fs.Bool("text", false, "print status as text (default)")
+	rig := fs.String("rig", "", "limit merge queue output to a single rig")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *asJSON && *asText {
+		fmt.Fprintln(stderr, "Error: --json and --text are mutually exclusive")
+		return exitUsage
+	}
+
+	cfg, loader, err := open(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.LoadTimeout)
+	defer cancel()
+
+	snap := loader.LoadAll(ctx)
+	report, err := buildStatusReport(snap, *rig)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(stderr, "Error: encoding status: %v\n", err)
+			return exitUnhealthy
+		}
+	} else {
+		writeStatusText(stdout, report)
+	}
+
+	if !report.Healthy {
+		return exitUnhealthy
+	}
+	return exitOK
+}
+
+// buildStatusReport summarizes a snapshot. When rig is set, merge queues
+// are limited to that rig and an unknown rig name is an error.
+func buildStatusReport(snap *data.Snapshot, rig string) (*statusReport, error) {
+	report := &statusReport{
+		LoadedAt:    snap.LoadedAt,
+		MergeQueues: make(map[string]queueSummary),
+		LoadErrors:  snap.LoadErrors,
+		Healthy:     true,
+	}
+
+	if snap.Town != nil {
+		report.Town = snap.Town.Name
+		report.Location = snap.Town.Location
+		summary := snap.Town.Summary
+		report.Summary = &summary
+	} else {
+		// Without town status we cannot vouch for anything else
+		report.Healthy = false
+	}
+
+	if rig != "" {
+		found := false
+		for _, name := range snap.RigNames() {
+			if name == rig {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown rig %q", rig)
+		}
+	}
+
+	report.State = "UNKNOWN"
+	if state := snap.OperationalState; state != nil {
+		report.State = state.Summary()
+		report.Issues = state.Issues
+		if state.HasIssues() {
+			report.Healthy = false
+		}
+	}
+
+	if doctor := snap.DoctorReport; doctor != nil {
+		report.Doctor = &doctorSummary{
+			Passed:   doctor.PassedCount,
+			Warnings: doctor.WarningCount,
+			Errors:   doctor.ErrorCount,
+		}
+		for _, check := range doctor.Errors() {
+			report.Doctor.Failing = append(report.Doctor.Failing, check.Name)
+		}
+		if doctor.ErrorCount > 0 || len(report.Doctor.Failing) > 0 {
+			report.Healthy = false
+		}
+	}
+
+	for name, mrs := range snap.MergeQueues {
+		if rig != "" && name != rig {
+			continue
+		}
+		q := queueSummary{Total: len(mrs)}
+		for _, mr := range mrs {
+			if mr.HasConflicts {
+				q.Conflicts++
+			}
+			if mr.NeedsRebase {
+				q.NeedsRebase++
+			}
+		}
+		report.MergeQueues[name] = q
+	}
+
+	return report, nil
+}
+
+// writeStatusText prints a human-readable status report.
+func writeStatusText(w io.Writer, r *statusReport) {
+	town := r.Town
+	if town == "" {
+		town = "(town status unavailable)"
+	}
+	fmt.Fprintf(w, "Town:   %s\n", town)
+	if r.Location != "" {
+		fmt.Fprintf(w, "Root:   %s\n", r.Location)
+	}
+	fmt.Fprintf(w, "State:  %s\n", r.State)
+	for _, issue := range r.Issues {
+		fmt.Fprintf(w, "  - %s\n", issue)
+	}
+
+	if s := r.Summary; s != nil {
+		fmt.Fprintf(w, "Rigs:   %d rigs, %d polecats, %d crews, %d witnesses, %d refineries, %d hooks active\n",
+			s.RigCount, s.PolecatCount, s.CrewCount, s.WitnessCount, s.RefineryCount, s.ActiveHooks)
+	}
+
+	if d := r.Doctor; d != nil {
+		fmt.Fprintf(w, "Doctor: %d passed, %d warnings, %d errors\n", d.Passed, d.Warnings, d.Errors)
+		if len(d.Failing) > 0 {
+			fmt.Fprintf(w, "  failing: %s\n", strings.Join(d.Failing, ", "))
+		}
+	}
+
+	if len(r.MergeQueues) > 0 {
+		fmt.Fprintln(w, "Merge queues:")
+		names := make([]string, 0, len(r.MergeQueues))
+		for name := range r.MergeQueues {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			q := r.MergeQueues[name]
+			fmt.Fprintf(w, "  %-16s %d queued, %d conflicts, %d need rebase\n", name, q.Total, q.Conflicts, q.NeedsRebase)
+		}
+	}
+
+	if len(r.LoadErrors) > 0 {
+		fmt.Fprintln(w, "Load errors:")
+		for _, e := range r.LoadErrors {
+			fmt.Fprintf(w, "  %s: %s\n", e.SourceLabel(), e.Error)
+		}
+	}
+}
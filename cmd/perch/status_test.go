@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/config"
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+// newStatusMock returns a MockRunner primed with fixture responses for LoadAll.
+func newStatusMock() *testutil.MockRunner {
+	fixtures := testutil.NewFixtures()
+	mock := testutil.NewMockRunner()
+	mock.On([]string{"gt", "status", "--json"}, fixtures.TownStatusJSON(), nil, nil)
+	mock.On([]string{"gt", "polecat", "list"}, fixtures.PolecatsJSON(), nil, nil)
+	mock.On([]string{"gt", "convoy", "list"}, fixtures.ConvoysJSON(), nil, nil)
+	mock.On([]string{"bd", "list", "--json", "--limit", "0"}, fixtures.IssuesJSON(), nil, nil)
+	mock.OnFunc([]string{"gt", "mq", "list"}, func(args []string) ([]byte, []byte, error) {
+		if len(args) >= 4 {
+			return fixtures.MergeQueueJSON(args[3]), nil, nil
+		}
+		return []byte("[]"), nil, nil
+	})
+	return mock
+}
+
+// openLoader opens loader with the default config, whatever --config says.
+func openLoader(loader *data.Loader) opener {
+	return func(string) (config.Config, *data.Loader, error) {
+		return config.Default(), loader, nil
+	}
+}
+
+func TestRunStatus(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		loader := data.NewLoaderWithRunner("/tmp/town", newStatusMock())
+		runStatus(openLoader(loader), []string{"--json"}, &stdout, &stderr)
+
+		var report statusReport
+		if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, stdout.String())
+		}
+		if report.Town != "test-town" {
+			t.Errorf("expected town 'test-town', got %q", report.Town)
+		}
+		if report.Summary == nil || report.Summary.RigCount != 2 {
+			t.Errorf("expected summary with 2 rigs, got %+v", report.Summary)
+		}
+		if q := report.MergeQueues["perch"]; q.Total != 2 {
+			t.Errorf("expected 2 MRs for perch, got %d", q.Total)
+		}
+		if q := report.MergeQueues["sidekick"]; q.Total != 1 {
+			t.Errorf("expected 1 MR for sidekick, got %d", q.Total)
+		}
+	})
+
+	t.Run("RigFilter", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		loader := data.NewLoaderWithRunner("/tmp/town", newStatusMock())
+		runStatus(openLoader(loader), []string{"--json", "--rig", "perch"}, &stdout, &stderr)
+
+		var report statusReport
+		if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+			t.Fatalf("invalid JSON output: %v", err)
+		}
+		if len(report.MergeQueues) != 1 {
+			t.Errorf("expected only perch merge queue, got %v", report.MergeQueues)
+		}
+	})
+
+	t.Run("UnknownRig", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		loader := data.NewLoaderWithRunner("/tmp/town", newStatusMock())
+		code := runStatus(openLoader(loader), []string{"--rig", "nope"}, &stdout, &stderr)
+		if code != exitUsage {
+			t.Errorf("expected exit %d, got %d", exitUsage, code)
+		}
+		if !strings.Contains(stderr.String(), "unknown rig") {
+			t.Errorf("expected unknown rig error, got %q", stderr.String())
+		}
+	})
+
+	t.Run("ConflictingFormats", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		loader := data.NewLoaderWithRunner("/tmp/town", newStatusMock())
+		if code := runStatus(openLoader(loader), []string{"--json", "--text"}, &stdout, &stderr); code != exitUsage {
+			t.Errorf("expected exit %d, got %d", exitUsage, code)
+		}
+	})
+
+	t.Run("TownStatusFailure", func(t *testing.T) {
+		mock := testutil.NewMockRunner()
+		mock.On([]string{"gt", "status"}, nil, []byte("connection refused"), errors.New("exit status 1"))
+
+		var stdout, stderr bytes.Buffer
+		loader := data.NewLoaderWithRunner("/tmp/town", mock)
+		code := runStatus(openLoader(loader), []string{"--text"}, &stdout, &stderr)
+		if code != exitUnhealthy {
+			t.Errorf("expected exit %d, got %d", exitUnhealthy, code)
+		}
+		out := stdout.String()
+		if !strings.Contains(out, "town status unavailable") {
+			t.Errorf("expected unavailable town in text output, got:\n%s", out)
+		}
+		if !strings.Contains(out, "Load errors:") {
+			t.Errorf("expected load errors in text output, got:\n%s", out)
+		}
+	})
+}
+
+func TestBuildStatusReport(t *testing.T) {
+	healthyState := &data.OperationalState{WatchdogHealthy: true}
+
+	t.Run("Healthy", func(t *testing.T) {
+		snap := &data.Snapshot{
+			Town:             &data.TownStatus{Name: "town"},
+			OperationalState: healthyState,
+			LoadedAt:         time.Now(),
+		}
+		report, err := buildStatusReport(snap, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !report.Healthy {
+			t.Errorf("expected healthy report, state=%s issues=%v", report.State, report.Issues)
+		}
+	})
+
+	t.Run("OperationalIssues", func(t *testing.T) {
+		snap := &data.Snapshot{
+			Town:             &data.TownStatus{Name: "town"},
+			OperationalState: &data.OperationalState{PatrolMuted: true, WatchdogHealthy: true},
+		}
+		report, _ := buildStatusReport(snap, "")
+		if report.Healthy {
+			t.Error("expected muted patrol to be unhealthy")
+		}
+	})
+
+	t.Run("DoctorErrors", func(t *testing.T) {
+		snap := &data.Snapshot{
+			Town:             &data.TownStatus{Name: "town"},
+			OperationalState: healthyState,
+			DoctorReport: &data.DoctorReport{
+				Checks:     []data.DoctorCheck{{Name: "routes", Status: data.CheckError}},
+				ErrorCount: 1,
+			},
+		}
+		report, _ := buildStatusReport(snap, "")
+		if report.Healthy {
+			t.Error("expected doctor errors to be unhealthy")
+		}
+		if len(report.Doctor.Failing) != 1 || report.Doctor.Failing[0] != "routes" {
+			t.Errorf("expected failing check 'routes', got %v", report.Doctor.Failing)
+		}
+	})
+
+	t.Run("MergeQueueCounts", func(t *testing.T) {
+		snap := &data.Snapshot{
+			Town:             &data.TownStatus{Name: "town"},
+			OperationalState: healthyState,
+			MergeQueues: map[string][]data.MergeRequest{
+				"perch": {{ID: "mr-1", HasConflicts: true}, {ID: "mr-2", NeedsRebase: true}, {ID: "mr-3"}},
+			},
+		}
+		report, _ := buildStatusReport(snap, "")
+		q := report.MergeQueues["perch"]
+		if q.Total != 3 || q.Conflicts != 1 || q.NeedsRebase != 1 {
+			t.Errorf("unexpected queue summary: %+v", q)
+		}
+	})
+}
+
+func TestRunStatusConfigFlag(t *testing.T) {
+	var path string
+	open := func(p string) (config.Config, *data.Loader, error) {
+		path = p
+		return config.Config{}, nil, errors.New("refresh.interval: want a duration")
+	}
+	var stdout, stderr bytes.Buffer
+	if code := runStatus(open, []string{"--config", "/etc/perch.toml"}, &stdout, &stderr); code != exitUsage {
+		t.Errorf("expected exit %d for a bad config, got %d", exitUsage, code)
+	}
+	if path != "/etc/perch.toml" || !strings.Contains(stderr.String(), "refresh.interval") {
+		t.Errorf("expected the config error for /etc/perch.toml, got %q: %q", path, stderr.String())
+	}
+}
@@ -0,0 +1,181 @@
+package data
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Breaker defaults.
+const (
+	DefaultBreakerThreshold  = 3                // Consecutive failures that open a breaker
+	DefaultBreakerBackoff    = 30 * time.Second // First wait before probing an open breaker
+	DefaultBreakerMaxBackoff = 10 * time.Minute // Longest wait between probes
+)
+
+// BreakerState is where a source's circuit breaker stands.
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"    // Loading normally
+	BreakerOpen     BreakerState = "open"      // Failing; not loaded until RetryAt
+	BreakerHalfOpen BreakerState = "half-open" // Probing with one load
+)
+
+// BreakerStatus is a breaker's state as attached to a LoadError.
+type BreakerStatus struct {
+	Key      string       `json:"key"`      // Source, or "merge_queue_<rig>" for one rig's queue
+	State    BreakerState `json:"state"`    // State after this failure
+	Failures int          `json:"failures"` // Consecutive failures so far
+	RetryAt  time.Time    `json:"retry_at"` // When an open breaker probes again
+}
+
+// Breakers holds a circuit breaker per source, so a broken command isn't
+// run on every refresh. A breaker opens after Threshold consecutive
+// failures and lets one probe through after a backoff that doubles with
+// each failed probe, up to MaxBackoff. A successful load closes it. It is
+// safe for concurrent use.
+type Breakers struct {
+	Threshold  int           // Zero uses DefaultBreakerThreshold
+	Backoff    time.Duration // Zero uses DefaultBreakerBackoff
+	MaxBackoff time.Duration // Zero uses DefaultBreakerMaxBackoff
+
+	mu    sync.Mutex
+	byKey map[string]*breaker
+}
+
+// breaker is one source's failure count and backoff.
+type breaker struct {
+	state    BreakerState
+	failures int
+	backoff  time.Duration
+	retryAt  time.Time // When an open breaker probes, or a half-open one probes again
+	last     LoadError // Most recent failure, reported while open
+}
+
+// NewBreakers creates breakers with the default threshold and backoff.
+func NewBreakers() *Breakers {
+	return &Breakers{}
+}
+
+// allow reports whether key may load at now. An open breaker past its
+// retry time goes half-open and allows the one probe. A probe that never
+// settles, as when its refresh is cancelled, is replaced by a new one
+// after another backoff. Otherwise it returns the last failure, marked
+// with the breaker's status, to report in place of a load. A nil Breakers
+// allows everything.
+func (b *Breakers) allow(key string, now time.Time) (LoadError, bool) {
+	if b == nil {
+		return LoadError{}, true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	br := b.byKey[key]
+	switch {
+	case br == nil || br.state == BreakerClosed:
+		return LoadError{}, true
+	case !now.Before(br.retryAt):
+		br.state = BreakerHalfOpen
+		br.retryAt = now.Add(br.backoff)
+		return LoadError{}, true
+	}
+	return br.last, false
+}
+
+// succeeded closes key's breaker.
+func (b *Breakers) succeeded(key string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.byKey, key)
+}
+
+// failed counts a failed load of key at now and returns loadErr marked
+// with the breaker's new status.
+func (b *Breakers) failed(key string, loadErr LoadError, now time.Time) LoadError {
+	if b == nil {
+		return loadErr
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.byKey == nil {
+		b.byKey = make(map[string]*breaker)
+	}
+	br, ok := b.byKey[key]
+	if !ok {
+		br = &breaker{state: BreakerClosed}
+		b.byKey[key] = br
+	}
+	br.failures++
+	switch {
+	case br.state == BreakerHalfOpen:
+		br.backoff = min(2*br.backoff, b.maxBackoff())
+		br.state = BreakerOpen
+		br.retryAt = now.Add(br.backoff)
+	case br.failures >= b.threshold():
+		br.backoff = min(b.firstBackoff(), b.maxBackoff())
+		br.state = BreakerOpen
+		br.retryAt = now.Add(br.backoff)
+	}
+	loadErr.Breaker = &BreakerStatus{Key: key, State: br.state, Failures: br.failures}
+	if br.state == BreakerOpen {
+		loadErr.Breaker.RetryAt = br.retryAt
+	}
+	br.last = loadErr
+	return loadErr
+}
+
+// Reset closes key's breaker so its next load runs, as for a manual retry.
+func (b *Breakers) Reset(key string) {
+	b.succeeded(key)
+}
+
+// Status returns key's breaker status. Keys without failures are closed.
+func (b *Breakers) Status(key string) BreakerStatus {
+	status := BreakerStatus{Key: key, State: BreakerClosed}
+	if b == nil {
+		return status
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if br := b.byKey[key]; br != nil {
+		status.State = br.state
+		status.Failures = br.failures
+		if br.state != BreakerClosed {
+			status.RetryAt = br.retryAt
+		}
+	}
+	return status
+}
+
+func (b *Breakers) threshold() int {
+	if b.Threshold <= 0 {
+		return DefaultBreakerThreshold
+	}
+	return b.Threshold
+}
+
+func (b *Breakers) firstBackoff() time.Duration {
+	if b.Backoff <= 0 {
+		return DefaultBreakerBackoff
+	}
+	return b.Backoff
+}
+
+func (b *Breakers) maxBackoff() time.Duration {
+	if b.MaxBackoff <= 0 {
+		return DefaultBreakerMaxBackoff
+	}
+	return b.MaxBackoff
+}
+
+// BreakerSource returns the source a breaker key loads: the key itself,
+// or SourceMergeQueue for a rig's queue.
+func BreakerSource(key string) string {
+	if strings.HasPrefix(key, SourceMergeQueue+"_") {
+		return SourceMergeQueue
+	}
+	return key
+}
@@ -0,0 +1,155 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+func TestBreakerOpensAndBacksOff(t *testing.T) {
+	b := &Breakers{Threshold: 2, Backoff: time.Minute, MaxBackoff: 3 * time.Minute}
+	start := time.Date(2026, 1, 2, 7, 0, 0, 0, time.UTC)
+	fail := LoadError{Source: SourceIssues, Error: "database locked"}
+
+	if e := b.failed(SourceIssues, fail, start); e.Breaker.State != BreakerClosed {
+		t.Errorf("expected one failure to leave the breaker closed, got %+v", e.Breaker)
+	}
+	e := b.failed(SourceIssues, fail, start)
+	if e.Breaker.State != BreakerOpen || !e.Breaker.RetryAt.Equal(start.Add(time.Minute)) {
+		t.Fatalf("expected the breaker open for a minute, got %+v", e.Breaker)
+	}
+	if held, ok := b.allow(SourceIssues, start.Add(30*time.Second)); ok || held.Error != "database locked" {
+		t.Errorf("expected loads held with the last failure, got %+v %v", held, ok)
+	}
+
+	// Failed probes double the backoff up to the maximum
+	at := start.Add(time.Minute)
+	for _, want := range []time.Duration{2 * time.Minute, 3 * time.Minute, 3 * time.Minute} {
+		if _, ok := b.allow(SourceIssues, at); !ok {
+			t.Fatalf("expected a probe at %s", at.Sub(start))
+		}
+		if _, ok := b.allow(SourceIssues, at); ok {
+			t.Error("expected only one probe while half-open")
+		}
+		e := b.failed(SourceIssues, fail, at)
+		if got := e.Breaker.RetryAt.Sub(at); got != want {
+			t.Errorf("expected a %s backoff, got %s", want, got)
+		}
+		at = e.Breaker.RetryAt
+	}
+
+	b.succeeded(SourceIssues)
+	if status := b.Status(SourceIssues); status.State != BreakerClosed || status.Failures != 0 {
+		t.Errorf("expected a success to close the breaker, got %+v", status)
+	}
+}
+
+func TestBreakerReprobesAfterCancelledProbe(t *testing.T) {
+	fixtures := testutil.NewFixtures()
+	mock := testutil.NewMockRunner()
+	mock.On([]string{"bd", "list", "--json", "--limit", "0"}, nil, []byte("database locked"), errors.New("exit status 1"))
+
+	loader := NewLoaderWithRunner("/tmp/town", mock)
+	loader.FS = testutil.NewMemFS()
+	loader.Breakers = &Breakers{Threshold: 1, Backoff: 10 * time.Millisecond}
+	snap := loader.LoadSources(t.Context(), nil, SourceIssues)
+	if status := loader.Breakers.Status(SourceIssues); status.State != BreakerOpen {
+		t.Fatalf("expected the breaker open, got %+v", status)
+	}
+
+	// The probe's refresh is cancelled, so it neither fails nor succeeds
+	time.Sleep(20 * time.Millisecond)
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	snap = loader.LoadSources(ctx, snap, SourceIssues)
+	if status := loader.Breakers.Status(SourceIssues); status.State != BreakerHalfOpen {
+		t.Fatalf("expected the cancelled probe to leave the breaker half-open, got %+v", status)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	mock.Reset()
+	mock.On([]string{"bd", "list", "--json", "--limit", "0"}, fixtures.IssuesJSON(), nil, nil)
+	snap = loader.LoadSources(t.Context(), snap, SourceIssues)
+	if !snap.SourceLoaded(SourceIssues) {
+		t.Errorf("expected a new probe to load issues, got %+v", snap.LoadErrors)
+	}
+	if status := loader.Breakers.Status(SourceIssues); status.State != BreakerClosed {
+		t.Errorf("expected the probe to close the breaker, got %+v", status)
+	}
+}
+
+func TestLoadSourcesHoldsOpenSources(t *testing.T) {
+	fixtures := testutil.NewFixtures()
+	mock := testutil.NewMockRunner()
+	mock.On([]string{"gt", "status", "--json"}, fixtures.TownStatusJSON(), nil, nil)
+	mock.On([]string{"bd", "list", "--json", "--limit", "0"}, fixtures.IssuesJSON(), nil, nil)
+	mock.On([]string{"gt", "mq", "list"}, []byte("[]"), nil, nil)
+
+	loader := NewLoaderWithRunner("/tmp/town", mock)
+	loader.FS = testutil.NewMemFS()
+	loader.Breakers = &Breakers{Threshold: 2, Backoff: time.Hour}
+	store := NewStoreWithLoader(loader)
+	store.Refresh(t.Context())
+
+	mock.Reset()
+	mock.On([]string{"bd", "list", "--json", "--limit", "0"}, nil, []byte("database locked"), errors.New("exit status 1"))
+	for i := 0; i < 2; i++ {
+		store.RefreshSources(t.Context(), SourceIssues)
+	}
+	if n := mock.CallCount([]string{"bd", "list", "--json", "--limit", "0"}); n != 2 {
+		t.Fatalf("expected two failed loads, got %d", n)
+	}
+
+	snap := store.RefreshSources(t.Context(), SourceIssues)
+	if n := mock.CallCount([]string{"bd", "list", "--json", "--limit", "0"}); n != 2 {
+		t.Errorf("expected the open breaker to skip bd, got %d calls", n)
+	}
+	if len(snap.LoadErrors) != 1 || snap.LoadErrors[0].Breaker == nil || snap.LoadErrors[0].Breaker.State != BreakerOpen {
+		t.Fatalf("expected one error with the open breaker, got %+v", snap.LoadErrors)
+	}
+	if action := snap.LoadErrors[0].SuggestedAction(); !strings.HasPrefix(action, "Paused after 2 failures") {
+		t.Errorf("expected the suggested action to mention the pause, got %q", action)
+	}
+	if _, ok := snap.StaleSince(SourceIssues); !ok || len(snap.Issues) != 3 {
+		t.Error("expected the last good issues kept")
+	}
+
+	// Retrying now closes the breaker and loads again
+	mock.Reset()
+	mock.On([]string{"bd", "list", "--json", "--limit", "0"}, fixtures.OpenIssuesJSON(), nil, nil)
+	snap = store.Retry(t.Context(), SourceIssues)
+	if !snap.SourceLoaded(SourceIssues) || len(snap.LoadErrors) != 0 {
+		t.Errorf("expected the retry to load issues, got %+v", snap.LoadErrors)
+	}
+}
+
+func TestBreakersTrackRigQueues(t *testing.T) {
+	fixtures := testutil.NewFixtures()
+	mock := testutil.NewMockRunner()
+	mock.On([]string{"gt", "status", "--json"}, fixtures.TownStatusJSON(), nil, nil)
+	mock.On([]string{"gt", "mq", "list", "perch"}, nil, nil, errors.New("exit status 1"))
+	mock.On([]string{"gt", "mq", "list", "sidekick"}, fixtures.MergeQueueJSON("sidekick"), nil, nil)
+
+	loader := NewLoaderWithRunner("/tmp/town", mock)
+	loader.FS = testutil.NewMemFS()
+	loader.Breakers = &Breakers{Threshold: 1, Backoff: time.Hour}
+	snap := loader.LoadAll(t.Context())
+	snap = loader.LoadSources(t.Context(), snap, SourceMergeQueue)
+
+	if n := mock.CallCount([]string{"gt", "mq", "list", "perch"}); n != 1 {
+		t.Errorf("expected the perch queue held after failing, got %d calls", n)
+	}
+	if n := mock.CallCount([]string{"gt", "mq", "list", "sidekick"}); n != 2 {
+		t.Errorf("expected the sidekick queue still loading, got %d calls", n)
+	}
+	if status := loader.Breakers.Status(SourceMergeQueue + "_perch"); status.State != BreakerOpen {
+		t.Errorf("expected the perch queue's breaker open, got %+v", status)
+	}
+	if len(snap.MergeQueues["sidekick"]) != 1 {
+		t.Errorf("expected sidekick's queue loaded, got %v", snap.MergeQueues)
+	}
+}
@@ -0,0 +1,278 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ChangeType identifies a kind of change between two snapshots.
+type ChangeType string
+
+const (
+	ChangeAgentStarted      ChangeType = "agent_started"
+	ChangeAgentStopped      ChangeType = "agent_stopped"
+	ChangeBeadCreated       ChangeType = "bead_created"
+	ChangeBeadStatus        ChangeType = "bead_status"
+	ChangeMREntered         ChangeType = "mr_entered"
+	ChangeMRLeft            ChangeType = "mr_left"
+	ChangeMRConflict        ChangeType = "mr_conflict"
+	ChangeConvoyProgress    ChangeType = "convoy_progress"
+	ChangeMailArrived       ChangeType = "mail_arrived"
+	ChangeLoadErrorAppeared ChangeType = "load_error_appeared"
+	ChangeLoadErrorCleared  ChangeType = "load_error_cleared"
+)
+
+// ChangeEvent is a single change detected between two snapshots.
+type ChangeEvent struct {
+	Type ChangeType `json:"type"`
+
+	// At is when the change was first seen. It is the source's own timestamp
+	// when that falls between the two snapshots, otherwise next.LoadedAt.
+	At time.Time `json:"at"`
+
+	Source  string `json:"source"`         // Agent address, rig, or load error source
+	ID      string `json:"id"`             // Related ID (agent, bead, MR, convoy, mail)
+	Summary string `json:"summary"`        // Brief description
+	From    string `json:"from,omitempty"` // Previous value for transitions
+	To      string `json:"to,omitempty"`   // New value for transitions
+}
+
+// DiffSnapshots returns the changes from prev to next, oldest first.
+// A nil prev is a baseline and produces no events. Sections whose source
+// failed to load in either snapshot are skipped so a failed load does not
+// look like everything disappeared.
+func DiffSnapshots(prev, next *Snapshot) []ChangeEvent {
+	if prev == nil || next == nil {
+		return nil
+	}
+
+	d := &snapshotDiff{prev: prev, next: next}
+	d.agents()
+	d.beads()
+	d.mergeQueues()
+	d.convoys()
+	d.mail()
+	d.loadErrors()
+
+	sort.Slice(d.events, func(i, j int) bool {
+		a, b := d.events[i], d.events[j]
+		if !a.At.Equal(b.At) {
+			return a.At.Before(b.At)
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.ID < b.ID
+	})
+	return d.events
+}
+
+// snapshotDiff accumulates events while comparing two snapshots.
+type snapshotDiff struct {
+	prev, next *Snapshot
+	events     []ChangeEvent
+}
+
+func (d *snapshotDiff) add(e ChangeEvent) {
+	if e.At.IsZero() {
+		e.At = d.next.LoadedAt
+	}
+	d.events = append(d.events, e)
+}
+
+// firstSeen returns t if it falls within the refresh window, else next.LoadedAt.
+func (d *snapshotDiff) firstSeen(t time.Time) time.Time {
+	if t.IsZero() || !t.After(d.prev.LoadedAt) || t.After(d.next.LoadedAt) {
+		return d.next.LoadedAt
+	}
+	return t
+}
+
+// loaded reports whether source loaded successfully in both snapshots.
+func (d *snapshotDiff) loaded(source string) bool {
+	return d.prev.SourceLoaded(source) && d.next.SourceLoaded(source)
+}
+
+// snapshotAgents collects town-level and rig agents by address.
+func snapshotAgents(snap *Snapshot) map[string]Agent {
+	agents := make(map[string]Agent)
+	if snap.Town == nil {
+		return agents
+	}
+	for _, a := range snap.Town.Agents {
+		agents[a.Address] = a
+	}
+	for _, rig := range snap.Town.Rigs {
+		for _, a := range rig.Agents {
+			agents[a.Address] = a
+		}
+	}
+	return agents
+}
+
+// agents reports agents that started or stopped. A running agent that is
+// no longer listed at all, such as a removed polecat, counts as stopped.
+func (d *snapshotDiff) agents() {
+	if !d.loaded(SourceTownStatus) || d.prev.Town == nil || d.next.Town == nil {
+		return
+	}
+	before := snapshotAgents(d.prev)
+	after := snapshotAgents(d.next)
+	for addr, a := range after {
+		old, existed := before[addr]
+		switch {
+		case a.Running && (!existed || !old.Running):
+			d.add(ChangeEvent{Type: ChangeAgentStarted, Source: addr, ID: addr, Summary: fmt.Sprintf("%s started", addr)})
+		case !a.Running && existed && old.Running:
+			d.add(ChangeEvent{Type: ChangeAgentStopped, Source: addr, ID: addr, Summary: fmt.Sprintf("%s stopped", addr)})
+		}
+	}
+	for addr, old := range before {
+		if _, listed := after[addr]; !listed && old.Running {
+			d.add(ChangeEvent{Type: ChangeAgentStopped, Source: addr, ID: addr, Summary: fmt.Sprintf("%s stopped and is no longer listed", addr)})
+		}
+	}
+}
+
+func (d *snapshotDiff) beads() {
+	if !d.loaded(SourceIssues) || d.prev.Issues == nil {
+		return
+	}
+	before := make(map[string]Issue, len(d.prev.Issues))
+	for _, issue := range d.prev.Issues {
+		before[issue.ID] = issue
+	}
+	for _, issue := range d.next.Issues {
+		old, existed := before[issue.ID]
+		if !existed {
+			d.add(ChangeEvent{
+				Type:    ChangeBeadCreated,
+				At:      d.firstSeen(issue.CreatedAt),
+				Source:  issue.Assignee,
+				ID:      issue.ID,
+				Summary: issue.Title,
+				To:      issue.Status,
+			})
+			continue
+		}
+		if old.Status != issue.Status {
+			d.add(ChangeEvent{
+				Type:    ChangeBeadStatus,
+				At:      d.firstSeen(issue.UpdatedAt),
+				Source:  issue.Assignee,
+				ID:      issue.ID,
+				Summary: issue.Title,
+				From:    old.Status,
+				To:      issue.Status,
+			})
+		}
+	}
+}
+
+func (d *snapshotDiff) mergeQueues() {
+	for rig, mrs := range d.next.MergeQueues {
+		if !d.loaded(SourceMergeQueue + "_" + rig) {
+			continue
+		}
+		oldMRs, tracked := d.prev.MergeQueues[rig]
+		if !tracked {
+			continue
+		}
+		before := make(map[string]MergeRequest, len(oldMRs))
+		for _, mr := range oldMRs {
+			before[mr.ID] = mr
+		}
+		current := make(map[string]bool, len(mrs))
+		for _, mr := range mrs {
+			current[mr.ID] = true
+			old, existed := before[mr.ID]
+			if !existed {
+				d.add(ChangeEvent{Type: ChangeMREntered, Source: rig, ID: mr.ID, Summary: mr.Title, To: mr.Status})
+			}
+			if (mr.HasConflicts || mr.NeedsRebase) && !(old.HasConflicts || old.NeedsRebase) {
+				d.add(ChangeEvent{Type: ChangeMRConflict, Source: rig, ID: mr.ID, Summary: mr.Title, To: mr.ConflictInfo})
+			}
+		}
+		for _, mr := range oldMRs {
+			if !current[mr.ID] {
+				d.add(ChangeEvent{Type: ChangeMRLeft, Source: rig, ID: mr.ID, Summary: mr.Title, From: mr.Status})
+			}
+		}
+	}
+}
+
+func (d *snapshotDiff) convoys() {
+	if !d.loaded(SourceConvoys) {
+		return
+	}
+	before := make(map[string]Convoy, len(d.prev.Convoys))
+	for _, c := range d.prev.Convoys {
+		before[c.ID] = c
+	}
+	for _, c := range d.next.Convoys {
+		old, existed := before[c.ID]
+		if !existed || old.Completed == c.Completed && old.Total == c.Total {
+			continue
+		}
+		d.add(ChangeEvent{
+			Type:    ChangeConvoyProgress,
+			ID:      c.ID,
+			Summary: c.Title,
+			From:    fmt.Sprintf("%d/%d", old.Completed, old.Total),
+			To:      fmt.Sprintf("%d/%d", c.Completed, c.Total),
+		})
+	}
+}
+
+func (d *snapshotDiff) mail() {
+	if !d.loaded(SourceMail) || d.prev.Mail == nil {
+		return
+	}
+	seen := make(map[string]bool, len(d.prev.Mail))
+	for _, m := range d.prev.Mail {
+		seen[m.ID] = true
+	}
+	for _, m := range d.next.Mail {
+		if seen[m.ID] {
+			continue
+		}
+		d.add(ChangeEvent{
+			Type:    ChangeMailArrived,
+			At:      d.firstSeen(m.Timestamp),
+			Source:  m.From,
+			ID:      m.ID,
+			Summary: m.Subject,
+		})
+	}
+}
+
+func (d *snapshotDiff) loadErrors() {
+	key := func(e LoadError) string { return e.Source + "\x00" + e.Command }
+	before := make(map[string]LoadError, len(d.prev.LoadErrors))
+	for _, e := range d.prev.LoadErrors {
+		before[key(e)] = e
+	}
+	after := make(map[string]bool, len(d.next.LoadErrors))
+	for _, e := range d.next.LoadErrors {
+		after[key(e)] = true
+		if _, existed := before[key(e)]; existed {
+			continue
+		}
+		d.add(ChangeEvent{
+			Type:    ChangeLoadErrorAppeared,
+			At:      d.firstSeen(e.OccurredAt),
+			Source:  e.Source,
+			ID:      e.Command,
+			Summary: e.Error,
+		})
+	}
+	for k, e := range before {
+		if !after[k] {
+			d.add(ChangeEvent{Type: ChangeLoadErrorCleared, Source: e.Source, ID: e.Command, Summary: e.Error})
+		}
+	}
+}
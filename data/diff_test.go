@@ -0,0 +1,187 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func diffTestSnapshots() (*Snapshot, *Snapshot) {
+	t0 := time.Date(2026, 1, 8, 12, 0, 0, 0, time.UTC)
+	t1 := t0.Add(5 * time.Second)
+
+	prev := &Snapshot{
+		LoadedAt: t0,
+		Town: &TownStatus{
+			Agents: []Agent{{Address: "mayor/", Running: true}},
+			Rigs: []Rig{{Name: "perch", Agents: []Agent{
+				{Address: "perch/witness", Running: true},
+				{Address: "perch/polecats/able", Running: false},
+			}}},
+		},
+		Issues: []Issue{
+			{ID: "gt-001", Title: "Auth", Status: "open"},
+			{ID: "gt-002", Title: "Login", Status: "in_progress"},
+		},
+		MergeQueues: map[string][]MergeRequest{
+			"perch": {{ID: "mr-001", Title: "Add auth"}, {ID: "mr-002", Title: "Fix bug"}},
+		},
+		Convoys: []Convoy{{ID: "convoy-001", Title: "Feature: Auth", Completed: 1, Total: 3}},
+		Mail:    []MailMessage{{ID: "m-1", Subject: "hello"}},
+		LoadErrors: []LoadError{
+			{Source: "doctor", Command: "gt doctor", Error: "timeout"},
+		},
+	}
+
+	next := &Snapshot{
+		LoadedAt: t1,
+		Town: &TownStatus{
+			Agents: []Agent{{Address: "mayor/", Running: true}},
+			Rigs: []Rig{{Name: "perch", Agents: []Agent{
+				{Address: "perch/witness", Running: false},
+				{Address: "perch/polecats/able", Running: true},
+			}}},
+		},
+		Issues: []Issue{
+			{ID: "gt-001", Title: "Auth", Status: "closed", UpdatedAt: t0.Add(2 * time.Second)},
+			{ID: "gt-002", Title: "Login", Status: "in_progress"},
+			{ID: "gt-003", Title: "Docs", Status: "open", CreatedAt: t0.Add(-time.Hour)},
+		},
+		MergeQueues: map[string][]MergeRequest{
+			"perch": {{ID: "mr-002", Title: "Fix bug", HasConflicts: true}, {ID: "mr-003", Title: "Docs"}},
+		},
+		Convoys: []Convoy{{ID: "convoy-001", Title: "Feature: Auth", Completed: 2, Total: 3}},
+		Mail: []MailMessage{
+			{ID: "m-1", Subject: "hello"},
+			{ID: "m-2", From: "mayor/", Subject: "HELP", Timestamp: t0.Add(3 * time.Second)},
+		},
+		LoadErrors: []LoadError{
+			{Source: "mail", Command: "gt mail inbox", Error: "boom", OccurredAt: t1},
+		},
+	}
+	return prev, next
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	prev, next := diffTestSnapshots()
+	events := DiffSnapshots(prev, next)
+
+	find := func(typ ChangeType, id string) *ChangeEvent {
+		for i := range events {
+			if events[i].Type == typ && events[i].ID == id {
+				return &events[i]
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name string
+		typ  ChangeType
+		id   string
+	}{
+		{"agent started", ChangeAgentStarted, "perch/polecats/able"},
+		{"agent stopped", ChangeAgentStopped, "perch/witness"},
+		{"bead status", ChangeBeadStatus, "gt-001"},
+		{"bead created", ChangeBeadCreated, "gt-003"},
+		{"mr entered", ChangeMREntered, "mr-003"},
+		{"mr left", ChangeMRLeft, "mr-001"},
+		{"mr conflict", ChangeMRConflict, "mr-002"},
+		{"convoy progress", ChangeConvoyProgress, "convoy-001"},
+		{"mail arrived", ChangeMailArrived, "m-2"},
+		{"load error appeared", ChangeLoadErrorAppeared, "gt mail inbox"},
+		{"load error cleared", ChangeLoadErrorCleared, "gt doctor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if find(tt.typ, tt.id) == nil {
+				t.Errorf("expected %s event for %s, got %+v", tt.typ, tt.id, events)
+			}
+		})
+	}
+
+	if len(events) != len(tests) {
+		t.Errorf("expected %d events, got %d: %+v", len(tests), len(events), events)
+	}
+
+	t.Run("transitions", func(t *testing.T) {
+		e := find(ChangeBeadStatus, "gt-001")
+		if e.From != "open" || e.To != "closed" {
+			t.Errorf("expected open → closed, got %s → %s", e.From, e.To)
+		}
+		c := find(ChangeConvoyProgress, "convoy-001")
+		if c.From != "1/3" || c.To != "2/3" {
+			t.Errorf("expected 1/3 → 2/3, got %s → %s", c.From, c.To)
+		}
+	})
+
+	t.Run("first seen timestamps", func(t *testing.T) {
+		// Source timestamps inside the refresh window are kept
+		if e := find(ChangeBeadStatus, "gt-001"); !e.At.Equal(prev.LoadedAt.Add(2 * time.Second)) {
+			t.Errorf("expected bead UpdatedAt as first seen, got %v", e.At)
+		}
+		if e := find(ChangeMailArrived, "m-2"); !e.At.Equal(prev.LoadedAt.Add(3 * time.Second)) {
+			t.Errorf("expected mail timestamp as first seen, got %v", e.At)
+		}
+		// Timestamps outside the window fall back to when the change was observed
+		if e := find(ChangeBeadCreated, "gt-003"); !e.At.Equal(next.LoadedAt) {
+			t.Errorf("expected next.LoadedAt for old CreatedAt, got %v", e.At)
+		}
+		if e := find(ChangeMREntered, "mr-003"); !e.At.Equal(next.LoadedAt) {
+			t.Errorf("expected next.LoadedAt for MR without timestamp, got %v", e.At)
+		}
+	})
+
+	t.Run("ordered oldest first", func(t *testing.T) {
+		for i := 1; i < len(events); i++ {
+			if events[i].At.Before(events[i-1].At) {
+				t.Fatalf("events not ordered at %d: %v before %v", i, events[i].At, events[i-1].At)
+			}
+		}
+	})
+}
+
+func TestDiffSnapshotsBaseline(t *testing.T) {
+	_, next := diffTestSnapshots()
+	if events := DiffSnapshots(nil, next); len(events) != 0 {
+		t.Errorf("expected no events for baseline, got %d", len(events))
+	}
+}
+
+func TestDiffSnapshotsSkipsFailedSources(t *testing.T) {
+	prev, next := diffTestSnapshots()
+	prev.LastSuccess = map[string]time.Time{"issues": prev.LoadedAt, "merge_queue_perch": prev.LoadedAt}
+	// Merge queue and issues failed to load in next
+	next.LastSuccess = map[string]time.Time{}
+	next.MergeQueues = map[string][]MergeRequest{"perch": nil}
+	next.Issues = nil
+
+	for _, e := range DiffSnapshots(prev, next) {
+		switch e.Type {
+		case ChangeMRLeft, ChangeMREntered, ChangeMRConflict, ChangeBeadStatus, ChangeBeadCreated:
+			t.Errorf("unexpected %s event for a source that failed to load", e.Type)
+		}
+	}
+}
+
+func TestDiffSnapshotsAgentVanished(t *testing.T) {
+	prev, next := diffTestSnapshots()
+	prev.Town.Rigs[0].Agents = append(prev.Town.Rigs[0].Agents, Agent{Address: "perch/polecats/nux", Running: true})
+
+	var stopped []string
+	for _, e := range DiffSnapshots(prev, next) {
+		if e.Type == ChangeAgentStopped {
+			stopped = append(stopped, e.ID)
+		}
+	}
+	if len(stopped) != 2 || stopped[0] != "perch/polecats/nux" || stopped[1] != "perch/witness" {
+		t.Errorf("expected the removed polecat and the witness to stop, got %v", stopped)
+	}
+
+	// A town status that failed to load says nothing about agents
+	next.LastSuccess = map[string]time.Time{}
+	for _, e := range DiffSnapshots(prev, next) {
+		if e.Type == ChangeAgentStopped || e.Type == ChangeAgentStarted {
+			t.Errorf("unexpected %s event for %s", e.Type, e.ID)
+		}
+	}
+}
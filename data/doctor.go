@@ -0,0 +1,122 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// errDoctorNotJSON reports gt doctor output that isn't JSON, from gt
+// versions without --json.
+var errDoctorNotJSON = errors.New("gt doctor output is not JSON")
+
+// doctorJSON is the output of gt doctor --json.
+type doctorJSON struct {
+	Checks []struct {
+		Name       string   `json:"name"`
+		Status     string   `json:"status"`
+		Message    string   `json:"message"`
+		Details    []string `json:"details"`
+		Fix        string   `json:"fix"`
+		FixCommand string   `json:"fix_command"`
+		DurationMS int64    `json:"duration_ms"`
+	} `json:"checks"`
+	Summary *struct {
+		Total    int `json:"total"`
+		Passed   int `json:"passed"`
+		Warnings int `json:"warnings"`
+		Errors   int `json:"errors"`
+	} `json:"summary"`
+}
+
+// parseDoctorJSON parses the output of gt doctor --json. Without a
+// summary the counts come from the checks.
+func parseDoctorJSON(output []byte) (*DoctorReport, error) {
+	output = bytes.TrimSpace(output)
+	if !bytes.HasPrefix(output, []byte("{")) {
+		return nil, errDoctorNotJSON
+	}
+	var raw doctorJSON
+	if err := json.Unmarshal(output, &raw); err != nil {
+		return nil, err
+	}
+
+	report := &DoctorReport{LoadedAt: time.Now()}
+	for _, c := range raw.Checks {
+		check := DoctorCheck{
+			Name:       c.Name,
+			Status:     parseCheckStatus(c.Status),
+			Message:    c.Message,
+			Details:    c.Details,
+			SuggestFix: c.Fix,
+			Duration:   time.Duration(c.DurationMS) * time.Millisecond,
+		}
+		if c.FixCommand != "" {
+			check.FixCommand = fixCommand(c.FixCommand)
+		} else {
+			check.FixCommand = fixCommand(c.Fix)
+		}
+		report.Checks = append(report.Checks, check)
+	}
+
+	if s := raw.Summary; s != nil {
+		report.TotalChecks = s.Total
+		report.PassedCount = s.Passed
+		report.WarningCount = s.Warnings
+		report.ErrorCount = s.Errors
+		return report, nil
+	}
+	report.TotalChecks = len(report.Checks)
+	for _, c := range report.Checks {
+		switch c.Status {
+		case CheckPassed:
+			report.PassedCount++
+		case CheckWarning:
+			report.WarningCount++
+		case CheckError:
+			report.ErrorCount++
+		}
+	}
+	return report, nil
+}
+
+// parseCheckStatus maps a gt doctor status to a CheckStatus. Unknown
+// statuses count as errors.
+func parseCheckStatus(status string) CheckStatus {
+	switch strings.ToLower(status) {
+	case "ok", "pass", "passed":
+		return CheckPassed
+	case "warn", "warning":
+		return CheckWarning
+	default:
+		return CheckError
+	}
+}
+
+// quotedFix matches a gt or bd command quoted in a fix suggestion, as in
+// "Run 'gt doctor --fix' to clean up".
+var quotedFix = regexp.MustCompile("['`\"]((?:gt|bd) [^'`\"]+)['`\"]")
+
+// fixCommand extracts the gt or bd command a fix suggestion runs: a
+// quoted command, or the whole suggestion after an optional "Run:". It
+// returns nil for suggestions without one, or with shell syntax, since
+// fixes run without a shell.
+func fixCommand(suggestion string) []string {
+	command := ""
+	if m := quotedFix.FindStringSubmatch(suggestion); m != nil {
+		command = m[1]
+	} else {
+		s := strings.TrimSpace(suggestion)
+		s = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(s, "Run:"), "Run"))
+		if strings.HasPrefix(s, "gt ") || strings.HasPrefix(s, "bd ") {
+			command = s
+		}
+	}
+	if command == "" || strings.ContainsAny(command, ";|&$<>()") {
+		return nil
+	}
+	return strings.Fields(command)
+}
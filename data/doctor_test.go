@@ -0,0 +1,97 @@
+package data
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+const doctorJSONOutput = `{
+  "checks": [
+    {"name": "town-config-exists", "status": "ok", "message": "mayor/town.json exists", "duration_ms": 2},
+    {"name": "orphan-sessions", "status": "warning", "message": "2 orphaned tmux sessions",
+     "details": ["gt-perch-able", "gt-perch-baker"], "fix": "Run 'gt doctor --fix orphan-sessions'", "duration_ms": 340},
+    {"name": "bd-daemon", "status": "error", "message": "bd daemon failed to start",
+     "fix": "Restart the daemon", "fix_command": "bd daemon --start", "duration_ms": 1500}
+  ],
+  "summary": {"total": 3, "passed": 1, "warnings": 1, "errors": 1}
+}`
+
+func TestParseDoctorJSON(t *testing.T) {
+	report, err := parseDoctorJSON([]byte(doctorJSONOutput))
+	if err != nil {
+		t.Fatalf("parseDoctorJSON: %v", err)
+	}
+	if report.TotalChecks != 3 || report.PassedCount != 1 || report.WarningCount != 1 || report.ErrorCount != 1 {
+		t.Errorf("unexpected summary %+v", report)
+	}
+
+	orphans, ok := report.Check("orphan-sessions")
+	if !ok || orphans.Status != CheckWarning || len(orphans.Details) != 2 || orphans.Duration != 340*time.Millisecond {
+		t.Errorf("unexpected orphan-sessions check %+v", orphans)
+	}
+	if want := []string{"gt", "doctor", "--fix", "orphan-sessions"}; !slices.Equal(orphans.FixCommand, want) {
+		t.Errorf("expected the fix taken from the suggestion, got %q", orphans.FixCommand)
+	}
+	if daemon, _ := report.Check("bd-daemon"); !slices.Equal(daemon.FixCommand, []string{"bd", "daemon", "--start"}) {
+		t.Errorf("expected the explicit fix command, got %q", daemon.FixCommand)
+	}
+
+	// Without a summary the counts come from the checks
+	report, err = parseDoctorJSON([]byte(`{"checks": [{"name": "a", "status": "passed"}, {"name": "b", "status": "fail"}]}`))
+	if err != nil || report.TotalChecks != 2 || report.PassedCount != 1 || report.ErrorCount != 1 {
+		t.Errorf("expected counts from the checks, got %+v, %v", report, err)
+	}
+
+	if _, err := parseDoctorJSON([]byte("✓ town-git: ok")); !errors.Is(err, errDoctorNotJSON) {
+		t.Errorf("expected text output rejected, got %v", err)
+	}
+}
+
+func TestFixCommand(t *testing.T) {
+	tests := []struct {
+		suggestion string
+		want       []string
+	}{
+		{"Run 'gt doctor --fix' to clean up", []string{"gt", "doctor", "--fix"}},
+		{"Run: bd sync --import-only", []string{"bd", "sync", "--import-only"}},
+		{"Check `bd daemon --status` and logs", []string{"bd", "daemon", "--status"}},
+		{"gt rig boot perch", []string{"gt", "rig", "boot", "perch"}},
+		{"Run 'git init' in your town root", nil},
+		{"Run 'gt doctor --fix && rm -rf /'", nil},
+		{"Restart the daemon", nil},
+	}
+	for _, tt := range tests {
+		if got := fixCommand(tt.suggestion); !slices.Equal(got, tt.want) {
+			t.Errorf("fixCommand(%q) = %q, want %q", tt.suggestion, got, tt.want)
+		}
+	}
+}
+
+func TestLoadDoctorReportFallsBackToText(t *testing.T) {
+	mock := testutil.NewMockRunner()
+	mock.On([]string{"gt", "doctor", "--json"}, nil, []byte("unknown flag: --json"), errors.New("exit status 1"))
+	mock.On([]string{"gt", "doctor"}, []byte("⚠ town-git: Town root is not under version control\n\n1 checks, 0 passed, 1 warnings, 0 errors"), nil, errors.New("exit status 1"))
+	loader := NewLoaderWithRunner("/tmp/town", mock)
+
+	for i := 0; i < 2; i++ {
+		report, err := loader.LoadDoctorReport(t.Context())
+		if err != nil || report.WarningCount != 1 || len(report.Checks) != 1 {
+			t.Fatalf("expected the text report, got %+v, %v", report, err)
+		}
+	}
+	if n := mock.CallCount([]string{"gt", "doctor", "--json"}); n != 1 {
+		t.Errorf("expected --json tried once, got %d", n)
+	}
+
+	mock = testutil.NewMockRunner()
+	mock.On([]string{"gt", "doctor", "--json"}, []byte(doctorJSONOutput), nil, errors.New("exit status 1"))
+	loader = NewLoaderWithRunner("/tmp/town", mock)
+	report, err := loader.LoadDoctorReport(t.Context())
+	if err != nil || report.ErrorCount != 1 || len(mock.Calls()) != 1 {
+		t.Errorf("expected the JSON report from one call, got %+v, %v, %v", report, err, mock.Calls())
+	}
+}
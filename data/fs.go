@@ -0,0 +1,60 @@
+package data
+
+import (
+	"io/fs"
+	"os"
+)
+
+// FS reads and writes files in the town. Everything the loader does with
+// the town directory other than running a command goes through it, so it
+// works against a remote town and tests need no real disk. Paths are
+// absolute paths on the machine the town lives on.
+type FS interface {
+	ReadFile(name string) ([]byte, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+	Stat(name string) (fs.FileInfo, error)
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	MkdirAll(name string, perm fs.FileMode) error
+	Remove(name string) error
+}
+
+// TailReader is an FS that can read just the end of a file. RemoteFS
+// implements it so a long log is not copied whole to show its last lines.
+type TailReader interface {
+	ReadTail(name string, lines int) ([]byte, error)
+}
+
+// readTail returns at least the last lines lines of a file: only those
+// when fsys is a TailReader, the whole file otherwise.
+func readTail(fsys FS, name string, lines int) ([]byte, error) {
+	if tail, ok := fsys.(TailReader); ok {
+		return tail.ReadTail(name, max(lines, 0))
+	}
+	return fsys.ReadFile(name)
+}
+
+// osFS is the local disk.
+type osFS struct{}
+
+func (osFS) ReadFile(name string) ([]byte, error)         { return os.ReadFile(name) }
+func (osFS) ReadDir(name string) ([]fs.DirEntry, error)   { return os.ReadDir(name) }
+func (osFS) Stat(name string) (fs.FileInfo, error)        { return os.Stat(name) }
+func (osFS) MkdirAll(name string, perm fs.FileMode) error { return os.MkdirAll(name, perm) }
+func (osFS) Remove(name string) error                     { return os.Remove(name) }
+
+func (osFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+// files returns the loader's filesystem, the local disk if none is set.
+func (l *Loader) files() FS {
+	return FilesOrDisk(l.FS)
+}
+
+// FilesOrDisk returns fsys, or the local disk if it is nil.
+func FilesOrDisk(fsys FS) FS {
+	if fsys == nil {
+		return osFS{}
+	}
+	return fsys
+}
@@ -0,0 +1,356 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default retention for snapshot history.
+const (
+	DefaultHistoryMaxAge      = 24 * time.Hour
+	DefaultHistoryMaxSegments = 300
+	DefaultHistorySegmentSize = 60
+)
+
+// historyMaxLine bounds a single record line (keyframes hold a full snapshot).
+const historyMaxLine = 64 * 1024 * 1024
+
+// History persists snapshots to disk so earlier town state can be browsed.
+//
+// Snapshots are written to append-only segment files (<unix-nanos>.jsonl).
+// Each segment starts with a keyframe holding every top-level Snapshot field,
+// followed by per-refresh diffs holding only the fields that changed.
+// Retention is applied per segment so every kept segment can be replayed.
+type History struct {
+	Dir string
+
+	// MaxAge drops segments older than this. Zero keeps segments forever.
+	MaxAge time.Duration
+
+	// MaxSegments caps the number of segment files. Zero means unlimited.
+	MaxSegments int
+
+	// SegmentSize is how many records are written before starting a new keyframe.
+	SegmentSize int
+
+	mu       sync.Mutex
+	segment  string                     // Path of the segment being appended to
+	count    int                        // Records written to the current segment
+	previous map[string]json.RawMessage // Fields of the last recorded snapshot
+}
+
+// HistoryEntry identifies one recorded snapshot.
+type HistoryEntry struct {
+	At time.Time
+
+	segment string
+	index   int
+}
+
+// historyRecord is one line of a segment file.
+type historyRecord struct {
+	At       time.Time                  `json:"at"`
+	Keyframe bool                       `json:"keyframe,omitempty"`
+	Fields   map[string]json.RawMessage `json:"fields,omitempty"`
+}
+
+// NewHistory creates a history rooted at dir with default retention.
+func NewHistory(dir string) *History {
+	return &History{
+		Dir:         dir,
+		MaxAge:      DefaultHistoryMaxAge,
+		MaxSegments: DefaultHistoryMaxSegments,
+		SegmentSize: DefaultHistorySegmentSize,
+	}
+}
+
+// DefaultHistoryDir returns a town's directory under ~/.perch/history, so
+// snapshots of different towns are kept apart. town identifies the town:
+// its root, or host:root for a remote one.
+func DefaultHistoryDir(town string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
+	}
+	sum := sha256.Sum256([]byte(town))
+	return filepath.Join(home, ".perch", "history", hex.EncodeToString(sum[:8])), nil
+}
+
+// Record appends a snapshot to the history.
+func (h *History) Record(snap *Snapshot) error {
+	if snap == nil {
+		return nil
+	}
+	fields, err := snapshotFields(snap)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rec := historyRecord{At: snap.LoadedAt}
+	newSegment := h.segment == "" || (h.SegmentSize > 0 && h.count >= h.SegmentSize)
+	if newSegment {
+		rec.Keyframe = true
+		rec.Fields = fields
+	} else {
+		rec.Fields = diffFields(h.previous, fields)
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding history record: %w", err)
+	}
+	line = append(line, '\n')
+
+	if err := os.MkdirAll(h.Dir, 0755); err != nil {
+		return fmt.Errorf("creating history directory: %w", err)
+	}
+
+	path := h.segment
+	if newSegment {
+		path = filepath.Join(h.Dir, fmt.Sprintf("%019d.jsonl", snap.LoadedAt.UnixNano()))
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening history segment: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return fmt.Errorf("writing history segment: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing history segment: %w", err)
+	}
+
+	if newSegment {
+		h.segment = path
+		h.count = 0
+	}
+	h.count++
+	h.previous = fields
+
+	if newSegment {
+		return h.prune(snap.LoadedAt)
+	}
+	return nil
+}
+
+// Prune applies the retention policy relative to now.
+func (h *History) Prune(now time.Time) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.prune(now)
+}
+
+// prune removes segments beyond MaxSegments or older than MaxAge.
+// The segment currently being written is never removed. Caller holds h.mu.
+func (h *History) prune(now time.Time) error {
+	segments, err := h.segments()
+	if err != nil {
+		return err
+	}
+
+	var remove []string
+	if h.MaxSegments > 0 && len(segments) > h.MaxSegments {
+		remove = append(remove, segments[:len(segments)-h.MaxSegments]...)
+		segments = segments[len(segments)-h.MaxSegments:]
+	}
+	if h.MaxAge > 0 {
+		cutoff := now.Add(-h.MaxAge)
+		// A segment ends where the next one starts, so it is expired
+		// once its successor started before the cutoff.
+		for i := 0; i+1 < len(segments); i++ {
+			if segmentStart(segments[i+1]).Before(cutoff) {
+				remove = append(remove, segments[i])
+			}
+		}
+	}
+
+	for _, path := range remove {
+		if path == h.segment {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing history segment: %w", err)
+		}
+	}
+	return nil
+}
+
+// Entries lists every recorded snapshot, oldest first.
+func (h *History) Entries() ([]HistoryEntry, error) {
+	h.mu.Lock()
+	segments, err := h.segments()
+	h.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []HistoryEntry
+	for _, path := range segments {
+		index := 0
+		err := scanSegment(path, func(line []byte) bool {
+			var rec struct {
+				At time.Time `json:"at"`
+			}
+			if json.Unmarshal(line, &rec) == nil {
+				entries = append(entries, HistoryEntry{At: rec.At, segment: path, index: index})
+			}
+			index++
+			return true
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	// Segments from concurrent writers may interleave
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].At.Before(entries[j].At)
+	})
+	return entries, nil
+}
+
+// Load reconstructs the snapshot recorded at entry.
+func (h *History) Load(entry HistoryEntry) (*Snapshot, error) {
+	fields := make(map[string]json.RawMessage)
+	var at time.Time
+	index := 0
+	var decodeErr error
+
+	err := scanSegment(entry.segment, func(line []byte) bool {
+		var rec historyRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			decodeErr = fmt.Errorf("decoding history record %d: %w", index, err)
+			return false
+		}
+		if index == 0 && !rec.Keyframe {
+			decodeErr = fmt.Errorf("history segment %s does not start with a keyframe", filepath.Base(entry.segment))
+			return false
+		}
+		for k, v := range rec.Fields {
+			fields[k] = v
+		}
+		at = rec.At
+		index++
+		return index <= entry.index
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading history segment: %w", err)
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	if index <= entry.index {
+		return nil, fmt.Errorf("history entry %d not found in %s", entry.index, filepath.Base(entry.segment))
+	}
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("encoding history snapshot: %w", err)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(raw, &snap); err != nil {
+		return nil, fmt.Errorf("decoding history snapshot: %w", err)
+	}
+	snap.LoadedAt = at
+	if snap.MergeQueues == nil {
+		snap.MergeQueues = make(map[string][]MergeRequest)
+	}
+	if snap.LastSuccess == nil {
+		snap.LastSuccess = make(map[string]time.Time)
+	}
+	return &snap, nil
+}
+
+// segments returns segment file paths, oldest first.
+func (h *History) segments() ([]string, error) {
+	dirEntries, err := os.ReadDir(h.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading history directory: %w", err)
+	}
+	var paths []string
+	for _, e := range dirEntries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		paths = append(paths, filepath.Join(h.Dir, e.Name()))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// segmentStart parses the start time encoded in a segment file name.
+func segmentStart(path string) time.Time {
+	name := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+	nanos, err := strconv.ParseInt(name, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// scanSegment calls fn for each line of a segment until fn returns false.
+func scanSegment(path string, fn func(line []byte) bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), historyMaxLine)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if !fn(line) {
+			break
+		}
+	}
+	return scanner.Err()
+}
+
+// snapshotFields splits a snapshot into its top-level JSON fields.
+// LoadedAt is stored on the record and the deprecated Errors field is dropped.
+func snapshotFields(snap *Snapshot) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(snap)
+	if err != nil {
+		return nil, fmt.Errorf("encoding snapshot: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("splitting snapshot: %w", err)
+	}
+	delete(fields, "LoadedAt")
+	delete(fields, "Errors")
+	delete(fields, "Timings")
+	return fields, nil
+}
+
+// diffFields returns the fields in next that differ from prev.
+func diffFields(prev, next map[string]json.RawMessage) map[string]json.RawMessage {
+	diff := make(map[string]json.RawMessage)
+	for k, v := range next {
+		if old, ok := prev[k]; !ok || !bytes.Equal(old, v) {
+			diff[k] = v
+		}
+	}
+	return diff
+}
@@ -0,0 +1,180 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+func historyTestSnapshot(at time.Time, mrs int) *Snapshot {
+	snap := &Snapshot{
+		Town:        &TownStatus{Name: "test-town", Rigs: []Rig{{Name: "perch"}}},
+		MergeQueues: map[string][]MergeRequest{"perch": {}},
+		LoadedAt:    at,
+		LastSuccess: map[string]time.Time{"town_status": at},
+		Convoys:     []Convoy{{ID: "convoy-001", Title: "Feature: Auth"}},
+	}
+	for i := 0; i < mrs; i++ {
+		snap.MergeQueues["perch"] = append(snap.MergeQueues["perch"], MergeRequest{ID: "mr-" + string(rune('a'+i))})
+	}
+	return snap
+}
+
+func TestHistoryRecordAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	h := NewHistory(dir)
+	h.SegmentSize = 2
+
+	base := time.Date(2026, 1, 8, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		if err := h.Record(historyTestSnapshot(base.Add(time.Duration(i)*time.Minute), i)); err != nil {
+			t.Fatalf("Record(%d): %v", i, err)
+		}
+	}
+
+	entries, err := h.Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("expected 5 entries, got %d", len(entries))
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 3 {
+		t.Errorf("expected 3 segments with SegmentSize=2, got %d", len(files))
+	}
+
+	for i, entry := range entries {
+		if !entry.At.Equal(base.Add(time.Duration(i) * time.Minute)) {
+			t.Errorf("entry %d: unexpected time %v", i, entry.At)
+		}
+		snap, err := h.Load(entry)
+		if err != nil {
+			t.Fatalf("Load(%d): %v", i, err)
+		}
+		if got := len(snap.MergeQueues["perch"]); got != i {
+			t.Errorf("entry %d: expected %d MRs, got %d", i, i, got)
+		}
+		if snap.Town == nil || snap.Town.Name != "test-town" {
+			t.Errorf("entry %d: town not restored from keyframe", i)
+		}
+		if len(snap.Convoys) != 1 {
+			t.Errorf("entry %d: expected unchanged convoys carried forward, got %d", i, len(snap.Convoys))
+		}
+		if !snap.LoadedAt.Equal(entry.At) {
+			t.Errorf("entry %d: LoadedAt %v != %v", i, snap.LoadedAt, entry.At)
+		}
+	}
+}
+
+func TestHistoryDiffsAreCompact(t *testing.T) {
+	prev, _ := snapshotFields(historyTestSnapshot(time.Now(), 1))
+	next, _ := snapshotFields(historyTestSnapshot(time.Now(), 2))
+
+	diff := diffFields(prev, next)
+	if _, ok := diff["MergeQueues"]; !ok {
+		t.Error("expected MergeQueues in diff")
+	}
+	if _, ok := diff["Town"]; ok {
+		t.Error("unchanged Town should not be in diff")
+	}
+	if _, ok := next["Errors"]; ok {
+		t.Error("deprecated Errors field should not be recorded")
+	}
+}
+
+func TestHistoryRetention(t *testing.T) {
+	t.Run("MaxSegments", func(t *testing.T) {
+		dir := t.TempDir()
+		h := NewHistory(dir)
+		h.SegmentSize = 1
+		h.MaxSegments = 3
+		h.MaxAge = 0
+
+		base := time.Date(2026, 1, 8, 12, 0, 0, 0, time.UTC)
+		for i := 0; i < 6; i++ {
+			if err := h.Record(historyTestSnapshot(base.Add(time.Duration(i)*time.Minute), 0)); err != nil {
+				t.Fatalf("Record: %v", err)
+			}
+		}
+		entries, _ := h.Entries()
+		if len(entries) != 3 {
+			t.Fatalf("expected 3 entries retained, got %d", len(entries))
+		}
+		if !entries[0].At.Equal(base.Add(3 * time.Minute)) {
+			t.Errorf("expected oldest retained entry at +3m, got %v", entries[0].At)
+		}
+	})
+
+	t.Run("MaxAge", func(t *testing.T) {
+		dir := t.TempDir()
+		h := NewHistory(dir)
+		h.SegmentSize = 1
+		h.MaxAge = time.Hour
+
+		base := time.Date(2026, 1, 8, 12, 0, 0, 0, time.UTC)
+		for _, offset := range []time.Duration{0, 30 * time.Minute, 3 * time.Hour} {
+			if err := h.Record(historyTestSnapshot(base.Add(offset), 0)); err != nil {
+				t.Fatalf("Record: %v", err)
+			}
+		}
+		// The +30m segment is still current at the 2h cutoff, so only the first is expired
+		entries, _ := h.Entries()
+		if len(entries) != 2 {
+			t.Fatalf("expected 2 entries retained, got %d", len(entries))
+		}
+		if !entries[0].At.Equal(base.Add(30 * time.Minute)) {
+			t.Errorf("expected oldest retained entry at +30m, got %v", entries[0].At)
+		}
+	})
+}
+
+func TestDefaultHistoryDirPerTown(t *testing.T) {
+	t.Setenv("HOME", "/home/op")
+	local, err := DefaultHistoryDir("/home/op/gt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote, _ := DefaultHistoryDir("deploy@build-01:/home/op/gt")
+	again, _ := DefaultHistoryDir("/home/op/gt")
+	if local == remote || local != again {
+		t.Errorf("expected one directory per town, got %q, %q and %q", local, remote, again)
+	}
+	if filepath.Dir(local) != "/home/op/.perch/history" {
+		t.Errorf("expected town directories under ~/.perch/history, got %q", local)
+	}
+}
+
+func TestHistoryMissingDir(t *testing.T) {
+	h := NewHistory(filepath.Join(t.TempDir(), "missing"))
+	entries, err := h.Entries()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(entries))
+	}
+}
+
+func TestStoreRecordsHistory(t *testing.T) {
+	dir := t.TempDir()
+	mock := testutil.NewMockRunner()
+	mock.On([]string{"gt", "status", "--json"}, testutil.NewFixtures().TownStatusJSON(), nil, nil)
+	store := NewStoreWithLoader(NewLoaderWithRunner("/tmp/town", mock))
+	store.History = NewHistory(dir)
+
+	store.Refresh(t.Context())
+	store.Refresh(t.Context())
+
+	entries, err := store.History.Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("expected 2 recorded refreshes, got %d", len(entries))
+	}
+}
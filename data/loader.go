@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -14,6 +15,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -75,23 +77,57 @@ type Loader struct {
 
 	// Runner executes commands. If nil, uses real exec.
 	Runner CommandRunner
+
+	// FS reads and writes files in the town. If nil, uses the local disk.
+	FS FS
+
+	// LifecycleEvents is how many recent town.log events LoadAll reads.
+	// Zero uses DefaultLifecycleEvents.
+	LifecycleEvents int
+
+	// Schedule sets each source's load timeout, and for a Store its
+	// reload interval. The zero value reloads everything every
+	// DefaultSourceInterval with no timeouts of its own.
+	Schedule Schedule
+
+	// Pool bounds the per-rig and per-convoy commands running at once.
+	// If nil, uses a pool of DefaultFanOut shared by all such loaders.
+	Pool *Pool
+
+	// CallTimeout bounds each of those commands. Zero uses
+	// DefaultCallTimeout.
+	CallTimeout time.Duration
+
+	// Stats, if set, records the latency of every command run.
+	Stats *CommandStats
+
+	// Breakers, if set, stop loading sources that keep failing until
+	// their backoff passes.
+	Breakers *Breakers
+
+	// doctorText is set once gt doctor turns out to lack --json. Copies of
+	// the loader share it; nil always tries --json first.
+	doctorText *atomic.Bool
 }
 
+// DefaultLifecycleEvents is how many lifecycle events LoadAll reads by default.
+const DefaultLifecycleEvents = 100
+
 // NewLoader creates a loader for the given town root.
 func NewLoader(townRoot string) *Loader {
-	return &Loader{TownRoot: townRoot, Runner: &realRunner{}}
+	return &Loader{TownRoot: townRoot, Runner: &realRunner{}, doctorText: new(atomic.Bool)}
 }
 
 // NewLoaderWithRunner creates a loader with a custom command runner.
 // Useful for testing with mock responses.
 func NewLoaderWithRunner(townRoot string, runner CommandRunner) *Loader {
-	return &Loader{TownRoot: townRoot, Runner: runner}
+	return &Loader{TownRoot: townRoot, Runner: runner, doctorText: new(atomic.Bool)}
 }
 
 // execJSON runs a command and unmarshals its JSON output into dst.
 // Returns an error that includes stderr for detailed error messages.
 func (l *Loader) execJSON(ctx context.Context, dst any, args ...string) error {
-	stdout, stderr, err := l.Runner.Exec(ctx, l.TownRoot, args...)
+	stdout, stderr, err := l.runner().Exec(ctx, l.TownRoot, args...)
 	if err != nil {
 		return &execError{
 			cmd:    args[0],
@@ -161,7 +197,8 @@ func (l *Loader) LoadConvoyDetails(ctx context.Context, id string) (*Convoy, err
 }
 
 // LoadConvoysWithDetails loads all convoys with full progress details.
-// This makes one call per convoy but provides complete information.
+// This makes one call per convoy on the loader's pool, each under the call
+// timeout. A convoy whose details fail to load keeps its basic list data.
 func (l *Loader) LoadConvoysWithDetails(ctx context.Context) ([]Convoy, error) {
 	// First get the list of convoys
 	convoys, err := l.LoadConvoys(ctx)
@@ -169,41 +206,15 @@ func (l *Loader) LoadConvoysWithDetails(ctx context.Context) ([]Convoy, error) {
 		return nil, err
 	}
 
-	if len(convoys) == 0 {
-		return convoys, nil
-	}
-
-	// Load details for each convoy in parallel
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	detailed := make([]Convoy, len(convoys))
-	errs := make(chan error, len(convoys))
-
-	for i, c := range convoys {
-		wg.Add(1)
-		go func(idx int, id string) {
-			defer wg.Done()
-			detail, err := l.LoadConvoyDetails(ctx, id)
-			if err != nil {
-				errs <- err
-				return
-			}
-			mu.Lock()
-			detailed[idx] = *detail
-			mu.Unlock()
-		}(i, c.ID)
-	}
-
-	wg.Wait()
-	close(errs)
-
-	// Collect errors but don't fail - use basic convoy data as fallback
-	for err := range errs {
-		// Log error but continue - we'll have partial data
-		_ = err
-	}
-
-	return detailed, nil
+	l.fanOut(ctx, len(convoys), func(ctx context.Context, i int) error {
+		detail, err := l.LoadConvoyDetails(ctx, convoys[i].ID)
+		if err != nil {
+			return err
+		}
+		convoys[i] = *detail
+		return nil
+	})
+	return convoys, ctx.Err()
 }
 
 // LoadConvoyStatus loads detailed status for a specific convoy.
@@ -219,29 +230,20 @@ func (l *Loader) LoadConvoyStatus(ctx context.Context, convoyID string) (*Convoy
 func (l *Loader) LoadAllConvoyStatuses(ctx context.Context, convoys []Convoy) (map[string]*ConvoyStatus, error) {
 	result := make(map[string]*ConvoyStatus)
 	var mu sync.Mutex
-	var wg sync.WaitGroup
-	errs := make(chan error, len(convoys))
 
-	for _, convoy := range convoys {
-		wg.Add(1)
-		go func(c Convoy) {
-			defer wg.Done()
-			status, err := l.LoadConvoyStatus(ctx, c.ID)
-			if err != nil {
-				errs <- err
-				return
-			}
-			mu.Lock()
-			result[c.ID] = status
-			mu.Unlock()
-		}(convoy)
-	}
-
-	wg.Wait()
-	close(errs)
+	errs := l.fanOut(ctx, len(convoys), func(ctx context.Context, i int) error {
+		status, err := l.LoadConvoyStatus(ctx, convoys[i].ID)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		result[convoys[i].ID] = status
+		mu.Unlock()
+		return nil
+	})
 
 	// Return first error if any
-	for err := range errs {
+	if err := firstError(errs); err != nil {
 		return nil, err
 	}
 
@@ -261,29 +263,20 @@ func (l *Loader) LoadMergeQueue(ctx context.Context, rig string) ([]MergeRequest
 func (l *Loader) LoadAllMergeQueues(ctx context.Context, rigs []string) (map[string][]MergeRequest, error) {
 	result := make(map[string][]MergeRequest)
 	var mu sync.Mutex
-	var wg sync.WaitGroup
-	errs := make(chan error, len(rigs))
-
-	for _, rig := range rigs {
-		wg.Add(1)
-		go func(r string) {
-			defer wg.Done()
-			mrs, err := l.LoadMergeQueue(ctx, r)
-			if err != nil {
-				errs <- err
-				return
-			}
-			mu.Lock()
-			result[r] = mrs
-			mu.Unlock()
-		}(rig)
-	}
 
-	wg.Wait()
-	close(errs)
+	errs := l.fanOut(ctx, len(rigs), func(ctx context.Context, i int) error {
+		mrs, err := l.LoadMergeQueue(ctx, rigs[i])
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		result[rigs[i]] = mrs
+		mu.Unlock()
+		return nil
+	})
 
 	// Return first error if any
-	for err := range errs {
+	if err := firstError(errs); err != nil {
 		return nil, err
 	}
 
@@ -348,7 +341,7 @@ func (l *Loader) LoadIssueDependencies(ctx context.Context, issueID string) (*Is
 		DepType    string `json:"dependency_type"`
 	}
 
-	stdout, _, err := l.Runner.Exec(ctx, l.TownRoot, "bd", "dep", "list", issueID)
+	stdout, _, err := l.runner().Exec(ctx, l.TownRoot, "bd", "dep", "list", issueID)
 	if err != nil {
 		// Dependency list may not be implemented yet, return empty result
 		result.LoadError = fmt.Errorf("listing dependencies: %w", err)
@@ -388,14 +381,14 @@ func (l *Loader) LoadIssueDependencies(ctx context.Context, issueID string) (*Is
 // blockerID blocks blockedID (blockedID depends on blockerID).
 // Command: bd dep add <blocked-id> <blocker-id>
 func (l *Loader) AddDependency(ctx context.Context, blockedID, blockerID string) error {
-	_, _, err := l.Runner.Exec(ctx, l.TownRoot, "bd", "dep", "add", blockedID, blockerID)
+	_, _, err := l.runner().Exec(ctx, l.TownRoot, "bd", "dep", "add", blockedID, blockerID)
 	return err
 }
 
 // RemoveDependency removes a dependency relationship between issues.
 // Command: bd dep remove <blocked-id> <blocker-id>
 func (l *Loader) RemoveDependency(ctx context.Context, blockedID, blockerID string) error {
-	_, _, err := l.Runner.Exec(ctx, l.TownRoot, "bd", "dep", "remove", blockedID, blockerID)
+	_, _, err := l.runner().Exec(ctx, l.TownRoot, "bd", "dep", "remove", blockedID, blockerID)
 	return err
 }
 
@@ -420,7 +413,7 @@ func (l *Loader) LoadIssueComments(ctx context.Context, issueID string) (*IssueC
 // AddComment adds a comment to an issue.
 // Command: bd comments add <issue-id> <comment>
 func (l *Loader) AddComment(ctx context.Context, issueID, comment string) error {
-	_, _, err := l.Runner.Exec(ctx, l.TownRoot, "bd", "comments", "add", issueID, comment)
+	_, _, err := l.runner().Exec(ctx, l.TownRoot, "bd", "comments", "add", issueID, comment)
 	return err
 }
 
@@ -457,20 +450,36 @@ func (l *Loader) loadMailFiltered(ctx context.Context, rig, role string, unreadO
 	return mail, nil
 }
 
-// LoadDoctorReport runs gt doctor and parses the output.
+// LoadDoctorReport runs gt doctor --json and parses the output. gt
+// versions without --json fall back to parsing the text output, and the
+// loader remembers to run those directly afterwards.
 func (l *Loader) LoadDoctorReport(ctx context.Context) (*DoctorReport, error) {
-	cmd := exec.CommandContext(ctx, "gt", "doctor")
-	cmd.Dir = l.TownRoot
-
-	// Capture both stdout and stderr (gt doctor writes to both)
-	var output bytes.Buffer
-	cmd.Stdout = &output
-	cmd.Stderr = &output
-
 	// Run command - it may exit with error if there are issues
-	_ = cmd.Run() // Ignore error since gt doctor exits 1 on issues
+	// Ignore the error since gt doctor exits 1 on issues
+	if l.doctorText == nil || !l.doctorText.Load() {
+		stdout, _, _ := l.runner().Exec(ctx, l.TownRoot, "gt", "doctor", "--json")
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("gt doctor: %w", err)
+		}
+		report, err := parseDoctorJSON(stdout)
+		if err == nil {
+			return report, nil
+		}
+		if !errors.Is(err, errDoctorNotJSON) {
+			return nil, fmt.Errorf("gt doctor --json: %w", err)
+		}
+		if l.doctorText != nil {
+			l.doctorText.Store(true)
+		}
+	}
 
-	return parseDoctorOutput(output.String())
+	stdout, stderr, _ := l.runner().Exec(ctx, l.TownRoot, "gt", "doctor")
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("gt doctor: %w", err)
+	}
+
+	// Parse both stdout and stderr (gt doctor writes to both)
+	return parseDoctorOutput(string(stdout) + string(stderr))
 }
 
 // parseDoctorOutput parses the text output from gt doctor.
@@ -530,6 +539,7 @@ func parseDoctorOutput(output string) (*DoctorReport, error) {
 		// Try to match fix suggestion
 		if matches := fixPattern.FindStringSubmatch(line); matches != nil && currentCheck != nil {
 			currentCheck.SuggestFix = matches[1]
+			currentCheck.FixCommand = fixCommand(matches[1])
 			continue
 		}
 
@@ -583,7 +593,7 @@ func (l *Loader) LoadIdentity(ctx context.Context, overseer *Overseer, issues []
 func (l *Loader) loadRecentCommits(ctx context.Context, limit int) []CommitInfo {
 	// Use git log with JSON-like format
 	format := `{"hash":"%h","subject":"%s","author":"%an","date":"%aI"}`
-	stdout, _, err := l.Runner.Exec(ctx, l.TownRoot,
+	stdout, _, err := l.runner().Exec(ctx, l.TownRoot,
 		"git", "log", fmt.Sprintf("-n%d", limit), fmt.Sprintf("--pretty=format:%s,", format))
 	if err != nil {
 		return nil
@@ -756,7 +766,9 @@ var requiredPatrolFormulas = []struct {
 
 // LoadPatrolFormulasHealth checks if patrol formula molecules are available.
 // These formulas are required for refinery/witness to auto-start patrols.
-func (l *Loader) LoadPatrolFormulasHealth(ctx context.Context) *PatrolFormulasHealth {
+// A missing catalog counts as missing formulas; failing to read one is an
+// error.
+func (l *Loader) LoadPatrolFormulasHealth(ctx context.Context) (*PatrolFormulasHealth, error) {
 	health := &PatrolFormulasHealth{
 		FormulasPath:  filepath.Join(l.TownRoot, ".beads", "formulas"),
 		MoleculesPath: filepath.Join(l.TownRoot, ".beads", "molecules.jsonl"),
@@ -764,7 +776,11 @@ func (l *Loader) LoadPatrolFormulasHealth(ctx context.Context) *PatrolFormulasHe
 
 	// Load existing molecules from catalog
 	existingMolecules := make(map[string]bool)
-	if content, err := os.ReadFile(health.MoleculesPath); err == nil {
+	content, err := l.files().ReadFile(health.MoleculesPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
 		scanner := strings.Split(string(content), "\n")
 		for _, line := range scanner {
 			line = strings.TrimSpace(line)
@@ -784,7 +800,7 @@ func (l *Loader) LoadPatrolFormulasHealth(ctx context.Context) *PatrolFormulasHe
 	for _, formula := range requiredPatrolFormulas {
 		// Check if formula file exists
 		formulaPath := filepath.Join(l.TownRoot, ".beads", "formulas", formula.fileName)
-		if _, err := os.Stat(formulaPath); err == nil {
+		if _, err := l.files().Stat(formulaPath); err == nil {
 			health.HasFormulas = true
 		}
 
@@ -807,7 +823,7 @@ func (l *Loader) LoadPatrolFormulasHealth(ctx context.Context) *PatrolFormulasHe
 		// Both missing
 	}
 
-	return health
+	return health, ctx.Err()
 }
 
 // LoadDependencies loads dependencies for an issue using the dependency dialog format.
@@ -816,7 +832,7 @@ func (l *Loader) LoadPatrolFormulasHealth(ctx context.Context) *PatrolFormulasHe
 func (l *Loader) LoadDependencies(ctx context.Context, issueID string) (dependencies, dependents []IssueDependency, err error) {
 	// Use bd dep list to get dependency information
 	// Output format: "pe-abc blocks pe-def"
-	stdout, _, execErr := l.Runner.Exec(ctx, l.TownRoot, "bd", "dep", "list", issueID)
+	stdout, _, execErr := l.runner().Exec(ctx, l.TownRoot, "bd", "dep", "list", issueID)
 	if execErr != nil {
 		// If command fails, return empty - dependencies may not be supported
 		return nil, nil, nil
@@ -836,7 +852,7 @@ func (l *Loader) LoadDependencies(ctx context.Context, issueID string) (dependen
 			// parts[0] is our issue, parts[1] is what blocks us
 			blockedBy := strings.TrimSpace(parts[1])
 			// We need to load the full issue details
-			issueData, _, showErr := l.Runner.Exec(ctx, l.TownRoot, "bd", "show", "--format", "json", blockedBy)
+			issueData, _, showErr := l.runner().Exec(ctx, l.TownRoot, "bd", "show", "--format", "json", blockedBy)
 			if showErr == nil {
 				var depIssue Issue
 				if json.Unmarshal(issueData, &depIssue) == nil {
@@ -867,7 +883,7 @@ func (l *Loader) LoadDependencies(ctx context.Context, issueID string) (dependen
 func (l *Loader) SearchIssues(ctx context.Context, query string, limit int) ([]Issue, error) {
 	if query == "" {
 		// If no query, return recent open issues
-		stdout, _, err := l.Runner.Exec(ctx, l.TownRoot, "bd", "list", "--status=open", "--format=json", "--limit", fmt.Sprintf("%d", limit))
+		stdout, _, err := l.runner().Exec(ctx, l.TownRoot, "bd", "list", "--status=open", "--format=json", "--limit", fmt.Sprintf("%d", limit))
 		if err != nil {
 			return nil, fmt.Errorf("bd list failed: %w", err)
 		}
@@ -879,7 +895,7 @@ func (l *Loader) SearchIssues(ctx context.Context, query string, limit int) ([]I
 	}
 
 	// For search, we need to list all open issues and filter
-	stdout, _, err := l.Runner.Exec(ctx, l.TownRoot, "bd", "list", "--status=open", "--format=json")
+	stdout, _, err := l.runner().Exec(ctx, l.TownRoot, "bd", "list", "--status=open", "--format=json")
 	if err != nil {
 		return nil, fmt.Errorf("bd list failed: %w", err)
 	}
@@ -914,7 +930,7 @@ func (l *Loader) LoadWorktrees(ctx context.Context, rigs []string) ([]Worktree,
 		crewDir := fmt.Sprintf("%s/%s/crew", l.TownRoot, rig)
 
 		// Read crew directory entries
-		entries, err := os.ReadDir(crewDir)
+		entries, err := l.files().ReadDir(crewDir)
 		if err != nil {
 			// Crew directory might not exist or be empty
 			continue
@@ -930,7 +946,7 @@ func (l *Loader) LoadWorktrees(ctx context.Context, rigs []string) ([]Worktree,
 
 			// Check if this is a git worktree (has .git file, not directory)
 			gitPath := fmt.Sprintf("%s/.git", wtPath)
-			info, err := os.Stat(gitPath)
+			info, err := l.files().Stat(gitPath)
 			if err != nil {
 				continue
 			}
@@ -945,7 +961,7 @@ func (l *Loader) LoadWorktrees(ctx context.Context, rigs []string) ([]Worktree,
 			sourceRig, sourceName := parseWorktreeName(name)
 
 			// Get git status for the worktree
-			branch, status, clean := getWorktreeStatus(ctx, wtPath)
+			branch, status, clean := l.getWorktreeStatus(ctx, wtPath)
 
 			worktrees = append(worktrees, Worktree{
 				Rig:        rig,
@@ -973,10 +989,9 @@ func parseWorktreeName(name string) (sourceRig, sourceName string) {
 }
 
 // getWorktreeStatus gets branch and status for a worktree.
-func getWorktreeStatus(ctx context.Context, path string) (branch, status string, clean bool) {
+func (l *Loader) getWorktreeStatus(ctx context.Context, path string) (branch, status string, clean bool) {
 	// Get current branch
-	cmd := exec.CommandContext(ctx, "git", "-C", path, "rev-parse", "--abbrev-ref", "HEAD")
-	out, err := cmd.Output()
+	out, _, err := l.runner().Exec(ctx, l.TownRoot, "git", "-C", path, "rev-parse", "--abbrev-ref", "HEAD")
 	if err == nil {
 		branch = strings.TrimSpace(string(out))
 	} else {
@@ -984,8 +999,7 @@ func getWorktreeStatus(ctx context.Context, path string) (branch, status string,
 	}
 
 	// Get status summary
-	cmd = exec.CommandContext(ctx, "git", "-C", path, "status", "--porcelain")
-	out, err = cmd.Output()
+	out, _, err = l.runner().Exec(ctx, l.TownRoot, "git", "-C", path, "status", "--porcelain")
 	if err != nil {
 		status = "unknown"
 		return
@@ -1049,7 +1063,7 @@ func (l *Loader) LoadPlugins(ctx context.Context, rigNames []string) ([]Plugin,
 
 // scanPluginDir scans a plugins directory and returns plugin info.
 func (l *Loader) scanPluginDir(dir string, scope string) ([]Plugin, error) {
-	entries, err := os.ReadDir(dir)
+	entries, err := l.files().ReadDir(dir)
 	if err != nil {
 		return nil, err
 	}
@@ -1079,20 +1093,20 @@ func (l *Loader) loadPluginInfo(pluginPath, name, scope string) Plugin {
 
 	// Check for disabled marker file
 	disabledPath := filepath.Join(pluginPath, ".disabled")
-	if _, err := os.Stat(disabledPath); err == nil {
+	if _, err := l.files().Stat(disabledPath); err == nil {
 		plugin.Enabled = false
 	}
 
 	// Check for error file
 	errorPath := filepath.Join(pluginPath, ".last_error")
-	if data, err := os.ReadFile(errorPath); err == nil {
+	if data, err := l.files().ReadFile(errorPath); err == nil {
 		plugin.LastError = strings.TrimSpace(string(data))
 		plugin.HasError = plugin.LastError != ""
 	}
 
 	// Check for last run file
 	lastRunPath := filepath.Join(pluginPath, ".last_run")
-	if data, err := os.ReadFile(lastRunPath); err == nil {
+	if data, err := l.files().ReadFile(lastRunPath); err == nil {
 		if t, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data))); err == nil {
 			plugin.LastRun = t
 		}
@@ -1100,15 +1114,14 @@ func (l *Loader) loadPluginInfo(pluginPath, name, scope string) Plugin {
 
 	// Parse plugin.md for metadata
 	pluginMdPath := filepath.Join(pluginPath, "plugin.md")
-	file, err := os.Open(pluginMdPath)
+	content, err := l.files().ReadFile(pluginMdPath)
 	if err != nil {
 		plugin.Title = name // Use directory name as fallback title
 		return plugin
 	}
-	defer file.Close()
 
 	// Parse TOML frontmatter (between +++ markers)
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
 	inFrontmatter := false
 	var frontmatterLines []string
 
@@ -1183,26 +1196,80 @@ type Snapshot struct {
 	Routes               *Routes                 // Beads prefix-to-location routing table
 	PatrolFormulasHealth *PatrolFormulasHealth   // Health of patrol formula molecules
 	LoadedAt             time.Time
-	Errors               []error // Deprecated: use LoadErrors for structured error info
-	LoadErrors           []LoadError          // Structured errors with source context
-	LastSuccess          map[string]time.Time // Per-source last successful load time
+	Errors               []error                 // Deprecated: use LoadErrors for structured error info
+	LoadErrors           []LoadError             // Structured errors with source context
+	LastSuccess          map[string]time.Time    // Per-source last successful load time
+	Stale                map[string]time.Time    // Failed sources keeping earlier data, by when it loaded
+	Timings              map[string]SourceTiming // Per-source duration of the last load
+}
+
+// Data sources, as named in LoadError.Source and Snapshot.LastSuccess.
+// Merge queues record success per rig, as "merge_queue_<rig>".
+const (
+	SourceTownStatus     = "town_status"
+	SourcePolecats       = "polecats"
+	SourceConvoys        = "convoys"
+	SourceClosedConvoys  = "closed_convoys"
+	SourceConvoyStatuses = "convoy_statuses"
+	SourceIssues         = "issues"
+	SourceMail           = "mail"
+	SourceHookedIssues   = "hooked_issues"
+	SourceLifecycle      = "lifecycle"
+	SourceDoctor         = "doctor"
+	SourceMergeQueue     = "merge_queue"
+	SourceWorktrees      = "worktrees"
+	SourcePlugins        = "plugins"
+	SourceRoutes         = "routes"
+	SourcePatrolFormulas = "patrol_formulas"
+)
+
+// AllSources lists every source LoadAll loads.
+var AllSources = []string{
+	SourceTownStatus, SourcePolecats, SourceConvoys, SourceClosedConvoys,
+	SourceConvoyStatuses, SourceIssues, SourceMail, SourceHookedIssues,
+	SourceLifecycle, SourceDoctor, SourceMergeQueue, SourceWorktrees,
+	SourcePlugins, SourceRoutes, SourcePatrolFormulas,
 }
 
 // LoadAll loads all data sources into a snapshot.
 // Errors for individual sources are collected but don't stop other loads.
 func (l *Loader) LoadAll(ctx context.Context) *Snapshot {
-	now := time.Now()
-	snap := &Snapshot{
-		LoadedAt:    now,
-		MergeQueues: make(map[string][]MergeRequest),
-		LastSuccess: make(map[string]time.Time),
+	return l.LoadSources(ctx, nil, AllSources...)
+}
+
+// LoadSources reloads only the given sources and returns a new snapshot
+// that keeps everything else from prev, including its errors and
+// LastSuccess times. prev is not modified; a nil prev loads every source.
+// A source that fails keeps its last good data from prev, marked in Stale
+// with when that data loaded, rather than going empty. Sources whose
+// breaker is open aren't loaded and report their last failure again.
+// Sources that feed each other reload together: hooked issues with the
+// town status they enrich, convoy statuses with convoys. Derived state
+// (operational state, identity) is recomputed when its inputs reload.
+// Each source loads under its Schedule timeout and records its timing.
+func (l *Loader) LoadSources(ctx context.Context, prev *Snapshot, sources ...string) *Snapshot {
+	if prev == nil {
+		sources = AllSources
 	}
+	want := expandSources(sources)
+
+	now := time.Now()
+	snap := prev.carryOver(want, now)
 
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
-	// Helper to add a structured error
-	addError := func(source, command string, err error) {
+	// Helper to record an error
+	report := func(loadErr LoadError, err error) {
+		mu.Lock()
+		snap.LoadErrors = append(snap.LoadErrors, loadErr)
+		snap.Errors = append(snap.Errors, err) // Keep for backwards compat
+		mu.Unlock()
+	}
+
+	// Helper to add a structured error, counting it against the breaker
+	// for key (the source, or one rig's merge queue)
+	addError := func(key, source, command string, err error) {
 		loadErr := LoadError{
 			Source:     source,
 			Command:    command,
@@ -1214,218 +1281,386 @@ func (l *Loader) LoadAll(ctx context.Context) *Snapshot {
 		if errors.As(err, &execErr) {
 			loadErr.Stderr = execErr.Stderr()
 		}
-		mu.Lock()
-		snap.LoadErrors = append(snap.LoadErrors, loadErr)
-		snap.Errors = append(snap.Errors, err) // Keep for backwards compat
-		mu.Unlock()
+		// A cancelled refresh isn't the source failing
+		if ctx.Err() == nil {
+			loadErr = l.Breakers.failed(key, loadErr, now)
+		}
+		report(loadErr, err)
 	}
 
 	// Helper to mark success
 	markSuccess := func(source string) {
+		l.Breakers.succeeded(source)
 		mu.Lock()
 		snap.LastSuccess[source] = now
 		mu.Unlock()
 	}
 
-	// Load town status first (we need rig names for MQ)
-	town, err := l.LoadTownStatus(ctx)
-	if err != nil {
-		addError("town_status", "gt status --json --fast", err)
-	} else {
-		snap.Town = town
-		markSuccess("town_status")
+	// Helper to keep a failed source's last good data, reporting whether
+	// there was any
+	keepStale := func(source string) bool {
+		at, ok := prev.LastGood(source)
+		if ok {
+			mu.Lock()
+			snap.Stale[source] = at
+			mu.Unlock()
+		}
+		return ok
 	}
 
-	// Parallel loads (polecats, convoys, closedConvoys, issues, mail, hookedIssues, lifecycle, doctor)
-	wg.Add(8)
+	// Helper to settle a load: reports whether to use the new value, which
+	// is all but failures with last good data to keep
+	accept := func(source, command string, err error) bool {
+		if err == nil {
+			markSuccess(source)
+			return true
+		}
+		addError(source, source, command, err)
+		return !keepStale(source)
+	}
+
+	// Helper to skip a load while key's breaker is open, reporting its
+	// last failure again and keeping its last good data
+	held := func(key string) bool {
+		loadErr, ok := l.Breakers.allow(key, now)
+		if ok {
+			return false
+		}
+		report(loadErr, errors.New(loadErr.Error))
+		keepStale(key)
+		return true
+	}
+
+	// Helper to load a source under its timeout and time it
+	timed := func(source string, load func(ctx context.Context)) {
+		if !want[source] {
+			return
+		}
+		if held(source) {
+			if prev != nil {
+				mu.Lock()
+				if timing, ok := prev.Timings[source]; ok {
+					snap.Timings[source] = timing
+				}
+				mu.Unlock()
+			}
+			return
+		}
+		ctx, cancel := l.Schedule.context(ctx, source)
+		defer cancel()
+		started := time.Now()
+		load(ctx)
+		mu.Lock()
+		snap.Timings[source] = SourceTiming{Started: started, Duration: time.Since(started)}
+		mu.Unlock()
+	}
+
+	// Helper to load a source in parallel
+	parallel := func(source string, load func(ctx context.Context)) {
+		if !want[source] {
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			timed(source, load)
+		}()
+	}
 
-	go func() {
-		defer wg.Done()
+	// Load town status first (we need rig names for MQ)
+	timed(SourceTownStatus, func(ctx context.Context) {
+		town, err := l.LoadTownStatus(ctx)
+		if accept(SourceTownStatus, "gt status --json --fast", err) {
+			snap.Town = town
+		}
+	})
+
+	// Parallel loads (polecats, convoys, closedConvoys, issues, mail, hookedIssues, lifecycle, doctor)
+	parallel(SourcePolecats, func(ctx context.Context) {
 		polecats, err := l.LoadPolecats(ctx)
-		if err != nil {
-			addError("polecats", "gt polecat list --all --json", err)
-		} else {
+		if accept(SourcePolecats, "gt polecat list --all --json", err) {
 			mu.Lock()
 			snap.Polecats = polecats
 			mu.Unlock()
-			markSuccess("polecats")
 		}
-	}()
+	})
 
-	go func() {
-		defer wg.Done()
+	parallel(SourceConvoys, func(ctx context.Context) {
 		convoys, err := l.LoadConvoysWithDetails(ctx)
-		if err != nil {
-			addError("convoys", "gt convoy list --json", err)
-		} else {
+		if accept(SourceConvoys, "gt convoy list --json", err) {
 			mu.Lock()
 			snap.Convoys = convoys
 			mu.Unlock()
-			markSuccess("convoys")
 		}
-	}()
+	})
 
-	go func() {
-		defer wg.Done()
+	parallel(SourceClosedConvoys, func(ctx context.Context) {
 		closedConvoys, err := l.LoadClosedConvoys(ctx)
-		if err != nil {
-			addError("closed_convoys", "gt convoy list --status=closed --json", err)
-		} else {
+		if accept(SourceClosedConvoys, "gt convoy list --status=closed --json", err) {
 			mu.Lock()
 			snap.ClosedConvoys = closedConvoys
 			mu.Unlock()
-			markSuccess("closed_convoys")
 		}
-	}()
+	})
 
-	go func() {
-		defer wg.Done()
+	parallel(SourceIssues, func(ctx context.Context) {
 		issues, err := l.LoadIssues(ctx)
-		if err != nil {
-			addError("issues", "bd list --json --limit 0", err)
-		} else {
+		if accept(SourceIssues, "bd list --json --limit 0", err) {
 			mu.Lock()
 			snap.Issues = issues
 			mu.Unlock()
-			markSuccess("issues")
 		}
-	}()
+	})
 
-	go func() {
-		defer wg.Done()
+	parallel(SourceMail, func(ctx context.Context) {
 		mail, err := l.LoadMail(ctx)
-		if err != nil {
-			addError("mail", "gt mail inbox --json", err)
-		} else {
+		if accept(SourceMail, "gt mail inbox --json", err) {
 			mu.Lock()
 			snap.Mail = mail
 			mu.Unlock()
-			markSuccess("mail")
 		}
-	}()
+	})
 
-	go func() {
-		defer wg.Done()
+	parallel(SourceHookedIssues, func(ctx context.Context) {
 		// Load both hooked and in_progress issues as active work
+		// Kept hooked issues don't count as loaded, so hook counts fall
+		// back to gt status
 		hooked, err := l.LoadHookedIssues(ctx)
-		if err != nil {
-			addError("hooked_issues", "bd list --json --status hooked --limit 0", err)
-		} else {
-			mu.Lock()
+		use := accept(SourceHookedIssues, "bd list --json --status hooked --limit 0", err)
+		mu.Lock()
+		if use {
 			snap.HookedIssues = hooked
-			snap.HookedLoaded = true
-			mu.Unlock()
-			markSuccess("hooked_issues")
 		}
-	}()
+		snap.HookedLoaded = err == nil
+		mu.Unlock()
+	})
 
-	go func() {
-		defer wg.Done()
-		lifecycle, err := l.LoadLifecycleLog(ctx, 100) // Load last 100 events
-		if err != nil {
-			addError("lifecycle", "$GT_ROOT/logs/town.log", err)
-		} else {
+	parallel(SourceLifecycle, func(ctx context.Context) {
+		limit := l.LifecycleEvents
+		if limit <= 0 {
+			limit = DefaultLifecycleEvents
+		}
+		lifecycle, err := l.LoadLifecycleLog(ctx, limit)
+		if accept(SourceLifecycle, "$GT_ROOT/logs/town.log", err) {
 			mu.Lock()
 			snap.Lifecycle = lifecycle
 			mu.Unlock()
-			markSuccess("lifecycle")
 		}
-	}()
+	})
 
-	go func() {
-		defer wg.Done()
+	parallel(SourceDoctor, func(ctx context.Context) {
 		doctor, err := l.LoadDoctorReport(ctx)
-		if err != nil {
-			addError("doctor", "gt doctor", err)
-		} else {
+		if accept(SourceDoctor, "gt doctor", err) {
 			mu.Lock()
 			snap.DoctorReport = doctor
 			mu.Unlock()
-			markSuccess("doctor")
 		}
-	}()
+	})
 
 	wg.Wait()
 
 	// Load operational state (requires town status and issues for migration check)
-	snap.OperationalState = l.LoadOperationalState(ctx, snap.Town, snap.Issues)
+	if want[SourceTownStatus] || want[SourceIssues] {
+		snap.OperationalState = l.LoadOperationalState(ctx, snap.Town, snap.Issues)
+	}
 
 	// Load patrol formulas health (independent check)
-	snap.PatrolFormulasHealth = l.LoadPatrolFormulasHealth(ctx)
+	timed(SourcePatrolFormulas, func(ctx context.Context) {
+		health, err := l.LoadPatrolFormulasHealth(ctx)
+		if accept(SourcePatrolFormulas, "$GT_ROOT/.beads/molecules.jsonl", err) {
+			snap.PatrolFormulasHealth = health
+		}
+	})
 
 	// Load convoy statuses (requires convoys to be loaded)
-	if len(snap.Convoys) > 0 {
+	timed(SourceConvoyStatuses, func(ctx context.Context) {
+		if len(snap.Convoys) == 0 {
+			snap.ConvoyStatuses = nil
+			return
+		}
 		statuses, err := l.LoadAllConvoyStatuses(ctx, snap.Convoys)
-		if err != nil {
-			addError("convoy_statuses", "gt convoy status --json", err)
-		} else {
+		if accept(SourceConvoyStatuses, "gt convoy status --json", err) {
 			snap.ConvoyStatuses = statuses
-			markSuccess("convoy_statuses")
 		}
-	}
+	})
 
 	// Load MQ for each rig (requires town status)
-	if snap.Town != nil {
-		rigNames := make([]string, len(snap.Town.Rigs))
-		for i, rig := range snap.Town.Rigs {
-			rigNames[i] = rig.Name
-			mrs, err := l.LoadMergeQueue(ctx, rig.Name)
-			if err != nil {
-				addError("merge_queue", fmt.Sprintf("gt mq list %s --json", rig.Name), err)
-			} else {
+	rigNames := snap.RigNames()
+	timed(SourceMergeQueue, func(ctx context.Context) {
+		if snap.Town == nil {
+			return
+		}
+		l.fanOut(ctx, len(rigNames), func(ctx context.Context, i int) error {
+			rig := rigNames[i]
+			key := SourceMergeQueue + "_" + rig
+			keep := func() {
 				mu.Lock()
-				snap.MergeQueues[rig.Name] = mrs
+				snap.MergeQueues[rig] = prev.MergeQueues[rig]
 				mu.Unlock()
-				markSuccess("merge_queue_" + rig.Name)
 			}
-		}
+			if held(key) {
+				if _, ok := prev.LastGood(key); ok {
+					keep()
+				}
+				return nil
+			}
+			mrs, err := l.LoadMergeQueue(ctx, rig)
+			if err != nil {
+				addError(key, SourceMergeQueue, fmt.Sprintf("gt mq list %s --json", rig), err)
+				if keepStale(key) {
+					keep()
+				}
+				return err
+			}
+			mu.Lock()
+			snap.MergeQueues[rig] = mrs
+			mu.Unlock()
+			markSuccess(key)
+			return nil
+		})
+	})
 
-		// Load worktrees (requires rig names)
+	// Load worktrees (requires rig names)
+	timed(SourceWorktrees, func(ctx context.Context) {
+		if snap.Town == nil {
+			snap.Worktrees = nil
+			return
+		}
 		worktrees, err := l.LoadWorktrees(ctx, rigNames)
-		if err != nil {
-			addError("worktrees", "filesystem scan of crew directories", err)
-		} else {
+		if accept(SourceWorktrees, "filesystem scan of crew directories", err) {
 			snap.Worktrees = worktrees
-			markSuccess("worktrees")
 		}
+	})
 
-		// Load plugins (requires rig names)
+	// Load plugins (requires rig names)
+	timed(SourcePlugins, func(ctx context.Context) {
+		if snap.Town == nil {
+			snap.Plugins = nil
+			return
+		}
 		plugins, err := l.LoadPlugins(ctx, rigNames)
-		if err != nil {
-			addError("plugins", "scan of plugin directories", err)
-		} else {
+		if accept(SourcePlugins, "scan of plugin directories", err) {
 			snap.Plugins = plugins
-			markSuccess("plugins")
 		}
-	}
+	})
 
 	// Load beads routing table (fast file read)
-	routes, err := l.LoadRoutes()
-	if err != nil {
-		addError("routes", "read ~/.gt/.beads/routes.jsonl", err)
-	} else {
-		snap.Routes = routes
-		markSuccess("routes")
-	}
+	timed(SourceRoutes, func(context.Context) {
+		routes, err := l.LoadRoutes()
+		if accept(SourceRoutes, "read ~/.gt/.beads/routes.jsonl", err) {
+			snap.Routes = routes
+		}
+	})
 
 	// Load identity (requires town status and issues)
-	var overseer *Overseer
-	if snap.Town != nil {
-		overseer = &snap.Town.Overseer
+	if want[SourceTownStatus] || want[SourceIssues] {
+		var overseer *Overseer
+		if snap.Town != nil {
+			overseer = &snap.Town.Overseer
+		}
+		snap.Identity = l.LoadIdentity(ctx, overseer, snap.Issues)
 	}
-	snap.Identity = l.LoadIdentity(ctx, overseer, snap.Issues)
 
-	// Enrich town status with bead-based hook data
-	snap.EnrichWithHookedBeads()
+	// Enrich town status with bead-based hook data. A kept town status
+	// is shared with prev and was enriched when it loaded.
+	if want[SourceTownStatus] && snap.SourceLoaded(SourceTownStatus) {
+		snap.EnrichWithHookedBeads()
+	}
 
 	return snap
 }
 
+// carryOver starts the snapshot for a reload of the wanted sources: a copy
+// of s without those sources' errors, success times and stale marks. The copy shares
+// s's data, which is never modified in place; sources that reload get
+// new values. A nil s starts an empty snapshot.
+func (s *Snapshot) carryOver(want map[string]bool, now time.Time) *Snapshot {
+	next := &Snapshot{
+		MergeQueues: make(map[string][]MergeRequest),
+		LastSuccess: make(map[string]time.Time),
+		Stale:       make(map[string]time.Time),
+		Timings:     make(map[string]SourceTiming),
+	}
+	if s != nil {
+		*next = *s
+		next.MergeQueues = make(map[string][]MergeRequest)
+		next.LastSuccess = make(map[string]time.Time)
+		next.Stale = make(map[string]time.Time)
+		next.Timings = make(map[string]SourceTiming)
+		next.LoadErrors = nil
+		next.Errors = nil
+		if !want[SourceMergeQueue] {
+			for rig, mrs := range s.MergeQueues {
+				next.MergeQueues[rig] = mrs
+			}
+		}
+		reloads := func(source string) bool {
+			return want[source] || want[SourceMergeQueue] && strings.HasPrefix(source, SourceMergeQueue+"_")
+		}
+		for source, at := range s.LastSuccess {
+			if !reloads(source) {
+				next.LastSuccess[source] = at
+			}
+		}
+		for source, at := range s.Stale {
+			if !reloads(source) {
+				next.Stale[source] = at
+			}
+		}
+		for source, timing := range s.Timings {
+			if !want[source] {
+				next.Timings[source] = timing
+			}
+		}
+		// Errors holds the same errors as LoadErrors, in order, minus any
+		// the store appends afterwards (like history), which are dropped
+		for i, loadErr := range s.LoadErrors {
+			if want[loadErr.Source] || i >= len(s.Errors) {
+				continue
+			}
+			next.LoadErrors = append(next.LoadErrors, loadErr)
+			next.Errors = append(next.Errors, s.Errors[i])
+		}
+	}
+	next.LoadedAt = now
+	return next
+}
+
 // HasErrors returns true if the snapshot has any load errors.
 func (s *Snapshot) HasErrors() bool {
 	return len(s.Errors) > 0
 }
 
+// SourceLoaded reports whether source loaded successfully in this snapshot.
+// Snapshots without success tracking are treated as fully loaded.
+func (s *Snapshot) SourceLoaded(source string) bool {
+	if s.LastSuccess == nil {
+		return true
+	}
+	_, ok := s.LastSuccess[source]
+	return ok
+}
+
+// StaleSince reports whether source failed to load and shows data kept
+// from an earlier load, and when that data loaded. Merge queues are
+// tracked per rig, as "merge_queue_<rig>".
+func (s *Snapshot) StaleSince(source string) (time.Time, bool) {
+	at, ok := s.Stale[source]
+	return at, ok
+}
+
+// LastGood returns when source's data last loaded successfully, whether
+// in this snapshot or kept from an earlier one. A nil snapshot has none.
+func (s *Snapshot) LastGood(source string) (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+	if at, ok := s.LastSuccess[source]; ok {
+		return at, true
+	}
+	return s.StaleSince(source)
+}
+
 // RigNames returns the names of all rigs.
 func (s *Snapshot) RigNames() []string {
 	if s.Town == nil {
@@ -1618,23 +1853,23 @@ func splitAgentAddress(addr string) []string {
 }
 
 // LoadLifecycleLog loads and parses the town.log file.
-// It reads the last 'limit' events from the log file.
+// It reads the last 'limit' events from the log file; a remote town sends
+// only those lines.
 func (l *Loader) LoadLifecycleLog(_ context.Context, limit int) (*LifecycleLog, error) {
 	logPath := filepath.Join(l.TownRoot, "logs", "town.log")
 
-	file, err := os.Open(logPath)
+	content, err := readTail(l.files(), logPath, limit)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			// Log file doesn't exist yet - not an error
 			return &LifecycleLog{LoadedAt: time.Now()}, nil
 		}
 		return nil, fmt.Errorf("opening town.log: %w", err)
 	}
-	defer file.Close()
 
 	// Read all lines first (we need to get the last N)
 	var allLines []string
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
 	for scanner.Scan() {
 		allLines = append(allLines, scanner.Text())
 	}
@@ -1749,7 +1984,7 @@ func (l *Loader) LoadRigSettings(ctx context.Context, rigName string) (*RigSetti
 
 	// Load from rigs.json
 	rigsPath := filepath.Join(l.TownRoot, "mayor", "rigs.json")
-	rigsData, err := os.ReadFile(rigsPath)
+	rigsData, err := l.files().ReadFile(rigsPath)
 	if err == nil {
 		var registry rigsRegistry
 		if err := json.Unmarshal(rigsData, &registry); err == nil {
@@ -1762,7 +1997,7 @@ func (l *Loader) LoadRigSettings(ctx context.Context, rigName string) (*RigSetti
 
 	// Load from <rig>/mayor/rig/settings/config.json
 	configPath := filepath.Join(l.TownRoot, rigName, "mayor", "rig", "settings", "config.json")
-	configData, err := os.ReadFile(configPath)
+	configData, err := l.files().ReadFile(configPath)
 	if err == nil {
 		var config rigConfig
 		if err := json.Unmarshal(configData, &config); err == nil {
@@ -1784,7 +2019,7 @@ func (l *Loader) SaveRigSettings(ctx context.Context, settings *RigSettings) err
 
 	// Update rigs.json (only the prefix, preserve other fields)
 	rigsPath := filepath.Join(l.TownRoot, "mayor", "rigs.json")
-	rigsData, err := os.ReadFile(rigsPath)
+	rigsData, err := l.files().ReadFile(rigsPath)
 	if err != nil {
 		return fmt.Errorf("reading rigs.json: %w", err)
 	}
@@ -1803,7 +2038,7 @@ func (l *Loader) SaveRigSettings(ctx context.Context, settings *RigSettings) err
 			return fmt.Errorf("marshaling rigs.json: %w", err)
 		}
 
-		if err := os.WriteFile(rigsPath, updatedData, 0644); err != nil {
+		if err := l.files().WriteFile(rigsPath, updatedData, 0644); err != nil {
 			return fmt.Errorf("writing rigs.json: %w", err)
 		}
 	}
@@ -1813,7 +2048,7 @@ func (l *Loader) SaveRigSettings(ctx context.Context, settings *RigSettings) err
 
 	// Ensure directory exists
 	configDir := filepath.Dir(configPath)
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+	if err := l.files().MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("creating settings directory: %w", err)
 	}
 
@@ -1828,7 +2063,7 @@ func (l *Loader) SaveRigSettings(ctx context.Context, settings *RigSettings) err
 		return fmt.Errorf("marshaling config: %w", err)
 	}
 
-	if err := os.WriteFile(configPath, configData, 0644); err != nil {
+	if err := l.files().WriteFile(configPath, configData, 0644); err != nil {
 		return fmt.Errorf("writing config: %w", err)
 	}
 
@@ -1841,9 +2076,9 @@ func (l *Loader) SaveRigSettings(ctx context.Context, settings *RigSettings) err
 func (l *Loader) LoadRoutes() (*Routes, error) {
 	routesPath := filepath.Join(l.TownRoot, ".beads", "routes.jsonl")
 
-	data, err := os.ReadFile(routesPath)
+	data, err := l.files().ReadFile(routesPath)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			// No routes file yet - return empty routes
 			return &Routes{Entries: make(map[string]BeadRoute)}, nil
 		}
@@ -228,7 +228,21 @@ func TestLoaderLoadAll(t *testing.T) {
 		return []byte("[]"), nil, nil
 	})
 
+	mock.On([]string{"gt", "doctor"}, []byte("✗ bd-daemon: bd daemon failed to start\n\n3 checks, 2 passed, 0 warnings, 1 errors\n"), nil, errors.New("exit status 1"))
+	mock.On([]string{"git", "-C", "/tmp/town/perch/crew/web-joe", "rev-parse"}, []byte("main\n"), nil, nil)
+	mock.On([]string{"git", "-C", "/tmp/town/perch/crew/web-joe", "status"}, []byte(" M loader.go\n"), nil, nil)
+
+	// Town files, so no source reads the real disk
+	files := testutil.NewMemFS().
+		Add("/tmp/town/logs/town.log", "2026-01-02 07:09:03 [done] perch/able completed pe-1\n").
+		Add("/tmp/town/plugins/digest/plugin.md", "+++\ntitle = \"Daily digest\"\n+++\n").
+		Add("/tmp/town/perch/crew/web-joe/.git", "gitdir: /tmp/town/web/.git/worktrees/joe\n").
+		Add("/tmp/town/.beads/routes.jsonl", `{"prefix":"pe-","location":"/tmp/town/perch","rig":"perch"}`+"\n").
+		Add("/tmp/town/.beads/molecules.jsonl", `{"id":"mol-witness-patrol"}`+"\n").
+		Add("/tmp/town/.beads/formulas/mol-witness-patrol.formula.toml", "")
+
 	loader := NewLoaderWithRunner("/tmp/town", mock)
+	loader.FS = files
 	snap := loader.LoadAll(context.Background())
 
 	if snap.HasErrors() {
@@ -266,6 +280,53 @@ func TestLoaderLoadAll(t *testing.T) {
 	if len(names) != 2 {
 		t.Errorf("expected 2 rig names, got %d", len(names))
 	}
+
+	// File and doctor sources
+	if snap.Lifecycle == nil || len(snap.Lifecycle.Events) != 1 {
+		t.Errorf("expected 1 lifecycle event, got %+v", snap.Lifecycle)
+	}
+	if snap.DoctorReport == nil || snap.DoctorReport.ErrorCount != 1 {
+		t.Errorf("expected a doctor report with 1 error, got %+v", snap.DoctorReport)
+	}
+	if len(snap.Plugins) != 1 || snap.Plugins[0].Title != "Daily digest" {
+		t.Errorf("expected the digest plugin, got %+v", snap.Plugins)
+	}
+	if len(snap.Worktrees) != 1 || snap.Worktrees[0].Branch != "main" || snap.Worktrees[0].Status != "1 uncommitted" {
+		t.Errorf("expected the web-joe worktree on main, got %+v", snap.Worktrees)
+	}
+	if snap.Routes == nil || len(snap.Routes.Entries) != 1 {
+		t.Errorf("expected 1 route, got %+v", snap.Routes)
+	}
+	if health := snap.PatrolFormulasHealth; !health.HasFormulas || !health.HasMolecules || len(health.MissingFormulas) != 1 {
+		t.Errorf("expected the refinery patrol missing, got %+v", health)
+	}
+}
+
+func TestSaveRigSettings(t *testing.T) {
+	files := testutil.NewMemFS().Add("/tmp/town/mayor/rigs.json", `{"version":1,"rigs":{"perch":{"git_url":"git@example.com:perch.git","beads":{"prefix":"pe"}}}}`)
+	loader := &Loader{TownRoot: "/tmp/town", FS: files}
+	ctx := context.Background()
+
+	settings, err := loader.LoadRigSettings(ctx, "perch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.Prefix = "pc"
+	settings.MaxWorkers = 4
+	if err := loader.SaveRigSettings(ctx, settings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	saved, err := loader.LoadRigSettings(ctx, "perch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Prefix != "pc" || saved.MaxWorkers != 4 || saved.GitURL != "git@example.com:perch.git" {
+		t.Errorf("expected the saved settings back, got %+v", saved)
+	}
+	if _, err := files.Stat("/tmp/town/perch/mayor/rig/settings/config.json"); err != nil {
+		t.Errorf("expected config.json written, got %v", err)
+	}
 }
 
 func TestLoaderLoadAllWithPartialErrors(t *testing.T) {
@@ -284,6 +345,7 @@ func TestLoaderLoadAllWithPartialErrors(t *testing.T) {
 	mock.On([]string{"gt", "mq", "list"}, []byte("[]"), nil, nil)
 
 	loader := NewLoaderWithRunner("/tmp/town", mock)
+	loader.FS = testutil.NewMemFS()
 	snap := loader.LoadAll(context.Background())
 
 	// Should have partial data with errors
@@ -2,10 +2,13 @@ package data
 
 import (
 	"context"
+	"errors"
+	"io/fs"
 	"os"
-	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/andyrewlee/perch/internal/testutil"
 )
 
 func TestLoaderIntegration(t *testing.T) {
@@ -858,31 +861,14 @@ func TestStoreRefresh(t *testing.T) {
 }
 
 func TestLoadRoutes(t *testing.T) {
-	// Create a temporary directory for testing
-	tmpDir, err := os.MkdirTemp("", "perch-routes-test-*")
-	if err != nil {
-		t.Fatalf("failed to create temp dir: %v", err)
-	}
-	defer os.RemoveAll(tmpDir)
-
-	// Create .beads directory
-	beadsDir := filepath.Join(tmpDir, ".beads")
-	if err := os.MkdirAll(beadsDir, 0755); err != nil {
-		t.Fatalf("failed to create .beads dir: %v", err)
-	}
-
 	// Write test routes.jsonl
-	routesPath := filepath.Join(beadsDir, "routes.jsonl")
-	testContent := `{"prefix":"hq-","location":"/Users/andrewlee/gt","rig":""}
+	files := testutil.NewMemFS().Add("/town/.beads/routes.jsonl", `{"prefix":"hq-","location":"/Users/andrewlee/gt","rig":""}
 {"prefix":"pe-","location":"/Users/andrewlee/gt/perch","rig":"perch"}
 {"prefix":"gt-","location":"/Users/andrewlee/gt/roles","rig":"roles"}
-`
-	if err := os.WriteFile(routesPath, []byte(testContent), 0644); err != nil {
-		t.Fatalf("failed to write routes.jsonl: %v", err)
-	}
+`)
 
 	// Create loader and load routes
-	loader := NewLoader(tmpDir)
+	loader := &Loader{TownRoot: "/town", FS: files}
 
 	routes, err := loader.LoadRoutes()
 	if err != nil {
@@ -917,25 +903,8 @@ func TestLoadRoutes(t *testing.T) {
 }
 
 func TestLoadRoutes_EmptyFile(t *testing.T) {
-	// Create a temporary directory for testing
-	tmpDir, err := os.MkdirTemp("", "perch-routes-test-*")
-	if err != nil {
-		t.Fatalf("failed to create temp dir: %v", err)
-	}
-	defer os.RemoveAll(tmpDir)
-
-	// Create .beads directory with empty routes.jsonl
-	beadsDir := filepath.Join(tmpDir, ".beads")
-	if err := os.MkdirAll(beadsDir, 0755); err != nil {
-		t.Fatalf("failed to create .beads dir: %v", err)
-	}
-
-	routesPath := filepath.Join(beadsDir, "routes.jsonl")
-	if err := os.WriteFile(routesPath, []byte(""), 0644); err != nil {
-		t.Fatalf("failed to write routes.jsonl: %v", err)
-	}
-
-	loader := NewLoader(tmpDir)
+	// Empty routes.jsonl
+	loader := &Loader{TownRoot: "/town", FS: testutil.NewMemFS().Add("/town/.beads/routes.jsonl", "")}
 
 	routes, err := loader.LoadRoutes()
 	if err != nil {
@@ -948,20 +917,10 @@ func TestLoadRoutes_EmptyFile(t *testing.T) {
 }
 
 func TestLoadRoutes_NoFile(t *testing.T) {
-	// Create a temporary directory without routes.jsonl
-	tmpDir, err := os.MkdirTemp("", "perch-routes-test-*")
-	if err != nil {
-		t.Fatalf("failed to create temp dir: %v", err)
-	}
-	defer os.RemoveAll(tmpDir)
-
-	// Create empty .beads directory
-	beadsDir := filepath.Join(tmpDir, ".beads")
-	if err := os.MkdirAll(beadsDir, 0755); err != nil {
-		t.Fatalf("failed to create .beads dir: %v", err)
-	}
-
-	loader := NewLoader(tmpDir)
+	// Empty .beads directory without routes.jsonl
+	files := testutil.NewMemFS()
+	files.MkdirAll("/town/.beads", 0755)
+	loader := &Loader{TownRoot: "/town", FS: files}
 
 	routes, err := loader.LoadRoutes()
 	if err != nil {
@@ -974,33 +933,16 @@ func TestLoadRoutes_NoFile(t *testing.T) {
 }
 
 func TestLoadRoutes_WithCommentsAndEmptyLines(t *testing.T) {
-	// Create a temporary directory for testing
-	tmpDir, err := os.MkdirTemp("", "perch-routes-test-*")
-	if err != nil {
-		t.Fatalf("failed to create temp dir: %v", err)
-	}
-	defer os.RemoveAll(tmpDir)
-
-	// Create .beads directory
-	beadsDir := filepath.Join(tmpDir, ".beads")
-	if err := os.MkdirAll(beadsDir, 0755); err != nil {
-		t.Fatalf("failed to create .beads dir: %v", err)
-	}
-
 	// Write routes.jsonl with comments and empty lines
-	routesPath := filepath.Join(beadsDir, "routes.jsonl")
-	testContent := `# This is a comment
+	files := testutil.NewMemFS().Add("/town/.beads/routes.jsonl", `# This is a comment
 {"prefix":"hq-","location":"/Users/andrewlee/gt"}
 
 {"prefix":"pe-","location":"/Users/andrewlee/gt/perch"}
 # Another comment
 {"prefix":"gt-","location":"/Users/andrewlee/gt/roles"}
-`
-	if err := os.WriteFile(routesPath, []byte(testContent), 0644); err != nil {
-		t.Fatalf("failed to write routes.jsonl: %v", err)
-	}
+`)
 
-	loader := NewLoader(tmpDir)
+	loader := &Loader{TownRoot: "/town", FS: files}
 
 	routes, err := loader.LoadRoutes()
 	if err != nil {
@@ -1013,3 +955,88 @@ func TestLoadRoutes_WithCommentsAndEmptyLines(t *testing.T) {
 	}
 }
 
+func TestLoadSourcesKeepsLastGoodData(t *testing.T) {
+	fixtures := testutil.NewFixtures()
+	mock := testutil.NewMockRunner()
+	mock.On([]string{"gt", "status", "--json"}, fixtures.TownStatusJSON(), nil, nil)
+	mock.On([]string{"bd", "list", "--json", "--limit", "0"}, fixtures.IssuesJSON(), nil, nil)
+	mock.On([]string{"gt", "mq", "list", "perch"}, fixtures.MergeQueueJSON("perch"), nil, nil)
+	mock.On([]string{"gt", "mq", "list", "sidekick"}, fixtures.MergeQueueJSON("sidekick"), nil, nil)
+
+	loader := NewLoaderWithRunner("/tmp/town", mock)
+	loader.FS = testutil.NewMemFS()
+	good := loader.LoadAll(t.Context())
+	if len(good.Issues) != 3 || len(good.MergeQueues["perch"]) != 2 || len(good.Stale) != 0 {
+		t.Fatalf("expected a clean first load, got %d issues, %v stale", len(good.Issues), good.Stale)
+	}
+
+	// Issues and the perch queue fail twice; they keep the first load's data
+	mock.Reset()
+	mock.On([]string{"gt", "status", "--json"}, fixtures.TownStatusJSON(), nil, nil)
+	mock.On([]string{"bd", "list", "--json", "--limit", "0"}, nil, []byte("database locked"), errors.New("exit status 1"))
+	mock.On([]string{"gt", "mq", "list", "perch"}, nil, nil, errors.New("exit status 1"))
+	mock.On([]string{"gt", "mq", "list", "sidekick"}, fixtures.MergeQueueJSON("sidekick"), nil, nil)
+	snap := good
+	for i := 0; i < 2; i++ {
+		snap = loader.LoadSources(t.Context(), snap, AllSources...)
+	}
+
+	if len(snap.Issues) != 3 || snap.SourceLoaded(SourceIssues) {
+		t.Errorf("expected issues kept but not loaded, got %d", len(snap.Issues))
+	}
+	if since, ok := snap.StaleSince(SourceIssues); !ok || !since.Equal(good.LastSuccess[SourceIssues]) {
+		t.Errorf("expected issues stale since the first load, got %v %v", since, ok)
+	}
+	if len(snap.MergeQueues["perch"]) != 2 {
+		t.Errorf("expected the perch queue kept, got %d", len(snap.MergeQueues["perch"]))
+	}
+	if _, ok := snap.StaleSince(SourceMergeQueue + "_perch"); !ok {
+		t.Error("expected the perch queue stale")
+	}
+	if _, ok := snap.StaleSince(SourceMergeQueue + "_sidekick"); ok {
+		t.Error("expected the sidekick queue fresh")
+	}
+	if len(snap.LoadErrors) != 2 {
+		t.Errorf("expected both failures reported, got %+v", snap.LoadErrors)
+	}
+
+	// Recovering clears the stale mark
+	mock.Reset()
+	mock.On([]string{"bd", "list", "--json", "--limit", "0"}, fixtures.OpenIssuesJSON(), nil, nil)
+	snap = loader.LoadSources(t.Context(), snap, SourceIssues)
+	if _, ok := snap.StaleSince(SourceIssues); ok || !snap.SourceLoaded(SourceIssues) {
+		t.Error("expected issues fresh after reloading")
+	}
+}
+
+// unreadableFS fails to read one file, as a remote town's might.
+type unreadableFS struct {
+	*testutil.MemFS
+	path string
+}
+
+func (f unreadableFS) ReadFile(name string) ([]byte, error) {
+	if name == f.path {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrPermission}
+	}
+	return f.MemFS.ReadFile(name)
+}
+
+func TestLoadSourcesTracksPatrolFormulas(t *testing.T) {
+	files := testutil.NewMemFS().Add("/tmp/town/.beads/molecules.jsonl", `{"id":"mol-deacon-patrol"}`)
+	loader := NewLoaderWithRunner("/tmp/town", testutil.NewMockRunner())
+	loader.FS = files
+	good := loader.LoadSources(t.Context(), nil, SourcePatrolFormulas)
+	if !good.SourceLoaded(SourcePatrolFormulas) || good.PatrolFormulasHealth == nil {
+		t.Fatalf("expected patrol formulas loaded, got %+v", good.LoadErrors)
+	}
+
+	loader.FS = unreadableFS{MemFS: files, path: "/tmp/town/.beads/molecules.jsonl"}
+	snap := loader.LoadSources(t.Context(), good, SourcePatrolFormulas)
+	if len(snap.LoadErrors) != 1 || snap.LoadErrors[0].Source != SourcePatrolFormulas {
+		t.Fatalf("expected a patrol formulas error, got %+v", snap.LoadErrors)
+	}
+	if _, ok := snap.StaleSince(SourcePatrolFormulas); !ok || snap.PatrolFormulasHealth != good.PatrolFormulasHealth {
+		t.Error("expected the last good patrol formulas kept")
+	}
+}
@@ -0,0 +1,197 @@
+package data
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultFanOut is how many per-rig and per-convoy commands run at once
+// across all loaders sharing the default pool.
+const DefaultFanOut = 4
+
+// DefaultCallTimeout bounds a single fan-out command, so one stuck rig or
+// convoy can't hold up the rest.
+const DefaultCallTimeout = 10 * time.Second
+
+// Pool bounds how many fan-out commands run at once. Loaders that share a
+// pool share its bound.
+type Pool struct {
+	slots chan struct{}
+}
+
+// NewPool creates a pool running at most size commands at once.
+func NewPool(size int) *Pool {
+	return &Pool{slots: make(chan struct{}, max(size, 1))}
+}
+
+// defaultPool is shared by loaders without a Pool of their own.
+var defaultPool = NewPool(DefaultFanOut)
+
+// pool returns the loader's pool, the shared default if none is set.
+func (l *Loader) pool() *Pool {
+	if l.Pool == nil {
+		return defaultPool
+	}
+	return l.Pool
+}
+
+// fanOut runs call for items 0..n-1 on the loader's pool, each under the
+// call timeout, and waits for them. Items still waiting for a slot when
+// ctx ends don't run and report ctx's error.
+func (l *Loader) fanOut(ctx context.Context, n int, call func(ctx context.Context, i int) error) []error {
+	timeout := l.CallTimeout
+	if timeout <= 0 {
+		timeout = DefaultCallTimeout
+	}
+	pool := l.pool()
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case pool.slots <- struct{}{}:
+		case <-ctx.Done():
+			for ; i < n; i++ {
+				errs[i] = ctx.Err()
+			}
+			wg.Wait()
+			return errs
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-pool.slots }()
+			callCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			errs[i] = call(callCtx, i)
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+// firstError returns the first non-nil error in errs.
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CommandStats records how long each command takes, by command name
+// (e.g. "gt mq list"), so slow calls show up. It is safe for concurrent
+// use.
+type CommandStats struct {
+	mu        sync.Mutex
+	byCommand map[string]*CommandStat
+}
+
+// CommandStat is the latency of one command across its calls.
+type CommandStat struct {
+	Command string
+	Calls   int
+	Errors  int
+	Total   time.Duration
+	Max     time.Duration
+	Last    time.Duration
+}
+
+// Mean returns the average call duration.
+func (c CommandStat) Mean() time.Duration {
+	if c.Calls == 0 {
+		return 0
+	}
+	return c.Total / time.Duration(c.Calls)
+}
+
+// NewCommandStats creates empty stats.
+func NewCommandStats() *CommandStats {
+	return &CommandStats{byCommand: make(map[string]*CommandStat)}
+}
+
+// Record adds one call of command that took d.
+func (s *CommandStats) Record(command string, d time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stat, ok := s.byCommand[command]
+	if !ok {
+		stat = &CommandStat{Command: command}
+		s.byCommand[command] = stat
+	}
+	stat.Calls++
+	if err != nil {
+		stat.Errors++
+	}
+	stat.Total += d
+	stat.Last = d
+	stat.Max = max(stat.Max, d)
+}
+
+// All returns every command's stats, slowest on average first.
+func (s *CommandStats) All() []CommandStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := make([]CommandStat, 0, len(s.byCommand))
+	for _, stat := range s.byCommand {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if mi, mj := stats[i].Mean(), stats[j].Mean(); mi != mj {
+			return mi > mj
+		}
+		return stats[i].Command < stats[j].Command
+	})
+	return stats
+}
+
+// statsRunner times every command it runs into stats.
+type statsRunner struct {
+	runner CommandRunner
+	stats  *CommandStats
+}
+
+func (r statsRunner) Exec(ctx context.Context, workDir string, args ...string) ([]byte, []byte, error) {
+	started := time.Now()
+	stdout, stderr, err := r.runner.Exec(ctx, workDir, args...)
+	r.stats.Record(commandName(args), time.Since(started), err)
+	return stdout, stderr, err
+}
+
+// runner returns the loader's command runner, timing calls into Stats
+// when set.
+func (l *Loader) runner() CommandRunner {
+	runner := l.Runner
+	if runner == nil {
+		runner = &realRunner{}
+	}
+	if l.Stats == nil {
+		return runner
+	}
+	return statsRunner{runner: runner, stats: l.Stats}
+}
+
+// subcommand matches words that name a subcommand rather than an ID, path
+// or rig argument.
+var subcommand = regexp.MustCompile(`^[a-z][a-z-]*$`)
+
+// commandName names a command for stats: the program and up to two
+// subcommands, without flags or arguments, e.g. "gt mq list" for
+// "gt mq list perch --json". git's -C directory is skipped.
+func commandName(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	name := args[0]
+	rest := args[1:]
+	if name == "git" && len(rest) >= 2 && rest[0] == "-C" {
+		rest = rest[2:]
+	}
+	for i := 0; i < len(rest) && i < 2 && subcommand.MatchString(rest[i]); i++ {
+		name += " " + rest[i]
+	}
+	return name
+}
@@ -0,0 +1,177 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+func TestFanOutBoundsConcurrency(t *testing.T) {
+	loader := NewLoaderWithRunner("/tmp/town", hangingRunner{})
+	loader.Pool = NewPool(2)
+
+	var running, peak atomic.Int32
+	errs := loader.fanOut(t.Context(), 6, func(ctx context.Context, i int) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	if len(errs) != 6 || firstError(errs) != nil {
+		t.Errorf("expected six calls without errors, got %v", errs)
+	}
+	if peak.Load() != 2 {
+		t.Errorf("expected at most 2 calls at once, got %d", peak.Load())
+	}
+}
+
+func TestFanOutTimesOutEachCall(t *testing.T) {
+	loader := NewLoaderWithRunner("/tmp/town", hangingRunner{})
+	loader.CallTimeout = 20 * time.Millisecond
+
+	started := time.Now()
+	errs := loader.fanOut(t.Context(), 3, func(ctx context.Context, i int) error {
+		_, _, err := loader.runner().Exec(ctx, "", "gt", "mq", "list")
+		return err
+	})
+	for i, err := range errs {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected call %d to time out, got %v", i, err)
+		}
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("expected the calls to time out promptly, took %s", elapsed)
+	}
+}
+
+// slowConvoyRunner lists two convoys and hangs loading the second one's
+// details.
+type slowConvoyRunner struct{}
+
+func (slowConvoyRunner) Exec(ctx context.Context, _ string, args ...string) ([]byte, []byte, error) {
+	fixtures := testutil.NewFixtures()
+	switch {
+	case len(args) >= 3 && args[2] == "list":
+		return fixtures.ConvoysJSON(), nil, nil
+	case len(args) >= 4 && args[3] == "convoy-001":
+		return fixtures.ConvoyStatusJSON("convoy-001"), nil, nil
+	}
+	<-ctx.Done()
+	return nil, nil, ctx.Err()
+}
+
+func TestLoadConvoysWithDetailsTimesOutEachConvoy(t *testing.T) {
+	loader := NewLoaderWithRunner("/tmp/town", slowConvoyRunner{})
+	loader.CallTimeout = 20 * time.Millisecond
+
+	convoys, err := loader.LoadConvoysWithDetails(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(convoys) != 2 || convoys[0].Total != 3 {
+		t.Fatalf("expected the first convoy's details, got %+v", convoys)
+	}
+	if convoys[1].ID != "convoy-002" || convoys[1].Total != 0 {
+		t.Errorf("expected the stuck convoy to keep its list data, got %+v", convoys[1])
+	}
+}
+
+func TestCommandName(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"gt", "mq", "list", "perch", "--json"}, "gt mq list"},
+		{[]string{"gt", "status", "--json"}, "gt status"},
+		{[]string{"bd", "show", "gt-123"}, "bd show"},
+		{[]string{"git", "-C", "/tmp/town/perch", "worktree", "list"}, "git worktree list"},
+		{[]string{"gt"}, "gt"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := commandName(tt.args); got != tt.want {
+			t.Errorf("commandName(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestCommandStats(t *testing.T) {
+	stats := NewCommandStats()
+	stats.Record("gt status", 100*time.Millisecond, nil)
+	stats.Record("gt mq list", time.Second, nil)
+	stats.Record("gt mq list", 3*time.Second, errors.New("boom"))
+
+	all := stats.All()
+	if len(all) != 2 || all[0].Command != "gt mq list" {
+		t.Fatalf("expected the merge queue slowest first, got %+v", all)
+	}
+	if mq := all[0]; mq.Calls != 2 || mq.Errors != 1 || mq.Mean() != 2*time.Second || mq.Max != 3*time.Second {
+		t.Errorf("unexpected merge queue stats %+v", mq)
+	}
+}
+
+// gatedRunner runs mock, or waits for its context to end while hang is set.
+type gatedRunner struct {
+	mock    *testutil.MockRunner
+	hang    atomic.Bool
+	hanging sync.WaitGroup
+}
+
+func (r *gatedRunner) Exec(ctx context.Context, workDir string, args ...string) ([]byte, []byte, error) {
+	if r.hang.Load() {
+		r.hanging.Done()
+		<-ctx.Done()
+		return nil, nil, ctx.Err()
+	}
+	return r.mock.Exec(ctx, workDir, args...)
+}
+
+func TestRefreshSupersedesLoadInFlight(t *testing.T) {
+	fixtures := testutil.NewFixtures()
+	mock := testutil.NewMockRunner()
+	mock.On([]string{"gt", "status", "--json"}, fixtures.TownStatusJSON(), nil, nil)
+	mock.On([]string{"gt", "polecat", "list"}, fixtures.PolecatsJSON(), nil, nil)
+	mock.On([]string{"gt", "mq", "list"}, []byte("[]"), nil, nil)
+	runner := &gatedRunner{mock: mock}
+
+	loader := NewLoaderWithRunner("/tmp/town", runner)
+	loader.FS = testutil.NewMemFS()
+	store := NewStoreWithLoader(loader)
+	first := store.Refresh(t.Context())
+
+	var refreshes atomic.Int32
+	store.OnRefresh = func(*Snapshot) { refreshes.Add(1) }
+	runner.hang.Store(true)
+	runner.hanging.Add(1)
+	done := make(chan *Snapshot)
+	go func() { done <- store.RefreshSources(t.Context(), SourcePolecats) }()
+	runner.hanging.Wait()
+	runner.hang.Store(false)
+
+	latest := store.Refresh(t.Context())
+	select {
+	case snap := <-done:
+		if snap != first && snap != latest {
+			t.Error("expected the superseded load to return the cached snapshot")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the full refresh to cancel the polecat load")
+	}
+	if len(latest.LoadErrors) != 0 || len(latest.Polecats) != len(first.Polecats) {
+		t.Errorf("expected a clean full refresh, got errors %+v", latest.LoadErrors)
+	}
+	if n := refreshes.Load(); n != 1 {
+		t.Errorf("expected only the full refresh committed, got %d", n)
+	}
+}
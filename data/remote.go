@@ -0,0 +1,257 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultControlPath is where SSHRunner keeps its shared connection socket.
+// ssh expands the ~ and the %C connection hash.
+const DefaultControlPath = "~/.ssh/perch-%C"
+
+// remoteFSTimeout bounds a single RemoteFS read, which has no context.
+const remoteFSTimeout = 30 * time.Second
+
+// SSHRunner runs commands on another machine over SSH. Commands share one
+// persistent connection per host (an OpenSSH ControlMaster), so each costs
+// a round trip rather than a handshake. Authentication must not prompt:
+// use keys or an agent.
+type SSHRunner struct {
+	// Host is the ssh destination, e.g. "deploy@build-01".
+	Host string
+
+	// ControlPath is the shared connection socket. Empty uses
+	// DefaultControlPath.
+	ControlPath string
+
+	// Transport runs the local ssh command. If nil, uses real exec.
+	Transport CommandRunner
+}
+
+// NewSSHRunner creates a runner for the given ssh destination.
+func NewSSHRunner(host string) *SSHRunner {
+	return &SSHRunner{Host: host}
+}
+
+// Exec runs args in workDir on the remote host.
+func (r *SSHRunner) Exec(ctx context.Context, workDir string, args ...string) ([]byte, []byte, error) {
+	if len(args) == 0 {
+		return nil, nil, fmt.Errorf("no command specified")
+	}
+	transport := r.Transport
+	if transport == nil {
+		transport = &realRunner{}
+	}
+	return transport.Exec(ctx, "", r.sshArgs(remoteCommand(workDir, args))...)
+}
+
+// sshArgs builds the local ssh invocation for a remote shell command.
+func (r *SSHRunner) sshArgs(command string) []string {
+	controlPath := r.ControlPath
+	if controlPath == "" {
+		controlPath = DefaultControlPath
+	}
+	return []string{
+		"ssh",
+		"-o", "BatchMode=yes",
+		"-o", "ControlMaster=auto",
+		"-o", "ControlPath=" + controlPath,
+		"-o", "ControlPersist=10m",
+		"--", r.Host,
+		command,
+	}
+}
+
+// remoteCommand quotes args into one shell command line, run from workDir
+// when set. ssh hands the remote shell a single string, so every argument
+// is quoted.
+func remoteCommand(workDir string, args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	command := strings.Join(quoted, " ")
+	if workDir != "" {
+		command = "cd " + shellQuote(workDir) + " && " + command
+	}
+	return command
+}
+
+// shellSafe matches arguments that need no quoting.
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_./=:,@%+-]+$`)
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ParseRemote splits a remote town spec, "user@host:/path/to/gt", into the
+// ssh destination and the town root on that host.
+func ParseRemote(spec string) (host, root string, err error) {
+	host, root, ok := strings.Cut(spec, ":")
+	if !ok || host == "" || !strings.HasPrefix(root, "/") {
+		return "", "", fmt.Errorf("remote %q must be user@host:/path/to/gt with an absolute path", spec)
+	}
+	return host, root, nil
+}
+
+// NewRemoteLoader creates a loader for a town on another machine: commands
+// run over SSH and files are read through the same connection.
+func NewRemoteLoader(host, townRoot string) *Loader {
+	runner := NewSSHRunner(host)
+	return &Loader{TownRoot: townRoot, Runner: runner, FS: RemoteFS{Runner: runner}, doctorText: new(atomic.Bool)}
+}
+
+// RemoteFS reads and writes files by running commands through a
+// CommandRunner, usually an SSHRunner. Directory listings and stats need
+// GNU find on the remote host. Writes take the remote umask rather than
+// perm.
+type RemoteFS struct {
+	Runner CommandRunner
+}
+
+// ReadFile reads a file with cat.
+func (f RemoteFS) ReadFile(name string) ([]byte, error) {
+	return f.run("open", name, "cat", "--", name)
+}
+
+// ReadTail reads the last lines of a file with tail.
+func (f RemoteFS) ReadTail(name string, lines int) ([]byte, error) {
+	return f.run("open", name, "tail", "-n", strconv.Itoa(lines), "--", name)
+}
+
+// ReadDir lists a directory, sorted by name like os.ReadDir.
+func (f RemoteFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	out, err := f.run("readdir", name, "find", name, "-mindepth", "1", "-maxdepth", "1", "-printf", remoteStatFormat)
+	if err != nil {
+		return nil, err
+	}
+	var entries []fs.DirEntry
+	for _, line := range strings.Split(strings.TrimSuffix(string(out), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		info, err := parseRemoteStat(line)
+		if err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// Stat describes a file, following symlinks like os.Stat.
+func (f RemoteFS) Stat(name string) (fs.FileInfo, error) {
+	out, err := f.run("stat", name, "find", "-L", name, "-maxdepth", "0", "-printf", remoteStatFormat)
+	if err != nil {
+		return nil, err
+	}
+	info, err := parseRemoteStat(strings.TrimSuffix(string(out), "\n"))
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	return info, nil
+}
+
+// WriteFile replaces a file's content. The content travels as a shell
+// argument, which suits small files like settings.
+func (f RemoteFS) WriteFile(name string, data []byte, _ fs.FileMode) error {
+	_, err := f.run("open", name, "sh", "-c", `printf '%s' "$1" > "$2"`, "sh", string(data), name)
+	return err
+}
+
+// MkdirAll creates a directory and its parents with mkdir -p.
+func (f RemoteFS) MkdirAll(name string, _ fs.FileMode) error {
+	_, err := f.run("mkdir", name, "mkdir", "-p", "--", name)
+	return err
+}
+
+// Remove removes a file with rm.
+func (f RemoteFS) Remove(name string) error {
+	_, err := f.run("remove", name, "rm", "--", name)
+	return err
+}
+
+// run runs a command, mapping a missing file to fs.ErrNotExist.
+func (f RemoteFS) run(op, name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteFSTimeout)
+	defer cancel()
+
+	stdout, stderr, err := f.Runner.Exec(ctx, "", args...)
+	if err != nil {
+		if strings.Contains(string(stderr), "No such file or directory") {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		return nil, &fs.PathError{Op: op, Path: name, Err: &execError{
+			cmd:    args[0],
+			args:   args,
+			err:    err,
+			stderr: string(stderr),
+		}}
+	}
+	return stdout, nil
+}
+
+// remoteStatFormat is the find -printf format parseRemoteStat reads: type,
+// size, modification time and base name.
+const remoteStatFormat = `%y %s %T@ %f\n`
+
+// parseRemoteStat parses one line of remoteStatFormat output.
+func parseRemoteStat(line string) (fs.FileInfo, error) {
+	fields := strings.SplitN(line, " ", 4)
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("unexpected find output %q", line)
+	}
+	size, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected find size %q", fields[1])
+	}
+	secs, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected find time %q", fields[2])
+	}
+	var mode fs.FileMode
+	switch fields[0] {
+	case "d":
+		mode = fs.ModeDir
+	case "l":
+		mode = fs.ModeSymlink
+	case "p":
+		mode = fs.ModeNamedPipe
+	case "s":
+		mode = fs.ModeSocket
+	}
+	return remoteFileInfo{
+		name:    path.Base(fields[3]),
+		size:    size,
+		mode:    mode,
+		modTime: time.Unix(0, int64(secs*float64(time.Second))),
+	}, nil
+}
+
+// remoteFileInfo is a file described by RemoteFS.
+type remoteFileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i remoteFileInfo) Name() string       { return i.name }
+func (i remoteFileInfo) Size() int64        { return i.size }
+func (i remoteFileInfo) Mode() fs.FileMode  { return i.mode }
+func (i remoteFileInfo) ModTime() time.Time { return i.modTime }
+func (i remoteFileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i remoteFileInfo) Sys() any           { return nil }
@@ -0,0 +1,145 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+// localSSH is a fake ssh transport: it runs the remote command line with
+// the local shell, as sshd would on the remote host.
+func localSSH() *testutil.MockRunner {
+	mock := testutil.NewMockRunner()
+	mock.OnMatcher(func(args []string) bool { return args[0] == "ssh" }, func(args []string) ([]byte, []byte, error) {
+		stdout, err := exec.Command("sh", "-c", args[len(args)-1]).Output()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return stdout, exitErr.Stderr, err
+		}
+		return stdout, nil, err
+	})
+	return mock
+}
+
+func TestSSHRunnerQuotesCommand(t *testing.T) {
+	mock := testutil.NewMockRunner()
+	runner := &SSHRunner{Host: "deploy@build-01", Transport: mock}
+	if _, _, err := runner.Exec(context.Background(), "/srv/my gt", "gt", "mail", "send", "--subject", "it's done"); err != nil {
+		t.Fatal(err)
+	}
+	calls := mock.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("expected one ssh call, got %v", calls)
+	}
+	args := calls[0].Args
+	if args[0] != "ssh" || args[len(args)-2] != "deploy@build-01" {
+		t.Errorf("expected ssh to deploy@build-01, got %v", args)
+	}
+	if !mock.CalledWith([]string{"ssh", "-o", "BatchMode=yes", "-o", "ControlMaster=auto", "-o", "ControlPath=" + DefaultControlPath}) {
+		t.Errorf("expected a shared connection, got %v", args)
+	}
+	want := `cd '/srv/my gt' && gt mail send --subject 'it'\''s done'`
+	if got := args[len(args)-1]; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestParseRemote(t *testing.T) {
+	host, root, err := ParseRemote("deploy@build-01:/srv/gt")
+	if err != nil || host != "deploy@build-01" || root != "/srv/gt" {
+		t.Errorf("unexpected %q %q %v", host, root, err)
+	}
+	for _, spec := range []string{"build-01", ":/srv/gt", "build-01:gt"} {
+		if _, _, err := ParseRemote(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
+
+func TestRemoteLoaderReadsTown(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("logs/town.log", "2026-01-02 07:09:03 [done] perch/nux completed pe-1\n")
+	write("plugins/digest/plugin.md", "+++\ntitle = \"Daily digest\"\n+++\n")
+	write("plugins/digest/.disabled", "disabled\n")
+	write("mayor/rigs.json", `{"version":1,"rigs":{"perch":{"beads":{"prefix":"pe"}}}}`)
+	write("perch/crew/web-joe/.git", "gitdir: /elsewhere\n")
+
+	runner := &SSHRunner{Host: "deploy@build-01", Transport: localSSH()}
+	loader := &Loader{TownRoot: root, Runner: runner, FS: RemoteFS{Runner: runner}}
+	ctx := context.Background()
+
+	log, err := loader.LoadLifecycleLog(ctx, 10)
+	if err != nil || len(log.Events) != 1 || log.Events[0].Agent != "perch/nux" {
+		t.Errorf("expected the town.log event, got %+v %v", log, err)
+	}
+	plugins, _ := loader.LoadPlugins(ctx, nil)
+	if len(plugins) != 1 || plugins[0].Title != "Daily digest" || plugins[0].Enabled {
+		t.Errorf("expected the disabled digest plugin, got %+v", plugins)
+	}
+	settings, _ := loader.LoadRigSettings(ctx, "perch")
+	if settings.Prefix != "pe" {
+		t.Errorf("expected the prefix from rigs.json, got %q", settings.Prefix)
+	}
+	settings.Theme = "it's green"
+	if err := loader.SaveRigSettings(ctx, settings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+	if saved, _ := loader.LoadRigSettings(ctx, "perch"); saved.Theme != "it's green" {
+		t.Errorf("expected the theme saved over ssh, got %q", saved.Theme)
+	}
+	worktrees, _ := loader.LoadWorktrees(ctx, []string{"perch"})
+	if len(worktrees) != 1 || worktrees[0].SourceRig != "web" || worktrees[0].SourceName != "joe" {
+		t.Errorf("expected the web-joe worktree, got %+v", worktrees)
+	}
+
+	// A missing file reads as not existing, not as a failure
+	routes, err := loader.LoadRoutes()
+	if err != nil || len(routes.Entries) != 0 {
+		t.Errorf("expected no routes, got %+v %v", routes, err)
+	}
+	if _, err := loader.files().Stat(filepath.Join(root, "missing")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+}
+
+func TestRemoteLifecycleLogReadsTail(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "logs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	log := "2026-01-02 07:09:01 [spawn] perch/nux started\n" +
+		"2026-01-02 07:09:02 [nudge] perch/nux nudged\n" +
+		"2026-01-02 07:09:03 [done] perch/nux completed pe-1\n"
+	if err := os.WriteFile(filepath.Join(root, "logs", "town.log"), []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	transport := localSSH()
+	runner := &SSHRunner{Host: "deploy@build-01", Transport: transport}
+	loader := &Loader{TownRoot: root, Runner: runner, FS: RemoteFS{Runner: runner}}
+
+	got, err := loader.LoadLifecycleLog(context.Background(), 2)
+	if err != nil || len(got.Events) != 2 || got.Events[0].EventType != "done" || got.Events[1].EventType != "nudge" {
+		t.Fatalf("expected the last two events, newest first, got %+v %v", got, err)
+	}
+	calls := transport.Calls()
+	if len(calls) != 1 || !strings.HasPrefix(calls[0].Args[len(calls[0].Args)-1], "tail -n 2 -- ") {
+		t.Errorf("expected a remote tail of town.log, got %v", calls)
+	}
+}
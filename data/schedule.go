@@ -0,0 +1,190 @@
+package data
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultSourceInterval is how often sources without their own interval
+// reload when a schedule has no base interval.
+const DefaultSourceInterval = 10 * time.Second
+
+// DefaultLiveInterval is how often the live sources reload by default.
+const DefaultLiveInterval = 5 * time.Second
+
+// LiveSources are the sources that follow sessions as they run: town
+// status, hooked issues and polecats. They reload most often.
+var LiveSources = []string{SourceTownStatus, SourceHookedIssues, SourcePolecats}
+
+// SourcePolicy is how often one source reloads, how long a load may take,
+// and how old its data may get before it counts as stale.
+type SourcePolicy struct {
+	Interval   time.Duration // Time between reloads; zero uses the schedule's interval
+	OnChange   bool          // Reload only when its files change or on a full refresh
+	Timeout    time.Duration // Bound on one load; zero leaves only the refresh's bound
+	StaleAfter time.Duration // Data age that counts as stale; zero is three intervals
+}
+
+// Schedule gives each source its own reload policy. Sources without an
+// entry reload every Interval.
+type Schedule struct {
+	Interval time.Duration
+	Sources  map[string]SourcePolicy
+}
+
+// DefaultSchedule returns the built-in policies around a base interval:
+// town status and polecats every 5s, doctor every 5m, slow-moving sources
+// like plugins every minute or more.
+func DefaultSchedule(interval time.Duration) Schedule {
+	return Schedule{
+		Interval: interval,
+		Sources: map[string]SourcePolicy{
+			SourceTownStatus:     {Interval: DefaultLiveInterval, Timeout: 15 * time.Second},
+			SourceHookedIssues:   {Interval: DefaultLiveInterval, Timeout: 15 * time.Second},
+			SourcePolecats:       {Interval: DefaultLiveInterval, Timeout: 15 * time.Second},
+			SourceClosedConvoys:  {Interval: time.Minute},
+			SourceDoctor:         {Interval: 5 * time.Minute, Timeout: time.Minute, StaleAfter: 15 * time.Minute},
+			SourceWorktrees:      {Interval: time.Minute},
+			SourcePlugins:        {Interval: time.Minute},
+			SourceRoutes:         {Interval: time.Minute},
+			SourcePatrolFormulas: {Interval: 5 * time.Minute},
+		},
+	}
+}
+
+// Policy returns the policy for source with the defaults filled in.
+func (s Schedule) Policy(source string) SourcePolicy {
+	p := s.Sources[source]
+	if p.Interval == 0 {
+		p.Interval = s.Interval
+	}
+	if p.Interval == 0 {
+		p.Interval = DefaultSourceInterval
+	}
+	if p.StaleAfter == 0 && !p.OnChange {
+		p.StaleAfter = 3 * p.Interval
+	}
+	return p
+}
+
+// changeOnlySources are the watched sources that stop polling while
+// watched. Plugin directories only change by hand.
+var changeOnlySources = map[string]bool{SourcePlugins: true}
+
+// WithLiveInterval returns a copy of s with LiveSources reloading every
+// interval. A non-positive interval leaves s unchanged.
+func (s Schedule) WithLiveInterval(interval time.Duration) Schedule {
+	if interval <= 0 {
+		return s
+	}
+	sources := make(map[string]SourcePolicy, len(s.Sources))
+	for source, p := range s.Sources {
+		sources[source] = p
+	}
+	for _, source := range LiveSources {
+		p := sources[source]
+		p.Interval = interval
+		sources[source] = p
+	}
+	return Schedule{Interval: s.Interval, Sources: sources}
+}
+
+// Watching returns a copy of s for a town whose files are watched: the
+// sources in WatchedSources reload when their files change, so their
+// polling slows to at least fallback, or stops for changeOnlySources.
+func (s Schedule) Watching(fallback time.Duration) Schedule {
+	sources := make(map[string]SourcePolicy, len(s.Sources))
+	for source, p := range s.Sources {
+		sources[source] = p
+	}
+	for _, source := range WatchedSources {
+		p := s.Policy(source)
+		if changeOnlySources[source] {
+			p.OnChange = true
+			p.StaleAfter = s.Sources[source].StaleAfter
+		} else if p.Interval < fallback {
+			if s.Sources[source].StaleAfter == 0 {
+				p.StaleAfter = 0
+			}
+			p.Interval = fallback
+		}
+		sources[source] = p
+	}
+	return Schedule{Interval: s.Interval, Sources: sources}
+}
+
+// Stale reports whether data for source loaded at loadedAt is past its
+// staleness budget at now.
+func (s Schedule) Stale(source string, loadedAt, now time.Time) bool {
+	p := s.Policy(source)
+	return p.StaleAfter > 0 && now.Sub(loadedAt) > p.StaleAfter
+}
+
+// context bounds ctx by source's timeout, if it has one.
+func (s Schedule) context(ctx context.Context, source string) (context.Context, context.CancelFunc) {
+	if timeout := s.Sources[source].Timeout; timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// due returns the sources whose interval has passed at now, given when
+// each last ran. On-change sources are due only if they never ran.
+func (s Schedule) due(lastRun map[string]time.Time, now time.Time) []string {
+	var due []string
+	for _, source := range AllSources {
+		at, ok := lastRun[source]
+		p := s.Policy(source)
+		if !ok || (!p.OnChange && now.Sub(at) >= p.Interval) {
+			due = append(due, source)
+		}
+	}
+	return due
+}
+
+// nextDue returns when the next source falls due, given when each last
+// ran. It returns now if one is due already.
+func (s Schedule) nextDue(lastRun map[string]time.Time, now time.Time) time.Time {
+	next := time.Time{}
+	for _, source := range AllSources {
+		at, ok := lastRun[source]
+		if !ok {
+			return now
+		}
+		p := s.Policy(source)
+		if p.OnChange {
+			continue
+		}
+		if at = at.Add(p.Interval); next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	if next.IsZero() || next.Before(now) {
+		return now
+	}
+	return next
+}
+
+// SourceTiming is how long a source's last load took.
+type SourceTiming struct {
+	Started  time.Time
+	Duration time.Duration
+}
+
+// expandSources returns the set of sources a reload of sources loads:
+// hooked issues reload with the town status they enrich, convoy statuses
+// with convoys.
+func expandSources(sources []string) map[string]bool {
+	want := make(map[string]bool, len(AllSources))
+	for _, source := range sources {
+		want[source] = true
+	}
+	if want[SourceTownStatus] || want[SourceHookedIssues] {
+		want[SourceTownStatus] = true
+		want[SourceHookedIssues] = true
+	}
+	if want[SourceConvoys] {
+		want[SourceConvoyStatuses] = true
+	}
+	return want
+}
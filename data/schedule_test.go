@@ -0,0 +1,132 @@
+package data
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+func TestScheduleDue(t *testing.T) {
+	schedule := DefaultSchedule(10 * time.Second)
+	start := time.Date(2026, 1, 2, 7, 0, 0, 0, time.UTC)
+
+	lastRun := make(map[string]time.Time)
+	if due := schedule.due(lastRun, start); len(due) != len(AllSources) {
+		t.Errorf("expected every source due before the first load, got %v", due)
+	}
+	for _, source := range AllSources {
+		lastRun[source] = start
+	}
+	if next := schedule.nextDue(lastRun, start); !next.Equal(start.Add(5 * time.Second)) {
+		t.Errorf("expected town status due in 5s, got %s", next.Sub(start))
+	}
+
+	due := schedule.due(lastRun, start.Add(10*time.Second))
+	for _, source := range []string{SourceTownStatus, SourcePolecats, SourceIssues, SourceMergeQueue} {
+		if !slices.Contains(due, source) {
+			t.Errorf("expected %s due after 10s, got %v", source, due)
+		}
+	}
+	for _, source := range []string{SourceDoctor, SourceWorktrees, SourcePlugins} {
+		if slices.Contains(due, source) {
+			t.Errorf("expected %s not due after 10s, got %v", source, due)
+		}
+	}
+	if due := schedule.due(lastRun, start.Add(time.Hour)); !slices.Contains(due, SourcePlugins) {
+		t.Error("expected plugins to poll without a watcher")
+	}
+
+	if !schedule.Stale(SourceIssues, start, start.Add(31*time.Second)) || schedule.Stale(SourceDoctor, start, start.Add(10*time.Minute)) {
+		t.Error("expected issues stale after three intervals and doctor within its budget")
+	}
+
+	live := schedule.WithLiveInterval(2 * time.Second)
+	if p := live.Policy(SourcePolecats); p.Interval != 2*time.Second || p.Timeout != 15*time.Second {
+		t.Errorf("expected polecats every 2s, got %+v", p)
+	}
+	if p := schedule.Policy(SourcePolecats); p.Interval != DefaultLiveInterval {
+		t.Errorf("expected the original schedule unchanged, got %+v", p)
+	}
+
+	// Watched sources poll at the fallback, the rest keep their interval
+	watching := schedule.Watching(time.Minute)
+	if p := watching.Policy(SourceIssues); p.Interval != time.Minute || p.StaleAfter != 3*time.Minute {
+		t.Errorf("expected issues every minute while watching, got %+v", p)
+	}
+	if p := watching.Policy(SourceTownStatus); p.Interval != 5*time.Second {
+		t.Errorf("expected town status unchanged, got %+v", p)
+	}
+	if due := watching.due(lastRun, start.Add(time.Hour)); slices.Contains(due, SourcePlugins) {
+		t.Error("expected plugins to reload only on change while watching")
+	}
+	if p := watching.Policy(SourcePlugins); !p.OnChange || p.StaleAfter != 0 {
+		t.Errorf("expected plugins never stale while watching, got %+v", p)
+	}
+}
+
+func TestStoreRefreshDue(t *testing.T) {
+	fixtures := testutil.NewFixtures()
+	mock := testutil.NewMockRunner()
+	mock.On([]string{"gt", "status", "--json"}, fixtures.TownStatusJSON(), nil, nil)
+	mock.On([]string{"gt", "mq", "list"}, []byte("[]"), nil, nil)
+
+	loader := NewLoaderWithRunner("/tmp/town", mock)
+	loader.FS = testutil.NewMemFS()
+	loader.Schedule = Schedule{
+		Interval: time.Hour,
+		Sources:  map[string]SourcePolicy{SourcePolecats: {Interval: time.Nanosecond}},
+	}
+	store := NewStoreWithLoader(loader)
+	first := store.RefreshDue(t.Context())
+	if first == nil || !first.SourceLoaded(SourceTownStatus) {
+		t.Fatal("expected the first refresh to load everything")
+	}
+
+	mock.Reset()
+	mock.On([]string{"gt", "polecat", "list"}, fixtures.PolecatsJSON(), nil, nil)
+	snap := store.RefreshDue(t.Context())
+	if len(snap.Polecats) != 3 {
+		t.Errorf("expected polecats reloaded, got %d", len(snap.Polecats))
+	}
+	if mock.CalledWith([]string{"gt", "status"}) || mock.CalledWith([]string{"gt", "doctor"}) {
+		t.Errorf("expected only polecats to reload, got %v", mock.Calls())
+	}
+	if snap.Town == nil || snap.Timings[SourceTownStatus] != first.Timings[SourceTownStatus] {
+		t.Error("expected the town status and its timing kept")
+	}
+	if snap.Timings[SourcePolecats].Started.Before(first.LoadedAt) {
+		t.Error("expected a new timing for polecats")
+	}
+}
+
+// hangingRunner runs nothing and waits for its context to end.
+type hangingRunner struct{}
+
+func (hangingRunner) Exec(ctx context.Context, _ string, _ ...string) ([]byte, []byte, error) {
+	<-ctx.Done()
+	return nil, nil, ctx.Err()
+}
+
+func TestLoadSourcesTimesOutSlowSources(t *testing.T) {
+	loader := NewLoaderWithRunner("/tmp/town", hangingRunner{})
+	loader.FS = testutil.NewMemFS()
+	loader.Schedule = Schedule{Sources: map[string]SourcePolicy{SourceDoctor: {Timeout: 20 * time.Millisecond}}}
+	prev := &Snapshot{}
+
+	done := make(chan *Snapshot)
+	go func() { done <- loader.LoadSources(t.Context(), prev, SourceDoctor) }()
+	select {
+	case snap := <-done:
+		if len(snap.LoadErrors) != 1 || snap.LoadErrors[0].Source != SourceDoctor {
+			t.Errorf("expected doctor to time out, got %+v", snap.LoadErrors)
+		}
+		if timing := snap.Timings[SourceDoctor]; timing.Duration < 20*time.Millisecond {
+			t.Errorf("expected the doctor load timed, got %+v", timing)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the doctor timeout to end the load")
+	}
+}
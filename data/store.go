@@ -8,11 +8,16 @@ import (
 
 // Store maintains a cached snapshot of town data with refresh capability.
 type Store struct {
-	loader   *Loader
-	mu       sync.RWMutex
-	snapshot *Snapshot
-
-	// RefreshInterval is how often to auto-refresh. Zero disables auto-refresh.
+	loader    *Loader
+	mu        sync.RWMutex
+	snapshot  *Snapshot
+	refreshMu sync.Mutex
+	lastRun   map[string]time.Time // When each source last started loading
+	loading   *load                // The refresh in flight, if any
+
+	// RefreshInterval is how often sources without their own interval
+	// auto-refresh, unless the loader has a Schedule. Zero disables
+	// auto-refresh.
 	RefreshInterval time.Duration
 
 	// OnRefresh is called after each refresh with the new snapshot.
@@ -21,24 +26,46 @@ type Store struct {
 	// OnError is called when refresh fails entirely (no partial data).
 	OnError func(error)
 
+	// History, if set, records every refreshed snapshot to disk. Set it
+	// with SetHistory once refreshes may be running.
+	History *History
+
 	cancelFunc context.CancelFunc
 }
 
+// load is a refresh in flight.
+type load struct {
+	sources    map[string]bool
+	cancel     context.CancelFunc
+	superseded bool
+}
+
 // NewStore creates a new data store.
 func NewStore(townRoot string) *Store {
-	return &Store{
-		loader: NewLoader(townRoot),
-	}
+	return NewStoreWithLoader(NewLoader(townRoot))
 }
 
 // NewStoreWithLoader creates a new data store with a custom loader.
-// Useful for testing with mock loaders.
+// Useful for testing with mock loaders. The loader records command
+// latency into the store's CommandStats and stops loading failing sources
+// with default Breakers.
 func NewStoreWithLoader(loader *Loader) *Store {
+	if loader.Stats == nil {
+		loader.Stats = NewCommandStats()
+	}
+	if loader.Breakers == nil {
+		loader.Breakers = NewBreakers()
+	}
 	return &Store{
 		loader: loader,
 	}
 }
 
+// CommandStats returns the latency of the commands the store has run.
+func (s *Store) CommandStats() *CommandStats {
+	return s.loader.Stats
+}
+
 // Snapshot returns the current cached snapshot.
 // Returns nil if no data has been loaded.
 func (s *Store) Snapshot() *Snapshot {
@@ -49,7 +76,72 @@ func (s *Store) Snapshot() *Snapshot {
 
 // Refresh loads fresh data from all sources.
 func (s *Store) Refresh(ctx context.Context) *Snapshot {
-	snap := s.loader.LoadAll(ctx)
+	return s.RefreshSources(ctx, AllSources...)
+}
+
+// RefreshSources reloads only the given sources, merging them into the
+// cached snapshot. With nothing cached yet it loads every source. Loads
+// run one at a time so a partial reload always builds on the latest
+// snapshot. A refresh covering every source of the one in flight
+// supersedes it: the old one is cancelled and its result dropped, and it
+// returns the cached snapshot instead.
+func (s *Store) RefreshSources(ctx context.Context, sources ...string) *Snapshot {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	current := &load{cancel: cancel}
+
+	s.mu.Lock()
+	if s.snapshot == nil {
+		sources = AllSources
+	}
+	current.sources = expandSources(sources)
+	if s.loading != nil && covers(current.sources, s.loading.sources) {
+		s.loading.superseded = true
+		s.loading.cancel()
+	}
+	s.mu.Unlock()
+
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	// Load from a copy so SetLifecycleEvents can't race an in-flight refresh
+	s.mu.Lock()
+	loader := *s.loader
+	history := s.History
+	prev := s.snapshot
+	if prev == nil {
+		sources = AllSources
+		current.sources = expandSources(sources)
+	}
+	if s.lastRun == nil {
+		s.lastRun = make(map[string]time.Time)
+	}
+	started := time.Now()
+	for source := range current.sources {
+		s.lastRun[source] = started
+	}
+	s.loading = current
+	s.mu.Unlock()
+	snap := loader.LoadSources(ctx, prev, sources...)
+
+	s.mu.Lock()
+	s.loading = nil
+	superseded := current.superseded
+	s.mu.Unlock()
+	if superseded {
+		return s.Snapshot()
+	}
+
+	if history != nil {
+		if err := history.Record(snap); err != nil {
+			snap.LoadErrors = append(snap.LoadErrors, LoadError{
+				Source:     "history",
+				Command:    "write " + history.Dir,
+				Error:      err.Error(),
+				OccurredAt: snap.LoadedAt,
+			})
+		}
+	}
 
 	s.mu.Lock()
 	s.snapshot = snap
@@ -62,6 +154,85 @@ func (s *Store) Refresh(ctx context.Context) *Snapshot {
 	return snap
 }
 
+// Retry closes the breaker for key (a source, or "merge_queue_<rig>") and
+// reloads its source now.
+func (s *Store) Retry(ctx context.Context, key string) *Snapshot {
+	s.loader.Breakers.Reset(key)
+	return s.RefreshSources(ctx, BreakerSource(key))
+}
+
+// covers reports whether sources includes every source in other.
+func covers(sources, other map[string]bool) bool {
+	for source := range other {
+		if !sources[source] {
+			return false
+		}
+	}
+	return true
+}
+
+// RefreshDue reloads the sources whose interval has passed, if any, and
+// returns the cached snapshot.
+func (s *Store) RefreshDue(ctx context.Context) *Snapshot {
+	if due := s.Due(time.Now()); len(due) > 0 {
+		return s.RefreshSources(ctx, due...)
+	}
+	return s.Snapshot()
+}
+
+// Due returns the sources whose reload interval has passed at now.
+// Sources that never loaded are always due.
+func (s *Store) Due(now time.Time) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.schedule().due(s.lastRun, now)
+}
+
+// NextDue returns when the next source falls due, now if one already is.
+func (s *Store) NextDue(now time.Time) time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.schedule().nextDue(s.lastRun, now)
+}
+
+// Schedule returns the source schedule in effect.
+func (s *Store) Schedule() Schedule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.schedule()
+}
+
+// SetSchedule changes how often each source reloads and its timeouts.
+func (s *Store) SetSchedule(schedule Schedule) {
+	s.mu.Lock()
+	s.loader.Schedule = schedule
+	s.mu.Unlock()
+}
+
+// schedule returns the loader's schedule, or the default one around
+// RefreshInterval. Callers hold s.mu.
+func (s *Store) schedule() Schedule {
+	if s.loader.Schedule.Interval > 0 || s.loader.Schedule.Sources != nil {
+		return s.loader.Schedule
+	}
+	return DefaultSchedule(s.RefreshInterval)
+}
+
+// SetHistory records snapshots from the next refresh on to h, or stops
+// recording when h is nil.
+func (s *Store) SetHistory(h *History) {
+	s.mu.Lock()
+	s.History = h
+	s.mu.Unlock()
+}
+
+// SetLifecycleEvents changes how many lifecycle events later refreshes load.
+func (s *Store) SetLifecycleEvents(n int) {
+	s.mu.Lock()
+	s.loader.LifecycleEvents = n
+	s.mu.Unlock()
+}
+
 // StartAutoRefresh begins periodic background refreshes.
 // Call Stop() to stop the refresh loop.
 func (s *Store) StartAutoRefresh(ctx context.Context) {
@@ -75,20 +246,29 @@ func (s *Store) StartAutoRefresh(ctx context.Context) {
 	s.Refresh(ctx)
 
 	go func() {
-		ticker := time.NewTicker(s.RefreshInterval)
-		defer ticker.Stop()
-
 		for {
+			timer := time.NewTimer(s.untilDue())
 			select {
 			case <-ctx.Done():
+				timer.Stop()
 				return
-			case <-ticker.C:
-				s.Refresh(ctx)
+			case <-timer.C:
+				s.RefreshDue(ctx)
 			}
 		}
 	}()
 }
 
+// minRefreshWait keeps an auto-refresh loop from spinning when sources
+// fall due back to back.
+const minRefreshWait = time.Second
+
+// untilDue returns how long to wait for the next source to fall due.
+func (s *Store) untilDue() time.Duration {
+	now := time.Now()
+	return max(s.NextDue(now).Sub(now), minRefreshWait)
+}
+
 // Stop halts auto-refresh.
 func (s *Store) Stop() {
 	if s.cancelFunc != nil {
@@ -0,0 +1,75 @@
+package data
+
+import (
+	"context"
+	"sync"
+)
+
+// Towns runs one Store per Gas Town root so several towns can be watched
+// side by side.
+type Towns struct {
+	mu    sync.RWMutex
+	towns []*TownStore
+}
+
+// TownStore is a registered town and the store that loads it.
+type TownStore struct {
+	Name  string
+	Root  string
+	Store *Store
+}
+
+// NewTowns creates an empty town set.
+func NewTowns() *Towns {
+	return &Towns{}
+}
+
+// Set replaces the registered towns, in order. A town given without a
+// store keeps the store it already had under the same root, so its last
+// snapshot survives, or gets a new one.
+func (t *Towns) Set(towns []TownStore) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	existing := make(map[string]*Store, len(t.towns))
+	for _, ts := range t.towns {
+		existing[ts.Root] = ts.Store
+	}
+	next := make([]*TownStore, 0, len(towns))
+	for _, ts := range towns {
+		if ts.Store == nil {
+			ts.Store = existing[ts.Root]
+		}
+		if ts.Store == nil {
+			ts.Store = NewStore(ts.Root)
+		}
+		next = append(next, &ts)
+	}
+	t.towns = next
+}
+
+// List returns the registered towns in order.
+func (t *Towns) List() []*TownStore {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return append([]*TownStore(nil), t.towns...)
+}
+
+// Refresh loads every town concurrently and waits for all of them.
+func (t *Towns) Refresh(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, ts := range t.List() {
+		wg.Add(1)
+		go func(s *Store) {
+			defer wg.Done()
+			s.Refresh(ctx)
+		}(ts.Store)
+	}
+	wg.Wait()
+}
+
+// QualifyAddress prefixes an agent or item address with its town, e.g.
+// "web:perch/polecats/nux".
+func QualifyAddress(town, address string) string {
+	return town + ":" + address
+}
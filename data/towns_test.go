@@ -0,0 +1,54 @@
+package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+func TestTownsRefreshesEveryTown(t *testing.T) {
+	fixtures := testutil.NewFixtures()
+	towns := NewTowns()
+	mocks := map[string]*testutil.MockRunner{}
+	var set []TownStore
+	for _, name := range []string{"web", "infra"} {
+		mock := testutil.NewMockRunner()
+		mock.On([]string{"gt", "status", "--json"}, fixtures.TownStatusJSON(), nil, nil)
+		mocks[name] = mock
+		root := "/srv/" + name
+		set = append(set, TownStore{Name: name, Root: root, Store: NewStoreWithLoader(NewLoaderWithRunner(root, mock))})
+	}
+	towns.Set(set)
+	towns.Refresh(context.Background())
+
+	list := towns.List()
+	if len(list) != 2 || list[0].Name != "web" || list[1].Name != "infra" {
+		t.Fatalf("expected web then infra, got %+v", list)
+	}
+	for _, ts := range list {
+		if snap := ts.Store.Snapshot(); snap == nil || snap.Town == nil {
+			t.Errorf("expected %s to be loaded", ts.Name)
+		}
+		if !mocks[ts.Name].CalledWith([]string{"gt", "status", "--json"}) {
+			t.Errorf("expected %s to run gt status", ts.Name)
+		}
+	}
+
+	// Towns that stay keep their store; new ones get their own
+	web := list[0].Store
+	towns.Set([]TownStore{{Name: "web", Root: "/srv/web"}, {Name: "ops", Root: "/srv/ops"}})
+	list = towns.List()
+	if list[0].Store != web {
+		t.Error("expected web to keep its store")
+	}
+	if list[1].Store == nil || list[1].Store == web {
+		t.Error("expected ops to get a new store")
+	}
+}
+
+func TestQualifyAddress(t *testing.T) {
+	if got := QualifyAddress("web", "perch/polecats/nux"); got != "web:perch/polecats/nux" {
+		t.Errorf("unexpected address %q", got)
+	}
+}
@@ -162,16 +162,39 @@ type ConvoyStatus struct {
 // MergeRequest represents an item in the merge queue.
 // Loaded via: gt mq list <rig> --json
 type MergeRequest struct {
-	ID           string `json:"id"`
-	Title        string `json:"title"`
-	Status       string `json:"status"`
-	Worker       string `json:"worker"`
-	Branch       string `json:"branch"`
-	Priority     int    `json:"priority"`
-	HasConflicts bool   `json:"has_conflicts"`
-	NeedsRebase  bool   `json:"needs_rebase"`
-	ConflictInfo string `json:"conflict_info,omitempty"`
-	LastChecked  string `json:"last_checked,omitempty"`
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	Status       string    `json:"status"`
+	Worker       string    `json:"worker"`
+	Branch       string    `json:"branch"`
+	Priority     int       `json:"priority"`
+	HasConflicts bool      `json:"has_conflicts"`
+	NeedsRebase  bool      `json:"needs_rebase"`
+	ConflictInfo string    `json:"conflict_info,omitempty"`
+	LastChecked  string    `json:"last_checked,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+}
+
+// RefineryStalled reports whether a rig's refinery is stalled with MRs
+// waiting: stopped, or idle while an MR has waited longer than staleAfter.
+// A zero staleAfter counts only stopped refineries. The queue health panel
+// and notifications share it.
+func RefineryStalled(refinery Agent, mrs []MergeRequest, now time.Time, staleAfter time.Duration) bool {
+	if len(mrs) == 0 {
+		return false
+	}
+	if !refinery.Running {
+		return true
+	}
+	if refinery.HasWork || staleAfter <= 0 {
+		return false
+	}
+	for _, mr := range mrs {
+		if !mr.CreatedAt.IsZero() && now.Sub(mr.CreatedAt) > staleAfter {
+			return true
+		}
+	}
+	return false
 }
 
 // Worktree represents a cross-rig git worktree.
@@ -423,15 +446,27 @@ func (e *ValidationError) Error() string {
 // LoadError represents a structured error from loading a data source.
 // This provides actionable context about what failed and how to fix it.
 type LoadError struct {
-	Source     string    `json:"source"`      // e.g., "town_status", "merge_queue", "mail", "convoys"
-	Command    string    `json:"command"`     // e.g., "gt status --json --fast"
-	Error      string    `json:"error"`       // The error message
-	Stderr     string    `json:"stderr"`      // Raw stderr output (if available)
-	OccurredAt time.Time `json:"occurred_at"` // When this error happened
+	Source     string         `json:"source"`            // e.g., "town_status", "merge_queue", "mail", "convoys"
+	Command    string         `json:"command"`           // e.g., "gt status --json --fast"
+	Error      string         `json:"error"`             // The error message
+	Stderr     string         `json:"stderr"`            // Raw stderr output (if available)
+	OccurredAt time.Time      `json:"occurred_at"`       // When this error happened
+	Breaker    *BreakerStatus `json:"breaker,omitempty"` // Source's circuit breaker after this error (nil if not tracked)
 }
 
-// SuggestedAction returns a suggested action for this error.
+// SuggestedAction returns a suggested action for this error. While the
+// source's breaker is open it says when loading resumes.
 func (e *LoadError) SuggestedAction() string {
+	action := e.sourceSuggestedAction()
+	if e.Breaker != nil && e.Breaker.State == BreakerOpen {
+		return fmt.Sprintf("Paused after %d failures; retrying at %s, or retry now. %s",
+			e.Breaker.Failures, e.Breaker.RetryAt.Format("15:04:05"), action)
+	}
+	return action
+}
+
+// sourceSuggestedAction returns how to fix a failure of e's source.
+func (e *LoadError) sourceSuggestedAction() string {
 	switch e.Source {
 	case "town_status":
 		return "Check if gt is installed and $GT_ROOT is set correctly"
@@ -451,6 +486,8 @@ func (e *LoadError) SuggestedAction() string {
 		return "Run 'gt doctor' manually to see full output"
 	case "worktrees":
 		return "Check crew directories exist in the rig"
+	case "history":
+		return "Check that ~/.perch/history is writable"
 	default:
 		return "Check command manually: " + e.Command
 	}
@@ -519,6 +556,10 @@ func (e *LoadError) SourceLabel() string {
 		return "Health Checks"
 	case "worktrees":
 		return "Worktrees"
+	case "history":
+		return "Snapshot History"
+	case "patrol_formulas":
+		return "Patrol Formulas"
 	default:
 		return e.Source
 	}
@@ -536,11 +577,13 @@ const (
 // DoctorCheck represents a single health check from gt doctor.
 // Loaded by parsing gt doctor output (no JSON available).
 type DoctorCheck struct {
-	Name       string      `json:"name"`
-	Status     CheckStatus `json:"status"`
-	Message    string      `json:"message"`
-	Details    []string    `json:"details,omitempty"`
-	SuggestFix string      `json:"suggest_fix,omitempty"`
+	Name       string        `json:"name"`
+	Status     CheckStatus   `json:"status"`
+	Message    string        `json:"message"`
+	Details    []string      `json:"details,omitempty"`
+	SuggestFix string        `json:"suggest_fix,omitempty"`
+	FixCommand []string      `json:"fix_command,omitempty"` // gt/bd command SuggestFix runs (nil if none)
+	Duration   time.Duration `json:"duration,omitempty"`    // How long the check took (zero if not reported)
 }
 
 // DoctorReport represents the full gt doctor output.
@@ -553,6 +596,19 @@ type DoctorReport struct {
 	LoadedAt     time.Time     `json:"loaded_at"`
 }
 
+// Check returns the check with the given name.
+func (r *DoctorReport) Check(name string) (DoctorCheck, bool) {
+	if r == nil {
+		return DoctorCheck{}, false
+	}
+	for _, c := range r.Checks {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return DoctorCheck{}, false
+}
+
 // HasIssues returns true if there are any warnings or errors.
 func (r *DoctorReport) HasIssues() bool {
 	return r.WarningCount > 0 || r.ErrorCount > 0
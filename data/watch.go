@@ -0,0 +1,238 @@
+package data
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// watchDebounce coalesces a burst of file events, like a bd write touching
+// several database files, into one reload.
+const watchDebounce = 250 * time.Millisecond
+
+// ErrWatchUnsupported is returned by Watch when the town's files cannot be
+// watched: on platforms without inotify, or for a town read through an
+// FS such as a remote one. Callers keep polling.
+var ErrWatchUnsupported = errors.New("file watching is not supported for this town")
+
+// Watcher reports which data sources changed on disk, so they can reload
+// as soon as they change rather than on the next poll.
+type Watcher struct {
+	// Changes delivers the sources to reload, coalesced over a short
+	// window. It is closed when the watcher stops.
+	Changes <-chan []string
+
+	changes chan []string
+	root    string
+	backend watchBackend
+	once    sync.Once
+
+	mu      sync.Mutex
+	rigs    []string
+	watches map[int]watchTarget // by watch id
+	dirs    map[string]int      // watch id by directory
+}
+
+// watchBackend is the platform's file notification API.
+type watchBackend interface {
+	// add watches a directory's entries and returns its watch id.
+	add(dir string) (int, error)
+	// events delivers events until the backend is closed.
+	events() <-chan watchEvent
+	close() error
+}
+
+// watchEvent is a change to an entry of a watched directory.
+type watchEvent struct {
+	id       int    // Watch id of the directory
+	name     string // Entry that changed, empty for the directory itself
+	gone     bool   // The watch was removed, e.g. the directory was deleted
+	overflow bool   // Events were dropped; anything may have changed
+}
+
+// watchTarget is a watched directory and the sources a change in it
+// reloads.
+type watchTarget struct {
+	dir     string
+	file    string // Only changes to this entry count; empty for any
+	sources []string
+}
+
+// beadsSources are the sources stored in a beads database.
+var beadsSources = []string{
+	SourceIssues, SourceHookedIssues, SourceConvoys, SourceClosedConvoys,
+	SourceMail, SourceMergeQueue, SourceRoutes,
+}
+
+// WatchedSources are the sources kept entirely in files a Watcher
+// watches, so it sees every change to them. Town status and polecats also
+// depend on tmux sessions, and doctor on the whole system.
+var WatchedSources = []string{
+	SourceConvoys, SourceClosedConvoys, SourceConvoyStatuses, SourceIssues,
+	SourceMail, SourceHookedIssues, SourceLifecycle, SourceMergeQueue,
+	SourceWorktrees, SourcePlugins, SourceRoutes, SourcePatrolFormulas,
+}
+
+// Watch starts watching the town's files for changes to the given rigs:
+// logs/town.log, the .beads databases, mayor/rigs.json, plugin directories
+// and crew worktrees. Close the watcher when done.
+func (l *Loader) Watch(rigs []string) (*Watcher, error) {
+	if l.FS != nil {
+		return nil, ErrWatchUnsupported
+	}
+	backend, err := newWatchBackend()
+	if err != nil {
+		return nil, err
+	}
+	changes := make(chan []string)
+	w := &Watcher{
+		Changes: changes,
+		changes: changes,
+		root:    l.TownRoot,
+		backend: backend,
+		rigs:    rigs,
+		watches: make(map[int]watchTarget),
+		dirs:    make(map[string]int),
+	}
+	w.sync()
+	go w.run()
+	return w, nil
+}
+
+// SetRigs updates the rigs whose directories are watched.
+func (w *Watcher) SetRigs(rigs []string) {
+	w.mu.Lock()
+	same := slices.Equal(w.rigs, rigs)
+	w.rigs = rigs
+	w.mu.Unlock()
+	if !same {
+		w.sync()
+	}
+}
+
+// Close stops the watcher.
+func (w *Watcher) Close() {
+	w.once.Do(func() { w.backend.close() })
+}
+
+// run coalesces events into batches of sources until the backend closes.
+// A batch waits for the reader rather than blocking the events.
+func (w *Watcher) run() {
+	defer close(w.changes)
+
+	pending := make(map[string]bool)
+	var ready []string
+	var out chan []string
+	var debounce <-chan time.Time
+	for {
+		select {
+		case ev, ok := <-w.backend.events():
+			if !ok {
+				return
+			}
+			for _, source := range w.sourcesFor(ev) {
+				pending[source] = true
+			}
+			if len(pending) > 0 && debounce == nil {
+				debounce = time.After(watchDebounce)
+			}
+
+		case <-debounce:
+			debounce = nil
+			for _, source := range ready {
+				pending[source] = true
+			}
+			ready = ready[:0]
+			for source := range pending {
+				ready = append(ready, source)
+			}
+			sort.Strings(ready)
+			pending = make(map[string]bool)
+			out = w.changes
+			// New plugin and worktree directories need watches of their own
+			w.sync()
+
+		case out <- ready:
+			ready = nil
+			out = nil
+		}
+	}
+}
+
+// sourcesFor returns the sources an event affects.
+func (w *Watcher) sourcesFor(ev watchEvent) []string {
+	if ev.overflow {
+		return AllSources
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	target, ok := w.watches[ev.id]
+	if !ok {
+		return nil
+	}
+	if ev.gone {
+		delete(w.watches, ev.id)
+		delete(w.dirs, target.dir)
+		return target.sources
+	}
+	if target.file != "" && ev.name != target.file {
+		return nil
+	}
+	// SQLite touches its shared-memory file on every read, including ours
+	if strings.HasSuffix(ev.name, "-shm") || strings.HasSuffix(ev.name, ".lock") {
+		return nil
+	}
+	return target.sources
+}
+
+// sync adds watches for target directories that exist and are not yet
+// watched. Missing ones are retried on the next sync.
+func (w *Watcher) sync() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, target := range watchTargets(w.root, w.rigs) {
+		if _, ok := w.dirs[target.dir]; ok {
+			continue
+		}
+		id, err := w.backend.add(target.dir)
+		if err != nil {
+			continue
+		}
+		w.watches[id] = target
+		w.dirs[target.dir] = id
+	}
+}
+
+// watchTargets lists the directories to watch in a town.
+func watchTargets(root string, rigs []string) []watchTarget {
+	targets := []watchTarget{
+		{dir: filepath.Join(root, "logs"), file: "town.log", sources: []string{SourceLifecycle, SourceTownStatus, SourcePolecats}},
+		{dir: filepath.Join(root, "mayor"), file: "rigs.json", sources: []string{SourceTownStatus, SourceMergeQueue, SourceWorktrees, SourcePlugins}},
+		{dir: filepath.Join(root, ".beads"), sources: beadsSources},
+		{dir: filepath.Join(root, ".beads", "formulas"), sources: []string{SourcePatrolFormulas}},
+	}
+	targets = append(targets, subdirTargets(filepath.Join(root, "plugins"), SourcePlugins)...)
+	for _, rig := range rigs {
+		targets = append(targets, watchTarget{dir: filepath.Join(root, rig, ".beads"), sources: beadsSources})
+		targets = append(targets, subdirTargets(filepath.Join(root, rig, "plugins"), SourcePlugins)...)
+		targets = append(targets, subdirTargets(filepath.Join(root, rig, "crew"), SourceWorktrees)...)
+	}
+	return targets
+}
+
+// subdirTargets watches dir and each directory in it for source.
+func subdirTargets(dir, source string) []watchTarget {
+	sources := []string{source}
+	targets := []watchTarget{{dir: dir, sources: sources}}
+	entries, _ := osFS{}.ReadDir(dir)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			targets = append(targets, watchTarget{dir: filepath.Join(dir, entry.Name()), sources: sources})
+		}
+	}
+	return targets
+}
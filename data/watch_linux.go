@@ -0,0 +1,69 @@
+//go:build linux
+
+package data
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// inotifyMask is the set of changes that reload a source.
+const inotifyMask = syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE | syscall.IN_CREATE |
+	syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_ONLYDIR
+
+// inotify watches directories with Linux inotify.
+type inotify struct {
+	fd   int
+	file *os.File
+	evs  chan watchEvent
+}
+
+func newWatchBackend() (watchBackend, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify: %w", err)
+	}
+	// A non-blocking file goes through the runtime poller, so Close
+	// interrupts a pending Read
+	in := &inotify{fd: fd, file: os.NewFile(uintptr(fd), "inotify"), evs: make(chan watchEvent, 64)}
+	go in.read()
+	return in, nil
+}
+
+func (in *inotify) add(dir string) (int, error) {
+	return syscall.InotifyAddWatch(in.fd, dir, inotifyMask)
+}
+
+func (in *inotify) events() <-chan watchEvent {
+	return in.evs
+}
+
+func (in *inotify) close() error {
+	return in.file.Close()
+}
+
+// read decodes events until the file is closed.
+func (in *inotify) read() {
+	defer close(in.evs)
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := in.file.Read(buf)
+		if err != nil {
+			return
+		}
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			raw := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			start := offset + syscall.SizeofInotifyEvent
+			offset = start + int(raw.Len)
+			in.evs <- watchEvent{
+				id:       int(raw.Wd),
+				name:     strings.TrimRight(string(buf[start:offset]), "\x00"),
+				gone:     raw.Mask&syscall.IN_IGNORED != 0,
+				overflow: raw.Mask&syscall.IN_Q_OVERFLOW != 0,
+			}
+		}
+	}
+}
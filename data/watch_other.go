@@ -0,0 +1,7 @@
+//go:build !linux
+
+package data
+
+func newWatchBackend() (watchBackend, error) {
+	return nil, ErrWatchUnsupported
+}
@@ -0,0 +1,107 @@
+package data
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+func TestWatcherReportsChangedSources(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"logs", "mayor", "perch/crew"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w, err := NewLoader(root).Watch([]string{"perch"})
+	if errors.Is(err, ErrWatchUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	next := func() []string {
+		t.Helper()
+		select {
+		case sources := <-w.Changes:
+			return sources
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a change")
+			return nil
+		}
+	}
+
+	os.WriteFile(filepath.Join(root, "logs", "town.log"), []byte("2026-01-02 07:09:03 [done] perch/nux completed pe-1\n"), 0644)
+	if got := next(); !slices.Equal(got, []string{SourceLifecycle, SourcePolecats, SourceTownStatus}) {
+		t.Errorf("expected the town.log sources, got %v", got)
+	}
+
+	// Other files in a filtered directory are ignored
+	os.WriteFile(filepath.Join(root, "mayor", "notes.md"), []byte("x"), 0644)
+	// New worktrees are picked up, and their changes too
+	os.Mkdir(filepath.Join(root, "perch", "crew", "web-joe"), 0755)
+	if got := next(); !slices.Equal(got, []string{SourceWorktrees}) {
+		t.Errorf("expected worktrees only, got %v", got)
+	}
+	os.WriteFile(filepath.Join(root, "perch", "crew", "web-joe", "main.go"), []byte("package main\n"), 0644)
+	if got := next(); !slices.Equal(got, []string{SourceWorktrees}) {
+		t.Errorf("expected worktrees, got %v", got)
+	}
+
+	w.Close()
+	if _, ok := <-w.Changes; ok {
+		t.Error("expected Changes to close")
+	}
+}
+
+func TestWatchNeedsLocalFiles(t *testing.T) {
+	loader := &Loader{TownRoot: "/town", FS: testutil.NewMemFS()}
+	if _, err := loader.Watch(nil); !errors.Is(err, ErrWatchUnsupported) {
+		t.Errorf("expected ErrWatchUnsupported, got %v", err)
+	}
+}
+
+func TestLoadSourcesKeepsOtherSources(t *testing.T) {
+	fixtures := testutil.NewFixtures()
+	mock := testutil.NewMockRunner()
+	mock.On([]string{"gt", "status", "--json"}, fixtures.TownStatusJSON(), nil, nil)
+	mock.On([]string{"gt", "polecat", "list"}, fixtures.PolecatsJSON(), nil, nil)
+	mock.On([]string{"bd", "list", "--json", "--limit", "0"}, nil, []byte("database locked"), errors.New("exit status 1"))
+	mock.On([]string{"gt", "mq", "list"}, []byte("[]"), nil, nil)
+
+	loader := NewLoaderWithRunner("/tmp/town", mock)
+	loader.FS = testutil.NewMemFS()
+	prev := loader.LoadAll(t.Context())
+	if prev.SourceLoaded(SourceIssues) || len(prev.LoadErrors) == 0 {
+		t.Fatalf("expected issues to fail, got %+v", prev.LoadErrors)
+	}
+
+	mock.Reset()
+	mock.On([]string{"bd", "list", "--json", "--limit", "0"}, fixtures.IssuesJSON(), nil, nil)
+	snap := loader.LoadSources(t.Context(), prev, SourceIssues)
+
+	if len(snap.Issues) != 3 || !snap.SourceLoaded(SourceIssues) {
+		t.Errorf("expected issues reloaded, got %d", len(snap.Issues))
+	}
+	for _, e := range snap.LoadErrors {
+		if e.Source == SourceIssues {
+			t.Errorf("expected the issues error cleared, got %+v", e)
+		}
+	}
+	if len(snap.Polecats) != 3 || snap.LastSuccess[SourcePolecats] != prev.LastSuccess[SourcePolecats] {
+		t.Error("expected polecats kept from the previous snapshot")
+	}
+	if mock.CalledWith([]string{"gt", "polecat", "list"}) || mock.CalledWith([]string{"gt", "status"}) {
+		t.Errorf("expected only issues to load, got %v", mock.Calls())
+	}
+	if prev.Issues != nil {
+		t.Error("expected the previous snapshot left alone")
+	}
+}
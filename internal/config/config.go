@@ -0,0 +1,523 @@
+// Package config loads perch's user configuration file.
+//
+// Settings are layered: built-in defaults, then the config file
+// (~/.config/perch/config.toml), then environment variables, then
+// command-line flags. A missing file is not an error.
+//
+//	town_root = "~/gt"
+//
+//	[refresh]
+//	interval = "10s"         # TUI auto-refresh of sources without their own
+//	store_interval = "5s"    # Reload of town status and polecats
+//	load_timeout = "30s"     # Bound on a single full load
+//	watch = true             # Reload sources as their files change
+//	fallback_interval = "1m" # Polling of watched sources
+//
+//	[data]
+//	lifecycle_events = 100  # Events read from town.log
+//
+//	[activity]
+//	max_events = 50         # Activity feed length
+//
+//	[queue]
+//	stale_mr_threshold = "1h"
+//
+//	[notify]
+//	methods = ["osc9", "bell"]
+//	command = ["notify-send", "-u", "critical"]
+//	events = ["crash", "p0_bead"]
+//	quiet_hours = "22:00-07:00"
+//
+//	[keys]                  # Rebind TUI actions (see the TUI help)
+//	refresh = "f5"
+//	quit = ["q", "ctrl+q"]
+//	archive_all = []        # Unbind
+//
+//	[[nudges]]              # Replaces the preset nudge list
+//	label = "Check mail"
+//	message = "Check your mail and respond to any pending items."
+//
+//	[[towns]]               # Town registry for switching and the overview
+//	name = "web"
+//	root = "~/gt-web"
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/perch/internal/notify"
+)
+
+// Config holds every user-tunable setting.
+type Config struct {
+	// Path is the file the config was loaded from, empty if none was found.
+	Path string
+
+	// TownRoot is the Gas Town workspace. Changing it requires a restart.
+	TownRoot string
+
+	// Remote is the ssh destination (user@host) of a town on another
+	// machine, with TownRoot the path there. It is set by --remote, not the
+	// file, and changing it requires a restart.
+	Remote string
+
+	RefreshInterval      time.Duration // TUI auto-refresh interval
+	StoreRefreshInterval time.Duration // Reload interval of town status, hooked issues and polecats
+	LoadTimeout          time.Duration // Bound on a single full load
+
+	// Watch reloads data sources as their files change. Sources kept
+	// entirely in files then poll only every WatchFallbackInterval, as a
+	// backstop. Remote towns and platforms without inotify keep polling.
+	Watch                 bool
+	WatchFallbackInterval time.Duration
+
+	LifecycleEvents   int           // Lifecycle log events loaded from town.log
+	ActivityMaxEvents int           // Activity feed length
+	StaleMRThreshold  time.Duration // MR age at which an idle refinery counts as stalled
+
+	// Nudges replace the built-in preset nudge messages when non-empty.
+	// The TUI always adds a custom entry.
+	Nudges []Nudge
+
+	// Towns is the town registry: named Gas Town roots the TUI can switch
+	// between and watch side by side. TownRoot need not be one of them.
+	Towns []Town
+
+	Notify notify.Config
+
+	// Keys rebinds TUI actions: action name to keys. An empty list unbinds.
+	// Action names and conflicts are checked by the TUI's keymap.
+	Keys map[string][]string
+
+	// DryRun starts the TUI previewing action commands instead of running
+	// them. It is set by --dry-run, not the file, and can be toggled at
+	// runtime.
+	DryRun bool
+
+	// ReadOnly starts the TUI as an observer: every action is disabled and
+	// refused by the action runner. It is set by --read-only, not the file.
+	ReadOnly bool
+}
+
+// Nudge is a preset nudge message.
+type Nudge struct {
+	Label   string
+	Message string
+}
+
+// Town is a named Gas Town root in the town registry.
+type Town struct {
+	Name string
+	Root string
+}
+
+// Default returns the built-in settings.
+func Default() Config {
+	return Config{
+		TownRoot:              defaultTownRoot(),
+		RefreshInterval:       10 * time.Second,
+		StoreRefreshInterval:  5 * time.Second,
+		LoadTimeout:           30 * time.Second,
+		Watch:                 true,
+		WatchFallbackInterval: time.Minute,
+		LifecycleEvents:       100,
+		ActivityMaxEvents:     50,
+		StaleMRThreshold:      time.Hour,
+	}
+}
+
+// defaultTownRoot returns ~/gt.
+func defaultTownRoot() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "/tmp/gt"
+	}
+	return filepath.Join(home, "gt")
+}
+
+// DefaultPath returns $XDG_CONFIG_HOME/perch/config.toml, falling back to
+// ~/.config/perch/config.toml.
+func DefaultPath() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "perch", "config.toml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "perch", "config.toml"), nil
+}
+
+// Load returns the defaults overlaid with the file at path.
+// A missing file yields the defaults unless required is set.
+func Load(path string, required bool) (Config, error) {
+	cfg := Default()
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	defer f.Close()
+
+	doc, err := parseTOML(f)
+	if err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.apply(doc); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.Path = path
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ApplyEnv overrides settings from environment variables: GT_ROOT for the
+// town root and the PERCH_NOTIFY* variables for notifications.
+func (c *Config) ApplyEnv(getenv func(string) string) error {
+	if root := getenv("GT_ROOT"); root != "" {
+		c.TownRoot = root
+	}
+	return c.Notify.ApplyEnv(getenv)
+}
+
+// Validate checks that every setting is usable.
+func (c Config) Validate() error {
+	var problems []string
+	positive := func(name string, d time.Duration) {
+		if d <= 0 {
+			problems = append(problems, fmt.Sprintf("%s must be positive, got %s", name, d))
+		}
+	}
+	positive("refresh.interval", c.RefreshInterval)
+	positive("refresh.store_interval", c.StoreRefreshInterval)
+	positive("refresh.load_timeout", c.LoadTimeout)
+	positive("refresh.fallback_interval", c.WatchFallbackInterval)
+	positive("queue.stale_mr_threshold", c.StaleMRThreshold)
+	if c.LifecycleEvents < 1 {
+		problems = append(problems, fmt.Sprintf("data.lifecycle_events must be at least 1, got %d", c.LifecycleEvents))
+	}
+	if c.ActivityMaxEvents < 1 {
+		problems = append(problems, fmt.Sprintf("activity.max_events must be at least 1, got %d", c.ActivityMaxEvents))
+	}
+	for i, n := range c.Nudges {
+		if strings.TrimSpace(n.Label) == "" || strings.TrimSpace(n.Message) == "" {
+			problems = append(problems, fmt.Sprintf("nudges[%d] needs both a label and a message", i))
+		}
+	}
+	if c.TownRoot == "" {
+		problems = append(problems, "town_root must not be empty")
+	}
+	names := make(map[string]bool)
+	for i, t := range c.Towns {
+		switch {
+		case strings.TrimSpace(t.Name) == "" || strings.TrimSpace(t.Root) == "":
+			problems = append(problems, fmt.Sprintf("towns[%d] needs both a name and a root", i))
+		case names[t.Name]:
+			problems = append(problems, fmt.Sprintf("towns[%d]: duplicate town name %q", i, t.Name))
+		}
+		names[t.Name] = true
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// apply overlays a parsed document onto c, rejecting unknown keys.
+func (c *Config) apply(doc *document) error {
+	for name := range doc.tables {
+		switch name {
+		case "refresh", "data", "activity", "queue", "notify", "keys":
+		default:
+			return fmt.Errorf("unknown section [%s]", name)
+		}
+	}
+	for name := range doc.arrays {
+		switch name {
+		case "nudges", "towns":
+		default:
+			return fmt.Errorf("unknown section [[%s]]", name)
+		}
+	}
+
+	root := newSection("", doc.root)
+	root.str("town_root", &c.TownRoot)
+	if expanded, err := expandHome(c.TownRoot); err == nil {
+		c.TownRoot = expanded
+	}
+
+	refresh := newSection("refresh", doc.tables["refresh"])
+	refresh.duration("interval", &c.RefreshInterval)
+	refresh.duration("store_interval", &c.StoreRefreshInterval)
+	refresh.duration("load_timeout", &c.LoadTimeout)
+	refresh.boolean("watch", &c.Watch)
+	refresh.duration("fallback_interval", &c.WatchFallbackInterval)
+
+	dataSec := newSection("data", doc.tables["data"])
+	dataSec.integer("lifecycle_events", &c.LifecycleEvents)
+
+	activity := newSection("activity", doc.tables["activity"])
+	activity.integer("max_events", &c.ActivityMaxEvents)
+
+	queue := newSection("queue", doc.tables["queue"])
+	queue.duration("stale_mr_threshold", &c.StaleMRThreshold)
+
+	notifySec := newSection("notify", doc.tables["notify"])
+	c.applyNotify(notifySec)
+
+	keys := newSection("keys", doc.tables["keys"])
+	c.applyKeys(keys)
+
+	sections := []*section{root, refresh, dataSec, activity, queue, notifySec, keys}
+	if tables, ok := doc.arrays["nudges"]; ok {
+		c.Nudges = nil
+		for i, t := range tables {
+			s := newSection(fmt.Sprintf("nudges[%d]", i), t)
+			var n Nudge
+			s.str("label", &n.Label)
+			s.str("message", &n.Message)
+			c.Nudges = append(c.Nudges, n)
+			sections = append(sections, s)
+		}
+	}
+	if tables, ok := doc.arrays["towns"]; ok {
+		c.Towns = nil
+		for i, t := range tables {
+			s := newSection(fmt.Sprintf("towns[%d]", i), t)
+			var town Town
+			s.str("name", &town.Name)
+			if s.str("root", &town.Root) {
+				if expanded, err := expandHome(town.Root); err == nil {
+					town.Root = expanded
+				}
+			}
+			c.Towns = append(c.Towns, town)
+			sections = append(sections, s)
+		}
+	}
+
+	for _, s := range sections {
+		if err := s.done(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) applyNotify(s *section) {
+	var methods, events []string
+	var quiet string
+	if s.strings("methods", &methods) {
+		if err := c.Notify.SetMethods(methods); err != nil {
+			s.fail("methods", "%v", err)
+		}
+	}
+	s.strings("command", &c.Notify.Command)
+	if s.strings("events", &events) {
+		kinds, err := notify.ParseKinds(events)
+		if err != nil {
+			s.fail("events", "%v", err)
+		}
+		c.Notify.Kinds = kinds
+	}
+	if s.str("quiet_hours", &quiet) {
+		start, end, err := notify.ParseQuietHours(quiet)
+		if err != nil {
+			s.fail("quiet_hours", "%v", err)
+		}
+		c.Notify.QuietStart, c.Notify.QuietEnd = start, end
+	}
+	s.duration("dedup_window", &c.Notify.DedupWindow)
+}
+
+// applyKeys reads action = "key" or action = ["key", ...] pairs.
+func (c *Config) applyKeys(s *section) {
+	names := make([]string, 0, len(s.t))
+	for name := range s.t {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v, _ := s.lookup(name)
+		var keys []string
+		switch x := v.v.(type) {
+		case string:
+			keys = []string{x}
+		case []string:
+			keys = x
+		default:
+			s.fail(name, "want a key or an array of keys")
+			continue
+		}
+		if c.Keys == nil {
+			c.Keys = make(map[string][]string)
+		}
+		c.Keys[name] = keys
+	}
+}
+
+// ResolveTown returns the root of the registered town named nameOrRoot,
+// or nameOrRoot itself when no town has that name.
+func (c Config) ResolveTown(nameOrRoot string) string {
+	for _, t := range c.Towns {
+		if t.Name == nameOrRoot {
+			return t.Root
+		}
+	}
+	return nameOrRoot
+}
+
+// expandHome replaces a leading ~ with the home directory.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path, err
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
+
+// section reads typed keys from one table. It tracks which keys were read
+// and keeps the first error so callers can read every key and check once.
+type section struct {
+	name string
+	t    table
+	used map[string]bool
+	err  error
+}
+
+func newSection(name string, t table) *section {
+	return &section{name: name, t: t, used: make(map[string]bool)}
+}
+
+// lookup returns the value for key, marking it as read.
+func (s *section) lookup(key string) (value, bool) {
+	v, ok := s.t[key]
+	if ok {
+		s.used[key] = true
+	}
+	return v, ok
+}
+
+// fail records an error for key if none has been recorded yet.
+func (s *section) fail(key, format string, args ...any) {
+	if s.err != nil {
+		return
+	}
+	name := key
+	if s.name != "" {
+		name = s.name + "." + key
+	}
+	s.err = fmt.Errorf("%s: %s", name, fmt.Sprintf(format, args...))
+}
+
+// str reads a string into dst and reports whether key was set.
+func (s *section) str(key string, dst *string) bool {
+	v, ok := s.lookup(key)
+	if !ok {
+		return false
+	}
+	str, ok := v.v.(string)
+	if !ok {
+		s.fail(key, "want a quoted string")
+		return false
+	}
+	*dst = str
+	return true
+}
+
+// integer reads an integer into dst and reports whether key was set.
+func (s *section) integer(key string, dst *int) bool {
+	v, ok := s.lookup(key)
+	if !ok {
+		return false
+	}
+	i, ok := v.v.(int64)
+	if !ok {
+		s.fail(key, "want an integer")
+		return false
+	}
+	*dst = int(i)
+	return true
+}
+
+// boolean reads true or false into dst and reports whether key was set.
+func (s *section) boolean(key string, dst *bool) bool {
+	v, ok := s.lookup(key)
+	if !ok {
+		return false
+	}
+	b, ok := v.v.(bool)
+	if !ok {
+		s.fail(key, "want true or false")
+		return false
+	}
+	*dst = b
+	return true
+}
+
+// duration reads a duration string into dst and reports whether key was set.
+func (s *section) duration(key string, dst *time.Duration) bool {
+	var str string
+	if v, ok := s.lookup(key); ok {
+		if str, ok = v.v.(string); !ok {
+			s.fail(key, `want a duration string like "10s"`)
+			return false
+		}
+	} else {
+		return false
+	}
+	d, err := time.ParseDuration(str)
+	if err != nil {
+		s.fail(key, `invalid duration %q (want e.g. "10s", "5m")`, str)
+		return false
+	}
+	*dst = d
+	return true
+}
+
+// strings reads an array of strings into dst and reports whether key was set.
+func (s *section) strings(key string, dst *[]string) bool {
+	v, ok := s.lookup(key)
+	if !ok {
+		return false
+	}
+	list, ok := v.v.([]string)
+	if !ok {
+		s.fail(key, "want an array of strings")
+		return false
+	}
+	*dst = list
+	return true
+}
+
+// done returns the first error, or rejects the earliest key that was never read.
+func (s *section) done() error {
+	if s.err != nil {
+		return s.err
+	}
+	var unknown []string
+	for key := range s.t {
+		if !s.used[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Slice(unknown, func(i, j int) bool { return s.t[unknown[i]].order < s.t[unknown[j]].order })
+	s.fail(unknown[0], "unknown setting")
+	return s.err
+}
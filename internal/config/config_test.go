@@ -0,0 +1,261 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/internal/notify"
+)
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.toml")
+
+	cfg, err := Load(path, false)
+	if err != nil {
+		t.Fatalf("expected defaults for a missing file, got %v", err)
+	}
+	if cfg.Path != "" || cfg.RefreshInterval != 10*time.Second {
+		t.Errorf("expected defaults, got %+v", cfg)
+	}
+
+	if _, err := Load(path, true); err == nil {
+		t.Error("expected an error for a required missing file")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := writeConfig(t, `
+# Perch settings
+town_root = "/srv/gt"
+
+[refresh]
+interval = "30s"      # slower TUI
+store_interval = "15s"
+load_timeout = "1m"
+watch = false
+fallback_interval = "2m"
+
+[data]
+lifecycle_events = 250
+
+[activity]
+max_events = 20
+
+[queue]
+stale_mr_threshold = "2h"
+
+[notify]
+methods = ["osc9", "bell"]
+command = ["notify-send", "-u", "critical"]
+events = ["crash", "p0_bead"]
+quiet_hours = "22:00-07:00"
+
+[[nudges]]
+label = "Ping"
+message = "Are you still there? # not a comment"
+
+[[nudges]]
+label = 'Rebase'
+message = "Please rebase on \"main\"."
+
+[[towns]]
+name = "web"
+root = "/srv/gt-web"
+
+[[towns]]
+name = "infra"
+root = "/srv/gt-infra"
+`)
+	cfg, err := Load(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Path != path || cfg.TownRoot != "/srv/gt" {
+		t.Errorf("unexpected path/town root: %q %q", cfg.Path, cfg.TownRoot)
+	}
+	if cfg.RefreshInterval != 30*time.Second || cfg.StoreRefreshInterval != 15*time.Second || cfg.LoadTimeout != time.Minute {
+		t.Errorf("unexpected refresh settings: %+v", cfg)
+	}
+	if cfg.Watch || cfg.WatchFallbackInterval != 2*time.Minute {
+		t.Errorf("unexpected watch settings: %v %s", cfg.Watch, cfg.WatchFallbackInterval)
+	}
+	if cfg.LifecycleEvents != 250 || cfg.ActivityMaxEvents != 20 || cfg.StaleMRThreshold != 2*time.Hour {
+		t.Errorf("unexpected limits: %+v", cfg)
+	}
+	if !cfg.Notify.OSC9 || !cfg.Notify.Bell || cfg.Notify.OSC777 {
+		t.Errorf("unexpected notify methods: %+v", cfg.Notify)
+	}
+	if strings.Join(cfg.Notify.Command, " ") != "notify-send -u critical" {
+		t.Errorf("unexpected notify command: %q", cfg.Notify.Command)
+	}
+	if len(cfg.Notify.Kinds) != 2 || !cfg.Notify.Kinds[notify.KindCrash] {
+		t.Errorf("unexpected notify kinds: %v", cfg.Notify.Kinds)
+	}
+	if cfg.Notify.QuietStart != 22*time.Hour || cfg.Notify.QuietEnd != 7*time.Hour {
+		t.Errorf("unexpected quiet hours: %s-%s", cfg.Notify.QuietStart, cfg.Notify.QuietEnd)
+	}
+
+	want := []Nudge{
+		{"Ping", "Are you still there? # not a comment"},
+		{"Rebase", `Please rebase on "main".`},
+	}
+	if len(cfg.Nudges) != len(want) {
+		t.Fatalf("expected %d nudges, got %+v", len(want), cfg.Nudges)
+	}
+	for i := range want {
+		if cfg.Nudges[i] != want[i] {
+			t.Errorf("nudge %d: got %+v, want %+v", i, cfg.Nudges[i], want[i])
+		}
+	}
+
+	if len(cfg.Towns) != 2 || cfg.Towns[1] != (Town{"infra", "/srv/gt-infra"}) {
+		t.Errorf("unexpected towns: %+v", cfg.Towns)
+	}
+	if cfg.ResolveTown("web") != "/srv/gt-web" || cfg.ResolveTown("/other") != "/other" {
+		t.Error("expected town names to resolve to their roots")
+	}
+}
+
+func TestLoadFullTOML(t *testing.T) {
+	path := writeConfig(t, `
+refresh.interval = "30s"
+activity = { max_events = 20 }
+
+[notify]
+methods = [
+  "osc9",
+  "bell", # trailing comma and comments
+]
+`)
+	cfg, err := Load(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.RefreshInterval != 30*time.Second || cfg.ActivityMaxEvents != 20 {
+		t.Errorf("expected dotted keys and inline tables applied, got %+v", cfg)
+	}
+	if !cfg.Notify.OSC9 || !cfg.Notify.Bell {
+		t.Errorf("expected a multi-line array applied, got %+v", cfg.Notify)
+	}
+
+	_, err = Load(writeConfig(t, "[activity]\nmax_events = 2.5\n"), true)
+	if err == nil || !strings.Contains(err.Error(), "activity.max_events: want an integer") {
+		t.Errorf("expected a float rejected by type, got %v", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"syntax", "[refresh]\ninterval 10s\n", "line 2: expected '.' or '='"},
+		{"unquoted string", "town_root = ~/gt\n", "line 1: expected value"},
+		{"unknown section", "[refrsh]\ninterval = \"10s\"\n", "unknown section [refrsh]"},
+		{"unknown key", "[refresh]\ninterval = \"10s\"\nintreval = \"5s\"\n", "refresh.intreval: unknown setting"},
+		{"bad duration", "[queue]\nstale_mr_threshold = \"an hour\"\n", `queue.stale_mr_threshold: invalid duration "an hour"`},
+		{"wrong type", "[activity]\nmax_events = \"50\"\n", "activity.max_events: want an integer"},
+		{"not a boolean", "[refresh]\nwatch = \"yes\"\n", "refresh.watch: want true or false"},
+		{"bad notify method", "[notify]\nmethods = [\"smoke\"]\n", `notify.methods: unknown method "smoke"`},
+		{"duplicate key", "[data]\nlifecycle_events = 1\nlifecycle_events = 2\n", `line 3: Key 'data.lifecycle_events' has already been defined`},
+		{"unterminated", "town_root = \"/srv\n", "line 1: strings cannot contain newlines"},
+		{"not positive", "[refresh]\ninterval = \"0s\"\n", "refresh.interval must be positive"},
+		{"too small", "[activity]\nmax_events = 0\n", "activity.max_events must be at least 1"},
+		{"empty nudge", "[[nudges]]\nlabel = \"Ping\"\n", "nudges[0] needs both a label and a message"},
+		{"duplicate town", "[[towns]]\nname = \"a\"\nroot = \"/a\"\n[[towns]]\nname = \"a\"\nroot = \"/b\"\n", `towns[1]: duplicate town name "a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.body)
+			_, err := Load(path, true)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %q", tt.want, err)
+			}
+			if !strings.HasPrefix(err.Error(), path) {
+				t.Errorf("expected error to name the file, got %q", err)
+			}
+		})
+	}
+}
+
+func TestLoadKeys(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "[keys]\nrefresh = \"f5\"\nquit = [\"q\", \"ctrl+q\"]\narchive_all = []\n"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"refresh": "f5", "quit": "q ctrl+q", "archive_all": ""}
+	if len(cfg.Keys) != len(want) {
+		t.Fatalf("expected %d bindings, got %v", len(want), cfg.Keys)
+	}
+	for action, keys := range want {
+		if got := strings.Join(cfg.Keys[action], " "); got != keys {
+			t.Errorf("keys.%s = %q, want %q", action, got, keys)
+		}
+	}
+
+	_, err = Load(writeConfig(t, "[keys]\nrefresh = 5\n"), true)
+	if err == nil || !strings.Contains(err.Error(), "keys.refresh: want a key or an array of keys") {
+		t.Errorf("expected key type error, got %v", err)
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	path := writeConfig(t, "town_root = \"/srv/gt\"\n[notify]\nmethods = [\"bell\"]\n")
+	cfg, err := Load(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{"GT_ROOT": "/env/gt", "PERCH_NOTIFY_QUIET": "23:00-06:00"}
+	if err := cfg.ApplyEnv(func(k string) string { return env[k] }); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TownRoot != "/env/gt" {
+		t.Errorf("expected GT_ROOT to override the file, got %q", cfg.TownRoot)
+	}
+	if !cfg.Notify.Bell || cfg.Notify.QuietStart != 23*time.Hour {
+		t.Errorf("expected file methods kept and env quiet hours applied, got %+v", cfg.Notify)
+	}
+
+	env = map[string]string{"PERCH_NOTIFY": "smoke"}
+	if err := cfg.ApplyEnv(func(k string) string { return env[k] }); err == nil {
+		t.Error("expected an error for a bad PERCH_NOTIFY")
+	}
+}
+
+func TestTownRootExpandsHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cfg, err := Load(writeConfig(t, "town_root = \"~/towns/main\"\n"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TownRoot != filepath.Join(home, "towns", "main") {
+		t.Errorf("expected ~ expanded, got %q", cfg.TownRoot)
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	path, err := DefaultPath()
+	if err != nil || path != filepath.Join("/xdg", "perch", "config.toml") {
+		t.Errorf("expected XDG path, got %q, %v", path, err)
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/BurntSushi/toml"
+)
+
+// document is a parsed TOML file: top-level keys, tables, and arrays of tables.
+type document struct {
+	root   table
+	tables map[string]table   // [name], or name.key = ... and inline tables
+	arrays map[string][]table // [[name]]
+}
+
+// table maps keys to their decoded values.
+type table map[string]value
+
+// value is a decoded TOML value: a string, int64, float64, bool, []string,
+// or any other TOML type as decoded, which settings reject by type.
+type value struct {
+	order int // Position among the file's keys, for reporting the first problem
+	v     any
+}
+
+// parseTOML parses a TOML file and splits it into perch's top-level keys,
+// tables and arrays of tables.
+func parseTOML(r io.Reader) (*document, error) {
+	var raw map[string]any
+	md, err := toml.NewDecoder(r).Decode(&raw)
+	if err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			return nil, fmt.Errorf("line %d: %s", perr.Position.Line, perr.Message)
+		}
+		return nil, err
+	}
+
+	order := make(map[string]int)
+	for i, key := range md.Keys() {
+		if _, ok := order[key.String()]; !ok {
+			order[key.String()] = i
+		}
+	}
+	newTable := func(prefix string, m map[string]any) table {
+		t := make(table, len(m))
+		for key, v := range m {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			t[key] = value{order: order[path], v: normalize(v)}
+		}
+		return t
+	}
+
+	doc := &document{root: table{}, tables: map[string]table{}, arrays: map[string][]table{}}
+	for key, v := range raw {
+		switch x := v.(type) {
+		case map[string]any:
+			doc.tables[key] = newTable(key, x)
+		case []map[string]any:
+			for _, m := range x {
+				doc.arrays[key] = append(doc.arrays[key], newTable(key, m))
+			}
+		default:
+			doc.root[key] = value{order: order[key], v: normalize(v)}
+		}
+	}
+	return doc, nil
+}
+
+// normalize turns arrays of strings into []string, leaving other values
+// as decoded.
+func normalize(v any) any {
+	list, ok := v.([]any)
+	if !ok {
+		return v
+	}
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			return v
+		}
+		strs = append(strs, s)
+	}
+	return strs
+}
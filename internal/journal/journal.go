@@ -0,0 +1,214 @@
+// Package journal keeps perch's append-only action journal.
+//
+// Every action run from the TUI appends one JSON line to
+// ~/.perch/actions.jsonl recording who ran it, against which target,
+// when, and the command output or error. Entries for reversible actions
+// carry the action that undoes them.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxOutput bounds the command output kept per entry.
+const MaxOutput = 4096
+
+// Entry is one journaled action.
+type Entry struct {
+	ID     string    `json:"id"`
+	Time   time.Time `json:"time"`
+	User   string    `json:"user"`
+	Town   string    `json:"town,omitempty"`
+	Action string    `json:"action"` // Display name, e.g. "Close bead"
+	Target string    `json:"target,omitempty"`
+	Input  string    `json:"input,omitempty"`
+
+	Commands []string `json:"commands,omitempty"`
+	Output   string   `json:"output,omitempty"`
+	Error    string   `json:"error,omitempty"`
+
+	// Undo is the action that reverses this one, when there is one.
+	Undo *Undo `json:"undo,omitempty"`
+	// UndoOf is the ID of the entry this one undid.
+	UndoOf string `json:"undo_of,omitempty"`
+}
+
+// Undo describes the action that reverses an entry.
+type Undo struct {
+	Action string `json:"action"` // Stable action key, e.g. "reopen_bead"
+	Target string `json:"target,omitempty"`
+	Input  string `json:"input,omitempty"`
+}
+
+// Failed reports whether the action failed.
+func (e Entry) Failed() bool {
+	return e.Error != ""
+}
+
+// Journal appends entries to a JSON lines file.
+type Journal struct {
+	Path string
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+// New creates a journal writing to path.
+func New(path string) *Journal {
+	return &Journal{Path: path}
+}
+
+// DefaultPath returns ~/.perch/actions.jsonl.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
+	}
+	return filepath.Join(home, ".perch", "actions.jsonl"), nil
+}
+
+var seq atomic.Uint64
+
+// Append writes an entry, filling in its ID, time and user when unset,
+// and returns the entry as written. The error is also kept for LastError.
+func (j *Journal) Append(e Entry) (Entry, error) {
+	e, err := j.append(e)
+	j.mu.Lock()
+	j.lastErr = err
+	j.mu.Unlock()
+	return e, err
+}
+
+// LastError returns the error of the most recent Append, if it failed.
+func (j *Journal) LastError() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.lastErr
+}
+
+func (j *Journal) append(e Entry) (Entry, error) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.ID == "" {
+		e.ID = strconv.FormatInt(e.Time.UnixNano(), 36) + "-" + strconv.FormatUint(seq.Add(1), 36)
+	}
+	if e.User == "" {
+		e.User = currentUser()
+	}
+	if len(e.Output) > MaxOutput {
+		// Cut at the start of a character so the output stays valid UTF-8
+		cut := MaxOutput
+		for cut > 0 && !utf8.RuneStart(e.Output[cut]) {
+			cut--
+		}
+		e.Output = e.Output[:cut] + "…"
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return e, fmt.Errorf("encoding journal entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(j.Path), 0755); err != nil {
+		return e, fmt.Errorf("creating journal directory: %w", err)
+	}
+	f, err := os.OpenFile(j.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return e, fmt.Errorf("opening journal: %w", err)
+	}
+	// A single write keeps lines whole when several perch processes append
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return e, fmt.Errorf("writing journal: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return e, fmt.Errorf("closing journal: %w", err)
+	}
+	return e, nil
+}
+
+// Load returns the last limit entries, oldest first. A zero limit returns
+// every entry. A missing journal is empty; lines that do not parse are
+// skipped.
+func (j *Journal) Load(limit int) ([]Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := os.Open(j.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening journal: %w", err)
+	}
+	defer f.Close()
+	return readEntries(f, limit)
+}
+
+func readEntries(r io.Reader, limit int) ([]Entry, error) {
+	var entries []Entry
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+		if limit > 0 && len(entries) > 2*limit {
+			entries = append(entries[:0], entries[len(entries)-limit:]...)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading journal: %w", err)
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	return entries, nil
+}
+
+// Undone returns the IDs of entries that a later entry undid.
+func Undone(entries []Entry) map[string]bool {
+	undone := make(map[string]bool)
+	for _, e := range entries {
+		if e.UndoOf != "" && !e.Failed() {
+			undone[e.UndoOf] = true
+		}
+	}
+	return undone
+}
+
+var currentUser = sync.OnceValue(CurrentUser)
+
+// CurrentUser names the operator as user@host.
+func CurrentUser() string {
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name = u.Username
+	}
+	if name == "" {
+		name = "unknown"
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return name + "@" + host
+	}
+	return name
+}
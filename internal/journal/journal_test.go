@@ -0,0 +1,89 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestAppendAndLoad(t *testing.T) {
+	j := New(filepath.Join(t.TempDir(), "perch", "actions.jsonl"))
+
+	at := time.Date(2026, 1, 8, 17, 0, 0, 0, time.UTC)
+	first, err := j.Append(Entry{Time: at, User: "ann@box", Action: "Close bead", Target: "pe-1",
+		Undo: &Undo{Action: "reopen_bead", Target: "pe-1"}})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if first.ID == "" {
+		t.Error("expected an ID to be assigned")
+	}
+	if _, err := j.Append(Entry{Time: at.Add(time.Minute), Action: "Reopen bead", Target: "pe-1", UndoOf: first.ID}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	entries, err := j.Load(0)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if e := entries[0]; e.User != "ann@box" || e.Undo == nil || e.Undo.Action != "reopen_bead" || !e.Time.Equal(at) {
+		t.Errorf("unexpected first entry %+v", e)
+	}
+	if entries[1].User == "" {
+		t.Error("expected the current user to be filled in")
+	}
+	if !Undone(entries)[first.ID] {
+		t.Error("expected the first entry to be undone")
+	}
+}
+
+func TestLoadKeepsTheTailAndSkipsBadLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "actions.jsonl")
+	lines := []string{
+		`{"id":"1","action":"Boot rig"}`,
+		`not json`,
+		`{"id":"2","action":"Boot rig"}`,
+		`{"id":"3","action":"Boot rig"}`,
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := New(path).Load(2)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != "2" || entries[1].ID != "3" {
+		t.Errorf("expected entries 2 and 3, got %+v", entries)
+	}
+
+	if entries, err := New(filepath.Join(t.TempDir(), "missing.jsonl")).Load(0); err != nil || len(entries) != 0 {
+		t.Errorf("expected a missing journal to be empty, got %v %v", entries, err)
+	}
+}
+
+func TestAppendTruncatesOutput(t *testing.T) {
+	j := New(filepath.Join(t.TempDir(), "actions.jsonl"))
+	e, err := j.Append(Entry{Action: "View output", Output: strings.Repeat("x", MaxOutput+10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Output) > MaxOutput+len("…") {
+		t.Errorf("expected output capped, got %d bytes", len(e.Output))
+	}
+
+	// A multi-byte character straddling the cap is dropped whole
+	e, err = j.Append(Entry{Action: "View output", Output: "x" + strings.Repeat("é", MaxOutput)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !utf8.ValidString(e.Output) || len(e.Output) != MaxOutput-1+len("…") {
+		t.Errorf("expected output cut at a character boundary, got %d bytes", len(e.Output))
+	}
+}
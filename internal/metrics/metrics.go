@@ -0,0 +1,320 @@
+// Package metrics renders town snapshots in the Prometheus text exposition format.
+//
+// Gauges are derived from a single Snapshot, so `perch metrics` can print
+// them from a one-shot load. Counters accumulate across refreshes in a
+// Collector, which perch serve feeds from the store's refresh hook.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// ContentType is the Content-Type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// beadStatuses are the bead statuses exported as perch_beads.
+var beadStatuses = []string{"open", "in_progress", "hooked"}
+
+// Collector accumulates counters across refreshes.
+type Collector struct {
+	mu         sync.Mutex
+	refreshes  int
+	loadErrors map[string]int // Cumulative load errors by source
+
+	// Commands, if set, adds the latency of each command the loader runs.
+	Commands *data.CommandStats
+}
+
+// NewCollector creates an empty collector.
+func NewCollector() *Collector {
+	return &Collector{loadErrors: make(map[string]int)}
+}
+
+// Observe counts a refresh and its load errors.
+func (c *Collector) Observe(snap *data.Snapshot) {
+	if snap == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshes++
+	for _, e := range snap.LoadErrors {
+		c.loadErrors[e.Source]++
+	}
+}
+
+// Write writes the gauges for snap followed by the collector's counters.
+// Ages are computed relative to now.
+func (c *Collector) Write(w io.Writer, snap *data.Snapshot, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	if snap != nil {
+		writeGauges(bw, snap, now)
+	}
+
+	c.mu.Lock()
+	refreshes := []sample{{value: float64(c.refreshes)}}
+	loadErrors := make([]sample, 0, len(c.loadErrors))
+	for _, source := range sortedKeys(c.loadErrors) {
+		loadErrors = append(loadErrors, sample{labels: []string{"source", source}, value: float64(c.loadErrors[source])})
+	}
+	c.mu.Unlock()
+
+	writeFamily(bw, "perch_refreshes_total", "counter", "Snapshot refreshes observed.", refreshes)
+	writeFamily(bw, "perch_load_errors_total", "counter", "Load errors observed across refreshes by source.", loadErrors)
+	if c.Commands != nil {
+		writeCommands(bw, c.Commands.All())
+	}
+	return bw.Flush()
+}
+
+// Write writes the gauges for a single snapshot.
+// It is used for one-shot output where there are no counters to report.
+func Write(w io.Writer, snap *data.Snapshot, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	writeGauges(bw, snap, now)
+	return bw.Flush()
+}
+
+// sample is one metric value with alternating label names and values.
+type sample struct {
+	labels []string
+	value  float64
+}
+
+func writeGauges(w *bufio.Writer, snap *data.Snapshot, now time.Time) {
+	writeFamily(w, "perch_snapshot_timestamp_seconds", "gauge", "Unix time the snapshot was loaded.",
+		[]sample{{value: unixSeconds(snap.LoadedAt)}})
+	writeFamily(w, "perch_agents", "gauge", "Agents by rig and role. Town-level agents have an empty rig.", agentSamples(snap, false))
+	writeFamily(w, "perch_agents_running", "gauge", "Running agents by rig and role. Town-level agents have an empty rig.", agentSamples(snap, true))
+
+	var depth, conflicts, rebase []sample
+	for _, rig := range sortedKeys(snap.MergeQueues) {
+		var c, r int
+		for _, mr := range snap.MergeQueues[rig] {
+			if mr.HasConflicts {
+				c++
+			}
+			if mr.NeedsRebase {
+				r++
+			}
+		}
+		labels := []string{"rig", rig}
+		depth = append(depth, sample{labels: labels, value: float64(len(snap.MergeQueues[rig]))})
+		conflicts = append(conflicts, sample{labels: labels, value: float64(c)})
+		rebase = append(rebase, sample{labels: labels, value: float64(r)})
+	}
+	writeFamily(w, "perch_merge_queue_depth", "gauge", "Merge requests queued per rig.", depth)
+	writeFamily(w, "perch_merge_queue_conflicts", "gauge", "Queued merge requests with conflicts per rig.", conflicts)
+	writeFamily(w, "perch_merge_queue_needs_rebase", "gauge", "Queued merge requests needing a rebase per rig.", rebase)
+
+	writeFamily(w, "perch_beads", "gauge", "Open, in-progress and hooked beads by status and priority.", beadSamples(snap))
+
+	unread := 0
+	for _, m := range snap.Mail {
+		if !m.Read {
+			unread++
+		}
+	}
+	writeFamily(w, "perch_mail_unread", "gauge", "Unread mail messages.", []sample{{value: float64(unread)}})
+
+	var convoys []sample
+	for _, c := range snap.Convoys {
+		ratio := 0.0
+		if c.Total > 0 {
+			ratio = float64(c.Completed) / float64(c.Total)
+		}
+		convoys = append(convoys, sample{labels: []string{"convoy", c.ID}, value: ratio})
+	}
+	writeFamily(w, "perch_convoy_completion_ratio", "gauge", "Fraction of tracked issues completed per open convoy.", convoys)
+
+	if snap.DoctorReport != nil {
+		var checks []sample
+		for _, check := range snap.DoctorReport.Checks {
+			checks = append(checks, sample{labels: []string{"check", check.Name}, value: checkValue(check.Status)})
+		}
+		writeFamily(w, "perch_doctor_check_status", "gauge", "Doctor check status (0 passed, 1 warning, 2 error).", checks)
+	}
+
+	var ages []sample
+	for _, source := range sortedKeys(snap.LastSuccess) {
+		ages = append(ages, sample{labels: []string{"source", source}, value: now.Sub(snap.LastSuccess[source]).Seconds()})
+	}
+	writeFamily(w, "perch_source_last_success_age_seconds", "gauge", "Seconds since each data source last loaded successfully.", ages)
+
+	errorCounts := make(map[string]int)
+	for _, e := range snap.LoadErrors {
+		errorCounts[e.Source]++
+	}
+	var errs []sample
+	for _, source := range sortedKeys(errorCounts) {
+		errs = append(errs, sample{labels: []string{"source", source}, value: float64(errorCounts[source])})
+	}
+	writeFamily(w, "perch_load_errors", "gauge", "Load errors in the current snapshot by source.", errs)
+}
+
+// writeCommands writes call counts and latency per command.
+func writeCommands(w *bufio.Writer, stats []data.CommandStat) {
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Command < stats[j].Command })
+	var calls, errs, seconds, slowest []sample
+	for _, stat := range stats {
+		labels := []string{"command", stat.Command}
+		calls = append(calls, sample{labels: labels, value: float64(stat.Calls)})
+		errs = append(errs, sample{labels: labels, value: float64(stat.Errors)})
+		seconds = append(seconds, sample{labels: labels, value: stat.Total.Seconds()})
+		slowest = append(slowest, sample{labels: labels, value: stat.Max.Seconds()})
+	}
+	writeFamily(w, "perch_command_calls_total", "counter", "Commands run by the loader.", calls)
+	writeFamily(w, "perch_command_errors_total", "counter", "Commands run by the loader that failed.", errs)
+	writeFamily(w, "perch_command_seconds_total", "counter", "Time spent running each command.", seconds)
+	writeFamily(w, "perch_command_max_seconds", "gauge", "Slowest single run of each command.", slowest)
+}
+
+// agentSamples counts agents by rig and role, optionally only running ones.
+// Every rig/role pair with an agent is reported so stopped pairs read as 0.
+func agentSamples(snap *data.Snapshot, runningOnly bool) []sample {
+	if snap.Town == nil {
+		return nil
+	}
+	type key struct{ rig, role string }
+	counts := make(map[key]int)
+	count := func(rig string, a data.Agent) {
+		k := key{rig, a.Role}
+		if _, ok := counts[k]; !ok {
+			counts[k] = 0
+		}
+		if !runningOnly || a.Running {
+			counts[k]++
+		}
+	}
+	for _, a := range snap.Town.Agents {
+		count("", a)
+	}
+	for _, rig := range snap.Town.Rigs {
+		for _, a := range rig.Agents {
+			count(rig.Name, a)
+		}
+	}
+
+	keys := make([]key, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].rig != keys[j].rig {
+			return keys[i].rig < keys[j].rig
+		}
+		return keys[i].role < keys[j].role
+	})
+	samples := make([]sample, 0, len(keys))
+	for _, k := range keys {
+		samples = append(samples, sample{labels: []string{"rig", k.rig, "role", k.role}, value: float64(counts[k])})
+	}
+	return samples
+}
+
+// beadSamples counts beads by status and priority.
+// Hooked issues are loaded separately, so they are merged in by ID.
+func beadSamples(snap *data.Snapshot) []sample {
+	seen := make(map[string]bool)
+	counts := make(map[string]map[int]int)
+	add := func(issue data.Issue) {
+		if seen[issue.ID] {
+			return
+		}
+		seen[issue.ID] = true
+		if counts[issue.Status] == nil {
+			counts[issue.Status] = make(map[int]int)
+		}
+		counts[issue.Status][issue.Priority]++
+	}
+	for _, issue := range snap.Issues {
+		add(issue)
+	}
+	for _, issue := range snap.HookedIssues {
+		add(issue)
+	}
+
+	var samples []sample
+	for _, status := range beadStatuses {
+		byPriority := counts[status]
+		priorities := make([]int, 0, len(byPriority))
+		for p := range byPriority {
+			priorities = append(priorities, p)
+		}
+		sort.Ints(priorities)
+		for _, p := range priorities {
+			samples = append(samples, sample{
+				labels: []string{"status", status, "priority", strconv.Itoa(p)},
+				value:  float64(byPriority[p]),
+			})
+		}
+	}
+	return samples
+}
+
+// checkValue maps a doctor check status to a gauge value.
+func checkValue(s data.CheckStatus) float64 {
+	switch s {
+	case data.CheckWarning:
+		return 1
+	case data.CheckError:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// writeFamily writes a metric family's HELP and TYPE lines and its samples.
+func writeFamily(w *bufio.Writer, name, typ, help string, samples []sample) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	for _, s := range samples {
+		w.WriteString(name)
+		if len(s.labels) > 0 {
+			w.WriteByte('{')
+			for i := 0; i+1 < len(s.labels); i += 2 {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				fmt.Fprintf(w, "%s=\"%s\"", s.labels[i], escapeLabel(s.labels[i+1]))
+			}
+			w.WriteByte('}')
+		}
+		w.WriteByte(' ')
+		w.WriteString(strconv.FormatFloat(s.value, 'g', -1, 64))
+		w.WriteByte('\n')
+	}
+}
+
+// labelEscaper escapes label values per the exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+// unixSeconds returns t as fractional Unix seconds, or 0 for the zero time.
+func unixSeconds(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	return float64(t.UnixNano()) / 1e9
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,187 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+var testNow = time.Date(2026, 1, 8, 17, 0, 0, 0, time.UTC)
+
+func testSnapshot() *data.Snapshot {
+	return &data.Snapshot{
+		LoadedAt: testNow,
+		Town: &data.TownStatus{
+			Name:   "test-town",
+			Agents: []data.Agent{{Address: "mayor/", Role: "mayor", Running: true}},
+			Rigs: []data.Rig{{
+				Name: "perch",
+				Agents: []data.Agent{
+					{Address: "perch/ace", Role: "polecat", Running: true},
+					{Address: "perch/bob", Role: "polecat", Running: false},
+					{Address: "perch/witness", Role: "witness", Running: false},
+				},
+			}},
+		},
+		MergeQueues: map[string][]data.MergeRequest{
+			"perch":    {{ID: "mr-1", HasConflicts: true}, {ID: "mr-2", NeedsRebase: true}, {ID: "mr-3"}},
+			"sidekick": {},
+		},
+		Issues: []data.Issue{
+			{ID: "gt-1", Status: "open", Priority: 1},
+			{ID: "gt-2", Status: "open", Priority: 1},
+			{ID: "gt-3", Status: "in_progress", Priority: 2},
+			{ID: "gt-4", Status: "closed", Priority: 1},
+			{ID: "gt-5", Status: "hooked", Priority: 0},
+		},
+		HookedIssues: []data.Issue{
+			{ID: "gt-5", Status: "hooked", Priority: 0},
+			{ID: "gt-6", Status: "hooked", Priority: 0},
+		},
+		Mail:    []data.MailMessage{{ID: "m1"}, {ID: "m2", Read: true}},
+		Convoys: []data.Convoy{{ID: "cv-1", Completed: 1, Total: 4}, {ID: "cv-2"}},
+		DoctorReport: &data.DoctorReport{Checks: []data.DoctorCheck{
+			{Name: "town-config", Status: data.CheckPassed},
+			{Name: "orphan-sessions", Status: data.CheckWarning},
+			{Name: "beads-db", Status: data.CheckError},
+		}},
+		LastSuccess: map[string]time.Time{
+			"town_status": testNow.Add(-30 * time.Second),
+			"mail":        testNow.Add(-90 * time.Second),
+		},
+		LoadErrors: []data.LoadError{
+			{Source: "mail", Error: "boom"},
+			{Source: "merge_queue", Error: "boom"},
+			{Source: "merge_queue", Error: "boom"},
+		},
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testSnapshot(), testNow); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	want := []string{
+		"# TYPE perch_agents_running gauge",
+		`perch_agents_running{rig="",role="mayor"} 1`,
+		`perch_agents_running{rig="perch",role="polecat"} 1`,
+		`perch_agents_running{rig="perch",role="witness"} 0`,
+		`perch_agents{rig="perch",role="polecat"} 2`,
+		`perch_merge_queue_depth{rig="perch"} 3`,
+		`perch_merge_queue_depth{rig="sidekick"} 0`,
+		`perch_merge_queue_conflicts{rig="perch"} 1`,
+		`perch_merge_queue_needs_rebase{rig="perch"} 1`,
+		`perch_beads{status="open",priority="1"} 2`,
+		`perch_beads{status="in_progress",priority="2"} 1`,
+		`perch_beads{status="hooked",priority="0"} 2`,
+		"perch_mail_unread 1",
+		`perch_convoy_completion_ratio{convoy="cv-1"} 0.25`,
+		`perch_convoy_completion_ratio{convoy="cv-2"} 0`,
+		`perch_doctor_check_status{check="town-config"} 0`,
+		`perch_doctor_check_status{check="orphan-sessions"} 1`,
+		`perch_doctor_check_status{check="beads-db"} 2`,
+		`perch_source_last_success_age_seconds{source="mail"} 90`,
+		`perch_source_last_success_age_seconds{source="town_status"} 30`,
+		`perch_load_errors{source="merge_queue"} 2`,
+	}
+	for _, line := range want {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %q in output:\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, `status="closed"`) {
+		t.Error("closed beads should not be exported")
+	}
+	if strings.Contains(out, "perch_refreshes_total") {
+		t.Error("one-shot output should not include counters")
+	}
+}
+
+func TestWriteIsDeterministic(t *testing.T) {
+	var a, b bytes.Buffer
+	_ = Write(&a, testSnapshot(), testNow)
+	_ = Write(&b, testSnapshot(), testNow)
+	if a.String() != b.String() {
+		t.Error("expected identical output for identical snapshots")
+	}
+}
+
+func TestWriteEmptySnapshot(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, &data.Snapshot{}, testNow); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "perch_mail_unread 0\n") {
+		t.Errorf("expected zero-valued gauges, got:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "perch_doctor_check_status") {
+		t.Error("doctor metrics should be omitted without a report")
+	}
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector()
+	c.Observe(testSnapshot())
+	c.Observe(&data.Snapshot{LoadErrors: []data.LoadError{{Source: "mail", Error: "x"}}})
+	c.Observe(nil)
+
+	var buf bytes.Buffer
+	if err := c.Write(&buf, nil, testNow); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, line := range []string{
+		"# TYPE perch_refreshes_total counter",
+		"perch_refreshes_total 2",
+		`perch_load_errors_total{source="mail"} 2`,
+		`perch_load_errors_total{source="merge_queue"} 2`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %q in output:\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, "perch_mail_unread") {
+		t.Error("gauges should be omitted without a snapshot")
+	}
+	if strings.Contains(out, "perch_command_calls_total") {
+		t.Error("command latency should be omitted without stats")
+	}
+}
+
+func TestCollectorCommands(t *testing.T) {
+	c := NewCollector()
+	c.Commands = data.NewCommandStats()
+	c.Commands.Record("gt mq list", 2*time.Second, nil)
+	c.Commands.Record("gt mq list", 500*time.Millisecond, errors.New("exit status 1"))
+
+	var buf bytes.Buffer
+	if err := c.Write(&buf, nil, testNow); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, line := range []string{
+		`perch_command_calls_total{command="gt mq list"} 2`,
+		`perch_command_errors_total{command="gt mq list"} 1`,
+		`perch_command_seconds_total{command="gt mq list"} 2.5`,
+		`perch_command_max_seconds{command="gt mq list"} 2`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %q in output:\n%s", line, out)
+		}
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	got := escapeLabel("a\"b\\c\nd")
+	want := `a\"b\\c\nd`
+	if got != want {
+		t.Errorf("escapeLabel = %q, want %q", got, want)
+	}
+}
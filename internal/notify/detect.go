@@ -0,0 +1,214 @@
+// Package notify raises desktop and terminal notifications for critical
+// town events, so problems surface even when nobody is watching perch.
+//
+// Detect compares consecutive snapshots and reports conditions worth
+// interrupting someone for. A Notifier filters them by kind, deduplicates
+// and honors quiet hours, then delivers them via OSC 9, OSC 777, the
+// terminal bell and/or a command hook such as notify-send.
+package notify
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// Kind identifies a notification condition.
+type Kind string
+
+const (
+	KindCrash           Kind = "crash"            // Lifecycle log recorded an agent crash
+	KindRefineryStalled Kind = "refinery_stalled" // A rig's refinery stopped with a queue to process
+	KindP0Bead          Kind = "p0_bead"          // A new P0 bead was filed
+	KindEscalation      Kind = "escalation"       // Escalation mail arrived
+	KindDeaconStopped   Kind = "deacon_stopped"   // The deacon stopped running
+	KindLoadError       Kind = "load_error"       // A data source started failing to load
+)
+
+// AllKinds lists every notification kind in display order.
+var AllKinds = []Kind{KindCrash, KindRefineryStalled, KindP0Bead, KindEscalation, KindDeaconStopped, KindLoadError}
+
+// Notification is a single event worth alerting on.
+type Notification struct {
+	Kind  Kind
+	Key   string // Identity used for deduplication
+	Title string
+	Body  string
+	At    time.Time
+}
+
+// Detect returns notifications for conditions that newly hold in next.
+// A nil prev is a baseline and produces nothing, so starting perch against
+// a town that is already unhealthy does not raise a burst of alerts. An idle
+// refinery counts as stalled once an MR has waited longer than staleMR.
+func Detect(prev, next *data.Snapshot, staleMR time.Duration) []Notification {
+	if prev == nil || next == nil {
+		return nil
+	}
+
+	var out []Notification
+	out = append(out, detectCrashes(prev, next)...)
+	out = append(out, detectStalledRefineries(prev, next, staleMR)...)
+	out = append(out, detectChanges(next, data.DiffSnapshots(prev, next))...)
+	out = append(out, detectLoadErrors(prev, next)...)
+	return out
+}
+
+// detectCrashes reports crash events that were not in the previous lifecycle log.
+func detectCrashes(prev, next *data.Snapshot) []Notification {
+	if prev.Lifecycle == nil || next.Lifecycle == nil {
+		return nil
+	}
+	key := func(e data.LifecycleEvent) string {
+		return fmt.Sprintf("crash:%s:%d", e.Agent, e.Timestamp.UnixNano())
+	}
+	seen := make(map[string]bool, len(prev.Lifecycle.Events))
+	for _, e := range prev.Lifecycle.Events {
+		seen[key(e)] = true
+	}
+
+	var out []Notification
+	for _, e := range next.Lifecycle.Events {
+		if e.EventType != data.EventCrash || seen[key(e)] {
+			continue
+		}
+		out = append(out, Notification{
+			Kind:  KindCrash,
+			Key:   key(e),
+			Title: "Agent crashed: " + e.Agent,
+			Body:  e.Message,
+			At:    e.Timestamp,
+		})
+	}
+	return out
+}
+
+// stalledRefineries returns each rig whose refinery is stalled, by the same
+// rule as the queue health panel, with the refinery.
+func stalledRefineries(snap *data.Snapshot, staleMR time.Duration) map[string]data.Agent {
+	stalled := make(map[string]data.Agent)
+	if snap.Town == nil {
+		return stalled
+	}
+	for _, rig := range snap.Town.Rigs {
+		for _, a := range rig.Agents {
+			if a.Role == "refinery" && data.RefineryStalled(a, snap.MergeQueues[rig.Name], snap.LoadedAt, staleMR) {
+				stalled[rig.Name] = a
+			}
+		}
+	}
+	return stalled
+}
+
+func detectStalledRefineries(prev, next *data.Snapshot, staleMR time.Duration) []Notification {
+	if prev.Town == nil {
+		return nil
+	}
+	before := stalledRefineries(prev, staleMR)
+	after := stalledRefineries(next, staleMR)
+
+	rigs := make([]string, 0, len(after))
+	for rig := range after {
+		if _, ok := before[rig]; !ok {
+			rigs = append(rigs, rig)
+		}
+	}
+	sort.Strings(rigs)
+
+	var out []Notification
+	for _, rig := range rigs {
+		why := "the refinery is not running"
+		if after[rig].Running {
+			why = "the refinery is idle"
+		}
+		out = append(out, Notification{
+			Kind:  KindRefineryStalled,
+			Key:   "refinery_stalled:" + rig,
+			Title: "Refinery stalled: " + rig,
+			Body:  fmt.Sprintf("%d MRs waiting and %s", len(next.MergeQueues[rig]), why),
+			At:    next.LoadedAt,
+		})
+	}
+	return out
+}
+
+// detectChanges reports P0 beads, escalation mail and deacon stops from the snapshot diff.
+func detectChanges(next *data.Snapshot, changes []data.ChangeEvent) []Notification {
+	issues := make(map[string]data.Issue, len(next.Issues))
+	for _, issue := range next.Issues {
+		issues[issue.ID] = issue
+	}
+	mail := make(map[string]data.MailMessage, len(next.Mail))
+	for _, m := range next.Mail {
+		mail[m.ID] = m
+	}
+	roles := make(map[string]string)
+	if next.Town != nil {
+		for _, a := range next.Town.Agents {
+			roles[a.Address] = a.Role
+		}
+	}
+
+	var out []Notification
+	for _, c := range changes {
+		switch c.Type {
+		case data.ChangeBeadCreated:
+			if issue, ok := issues[c.ID]; ok && issue.Priority == 0 {
+				out = append(out, Notification{
+					Kind:  KindP0Bead,
+					Key:   "p0_bead:" + c.ID,
+					Title: "New P0: " + c.ID,
+					Body:  issue.Title,
+					At:    c.At,
+				})
+			}
+		case data.ChangeMailArrived:
+			if m, ok := mail[c.ID]; ok && m.Type == "escalation" {
+				out = append(out, Notification{
+					Kind:  KindEscalation,
+					Key:   "escalation:" + c.ID,
+					Title: "Escalation from " + m.From,
+					Body:  m.Subject,
+					At:    c.At,
+				})
+			}
+		case data.ChangeAgentStopped:
+			if roles[c.ID] == "deacon" {
+				out = append(out, Notification{
+					Kind:  KindDeaconStopped,
+					Key:   "deacon_stopped:" + c.ID,
+					Title: "Deacon stopped",
+					Body:  c.ID + " is no longer running; patrols and watchdog checks have paused",
+					At:    c.At,
+				})
+			}
+		}
+	}
+	return out
+}
+
+// detectLoadErrors reports sources that have errors in next but not in prev.
+func detectLoadErrors(prev, next *data.Snapshot) []Notification {
+	failing := make(map[string]bool, len(prev.LoadErrors))
+	for _, e := range prev.LoadErrors {
+		failing[e.Source] = true
+	}
+
+	var out []Notification
+	for _, e := range next.LoadErrors {
+		if failing[e.Source] {
+			continue
+		}
+		failing[e.Source] = true
+		out = append(out, Notification{
+			Kind:  KindLoadError,
+			Key:   "load_error:" + e.Source,
+			Title: "Failed to load " + e.SourceLabel(),
+			Body:  e.Error,
+			At:    e.OccurredAt,
+		})
+	}
+	return out
+}
@@ -0,0 +1,166 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+var t0 = time.Date(2026, 1, 8, 17, 0, 0, 0, time.UTC)
+
+// baseSnapshot returns a healthy town with a running deacon and refinery.
+func baseSnapshot(at time.Time) *data.Snapshot {
+	return &data.Snapshot{
+		LoadedAt: at,
+		Town: &data.TownStatus{
+			Agents: []data.Agent{{Address: "deacon/", Role: "deacon", Running: true}},
+			Rigs: []data.Rig{{
+				Name:   "perch",
+				Agents: []data.Agent{{Address: "perch/refinery", Role: "refinery", Running: true}},
+			}},
+		},
+		MergeQueues: map[string][]data.MergeRequest{"perch": {{ID: "mr-1"}}},
+		Issues:      []data.Issue{{ID: "gt-1", Status: "open", Priority: 2}},
+		Mail:        []data.MailMessage{{ID: "m-1", Type: "notification"}},
+		Lifecycle:   &data.LifecycleLog{},
+	}
+}
+
+func kinds(notes []Notification) []Kind {
+	out := make([]Kind, len(notes))
+	for i, n := range notes {
+		out[i] = n.Kind
+	}
+	return out
+}
+
+func TestDetectBaseline(t *testing.T) {
+	next := baseSnapshot(t0)
+	next.LoadErrors = []data.LoadError{{Source: "mail", Error: "boom"}}
+	if notes := Detect(nil, next, time.Hour); notes != nil {
+		t.Errorf("expected no notifications for baseline, got %v", kinds(notes))
+	}
+}
+
+func TestDetectNoChange(t *testing.T) {
+	if notes := Detect(baseSnapshot(t0), baseSnapshot(t0.Add(5*time.Second)), time.Hour); len(notes) != 0 {
+		t.Errorf("expected no notifications, got %v", kinds(notes))
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(prev, next *data.Snapshot)
+		want   Kind
+		key    string
+	}{
+		{
+			name: "crash",
+			mutate: func(prev, next *data.Snapshot) {
+				next.Lifecycle.Events = []data.LifecycleEvent{
+					{Timestamp: t0.Add(time.Second), EventType: data.EventCrash, Agent: "perch/ace", Message: "panic"},
+				}
+			},
+			want: KindCrash,
+		},
+		{
+			name: "refinery stalled",
+			mutate: func(prev, next *data.Snapshot) {
+				next.Town.Rigs[0].Agents[0].Running = false
+			},
+			want: KindRefineryStalled,
+			key:  "refinery_stalled:perch",
+		},
+		{
+			name: "refinery idle with an old MR",
+			mutate: func(prev, next *data.Snapshot) {
+				prev.MergeQueues["perch"][0].CreatedAt = t0.Add(-30 * time.Minute)
+				next.MergeQueues["perch"][0].CreatedAt = t0.Add(-30 * time.Minute)
+				next.LoadedAt = t0.Add(31 * time.Minute)
+			},
+			want: KindRefineryStalled,
+			key:  "refinery_stalled:perch",
+		},
+		{
+			name: "p0 bead",
+			mutate: func(prev, next *data.Snapshot) {
+				next.Issues = append(next.Issues, data.Issue{ID: "gt-9", Title: "Prod down", Status: "open", Priority: 0})
+			},
+			want: KindP0Bead,
+			key:  "p0_bead:gt-9",
+		},
+		{
+			name: "escalation mail",
+			mutate: func(prev, next *data.Snapshot) {
+				next.Mail = append(next.Mail, data.MailMessage{ID: "m-2", From: "perch/witness", Subject: "Help", Type: "escalation"})
+			},
+			want: KindEscalation,
+			key:  "escalation:m-2",
+		},
+		{
+			name: "deacon stopped",
+			mutate: func(prev, next *data.Snapshot) {
+				next.Town.Agents[0].Running = false
+			},
+			want: KindDeaconStopped,
+			key:  "deacon_stopped:deacon/",
+		},
+		{
+			name: "new load error source",
+			mutate: func(prev, next *data.Snapshot) {
+				next.LoadErrors = []data.LoadError{{Source: "mail", Error: "boom"}, {Source: "mail", Error: "again"}}
+			},
+			want: KindLoadError,
+			key:  "load_error:mail",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev, next := baseSnapshot(t0), baseSnapshot(t0.Add(5*time.Second))
+			tt.mutate(prev, next)
+			notes := Detect(prev, next, time.Hour)
+			if len(notes) != 1 || notes[0].Kind != tt.want {
+				t.Fatalf("expected one %s notification, got %v", tt.want, kinds(notes))
+			}
+			if tt.key != "" && notes[0].Key != tt.key {
+				t.Errorf("expected key %q, got %q", tt.key, notes[0].Key)
+			}
+			if notes[0].Title == "" {
+				t.Error("expected a title")
+			}
+		})
+	}
+}
+
+func TestDetectIgnoresExistingConditions(t *testing.T) {
+	prev, next := baseSnapshot(t0), baseSnapshot(t0.Add(5*time.Second))
+	crash := data.LifecycleEvent{Timestamp: t0, EventType: data.EventCrash, Agent: "perch/ace"}
+	for _, s := range []*data.Snapshot{prev, next} {
+		s.Lifecycle.Events = []data.LifecycleEvent{crash}
+		s.Town.Rigs[0].Agents[0].Running = false
+		s.LoadErrors = []data.LoadError{{Source: "mail", Error: "boom"}}
+	}
+	if notes := Detect(prev, next, time.Hour); len(notes) != 0 {
+		t.Errorf("expected no notifications for ongoing conditions, got %v", kinds(notes))
+	}
+}
+
+func TestDetectStoppedRefineryWithEmptyQueue(t *testing.T) {
+	prev, next := baseSnapshot(t0), baseSnapshot(t0.Add(5*time.Second))
+	next.Town.Rigs[0].Agents[0].Running = false
+	next.MergeQueues["perch"] = nil
+	if notes := Detect(prev, next, time.Hour); len(notes) != 0 {
+		t.Errorf("expected no stall without MRs waiting, got %v", kinds(notes))
+	}
+}
+
+func TestDetectNonP0BeadIgnored(t *testing.T) {
+	prev, next := baseSnapshot(t0), baseSnapshot(t0.Add(5*time.Second))
+	next.Issues = append(next.Issues, data.Issue{ID: "gt-9", Status: "open", Priority: 1})
+	if notes := Detect(prev, next, time.Hour); len(notes) != 0 {
+		t.Errorf("expected no notifications, got %v", kinds(notes))
+	}
+}
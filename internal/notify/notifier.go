@@ -0,0 +1,344 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// DefaultDedupWindow is how long a notification key stays suppressed after delivery.
+const DefaultDedupWindow = 30 * time.Minute
+
+// hookTimeout bounds a single run of the command hook.
+const hookTimeout = 10 * time.Second
+
+// Config selects which notifications are raised and how they are delivered.
+type Config struct {
+	// Kinds enables individual conditions. Nil enables all of them.
+	Kinds map[Kind]bool
+
+	// Delivery methods
+	OSC9    bool     // iTerm2/WezTerm/kitty style OSC 9 notification
+	OSC777  bool     // urxvt/foot/Ghostty style OSC 777 notification
+	Bell    bool     // Terminal bell
+	Command []string // Hook run as Command... <title> <body>, e.g. notify-send
+
+	// DedupWindow suppresses repeats of the same key. Zero uses DefaultDedupWindow.
+	DedupWindow time.Duration
+
+	// StaleMRThreshold is the MR age at which an idle refinery counts as
+	// stalled. Zero counts only stopped refineries.
+	StaleMRThreshold time.Duration
+
+	// Quiet hours as offsets from local midnight. Notifications raised in
+	// [QuietStart, QuietEnd) are dropped; the range may wrap past midnight.
+	// Equal values disable quiet hours.
+	QuietStart time.Duration
+	QuietEnd   time.Duration
+}
+
+// Enabled reports whether any delivery method is configured.
+func (c Config) Enabled() bool {
+	return c.OSC9 || c.OSC777 || c.Bell || len(c.Command) > 0
+}
+
+// wants reports whether kind is enabled.
+func (c Config) wants(kind Kind) bool {
+	return c.Kinds == nil || c.Kinds[kind]
+}
+
+// quiet reports whether t falls within quiet hours.
+func (c Config) quiet(t time.Time) bool {
+	if c.QuietStart == c.QuietEnd {
+		return false
+	}
+	y, mo, d := t.Date()
+	offset := t.Sub(time.Date(y, mo, d, 0, 0, 0, 0, t.Location()))
+	if c.QuietStart < c.QuietEnd {
+		return offset >= c.QuietStart && offset < c.QuietEnd
+	}
+	return offset >= c.QuietStart || offset < c.QuietEnd
+}
+
+// ConfigFromEnv builds a Config from environment variables alone.
+// getenv is usually os.Getenv.
+func ConfigFromEnv(getenv func(string) string) (Config, error) {
+	var cfg Config
+	err := cfg.ApplyEnv(getenv)
+	return cfg, err
+}
+
+// ApplyEnv overrides c with any of these environment variables that are set:
+//
+//	PERCH_NOTIFY         delivery methods: comma-separated osc9, osc777, bell
+//	PERCH_NOTIFY_CMD     command hook, e.g. "notify-send -u critical"
+//	PERCH_NOTIFY_EVENTS  comma-separated kinds to enable (default all)
+//	PERCH_NOTIFY_QUIET   quiet hours as HH:MM-HH:MM, e.g. 22:00-07:00
+func (c *Config) ApplyEnv(getenv func(string) string) error {
+	if methods := getenv("PERCH_NOTIFY"); methods != "" {
+		if err := c.SetMethods(splitList(methods)); err != nil {
+			return fmt.Errorf("PERCH_NOTIFY: %w", err)
+		}
+	}
+	if cmd := getenv("PERCH_NOTIFY_CMD"); cmd != "" {
+		c.Command = strings.Fields(cmd)
+	}
+	if events := splitList(getenv("PERCH_NOTIFY_EVENTS")); len(events) > 0 {
+		kinds, err := ParseKinds(events)
+		if err != nil {
+			return fmt.Errorf("PERCH_NOTIFY_EVENTS: %w", err)
+		}
+		c.Kinds = kinds
+	}
+	if quiet := getenv("PERCH_NOTIFY_QUIET"); quiet != "" {
+		start, end, err := ParseQuietHours(quiet)
+		if err != nil {
+			return fmt.Errorf("PERCH_NOTIFY_QUIET: %w", err)
+		}
+		c.QuietStart, c.QuietEnd = start, end
+	}
+	return nil
+}
+
+// SetMethods replaces the terminal delivery methods with osc9, osc777 and/or bell.
+func (c *Config) SetMethods(methods []string) error {
+	c.OSC9, c.OSC777, c.Bell = false, false, false
+	for _, method := range methods {
+		switch strings.ToLower(strings.TrimSpace(method)) {
+		case "osc9":
+			c.OSC9 = true
+		case "osc777":
+			c.OSC777 = true
+		case "bell":
+			c.Bell = true
+		default:
+			return fmt.Errorf("unknown method %q (want osc9, osc777 or bell)", method)
+		}
+	}
+	return nil
+}
+
+// ParseKinds converts kind names into an enabled set.
+func ParseKinds(names []string) (map[Kind]bool, error) {
+	kinds := make(map[Kind]bool, len(names))
+	for _, name := range names {
+		known := false
+		for _, k := range AllKinds {
+			if string(k) == name {
+				kinds[k] = true
+				known = true
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown event %q", name)
+		}
+	}
+	return kinds, nil
+}
+
+// ParseQuietHours parses "HH:MM-HH:MM" into offsets from midnight.
+func ParseQuietHours(s string) (start, end time.Duration, err error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid quiet hours %q (want HH:MM-HH:MM)", s)
+	}
+	if start, err = parseClock(from); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parseClock(to); err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// parseClock parses "HH:MM" into an offset from midnight.
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// splitList splits a comma-separated list, dropping blanks.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(strings.ToLower(part)); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// Notifier watches successive snapshots and delivers notifications.
+// It is safe for concurrent use.
+type Notifier struct {
+	Config Config
+
+	// Out receives OSC sequences and the bell; usually the controlling terminal.
+	Out io.Writer
+
+	// Runner executes the command hook. If nil, uses real exec.
+	Runner data.CommandRunner
+
+	// Now returns the current time. If nil, uses time.Now.
+	Now func() time.Time
+
+	mu   sync.Mutex
+	last *data.Snapshot
+	sent map[string]time.Time // Key -> last delivery
+}
+
+// New creates a notifier writing terminal notifications to out.
+func New(cfg Config, out io.Writer) *Notifier {
+	return &Notifier{Config: cfg, Out: out, Runner: &execRunner{}}
+}
+
+// SetConfig changes which notifications are raised and how. The last
+// snapshot and delivery history are kept, so conditions already notified
+// are not raised again.
+func (n *Notifier) SetConfig(cfg Config) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.Config = cfg
+}
+
+// Observe compares snap with the previously observed snapshot and delivers
+// any new notifications. It returns the notifications that were delivered
+// and the first delivery error, if any.
+func (n *Notifier) Observe(ctx context.Context, snap *data.Snapshot) ([]Notification, error) {
+	if snap == nil {
+		return nil, nil
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	pending := Detect(n.last, snap, n.Config.StaleMRThreshold)
+	n.last = snap
+
+	now := n.now()
+	window := n.Config.DedupWindow
+	if window <= 0 {
+		window = DefaultDedupWindow
+	}
+	if n.sent == nil {
+		n.sent = make(map[string]time.Time)
+	}
+
+	var delivered []Notification
+	var firstErr error
+	for _, note := range pending {
+		if !n.Config.wants(note.Kind) {
+			continue
+		}
+		if last, ok := n.sent[note.Key]; ok && now.Sub(last) < window {
+			continue
+		}
+		// Record even when quiet so the alert does not fire once quiet hours end
+		n.sent[note.Key] = now
+		if n.Config.quiet(now) {
+			continue
+		}
+		if err := n.send(ctx, note); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delivered = append(delivered, note)
+	}
+
+	// Forget keys once they can no longer suppress anything
+	for key, at := range n.sent {
+		if now.Sub(at) >= window {
+			delete(n.sent, key)
+		}
+	}
+	return delivered, firstErr
+}
+
+// send delivers one notification by every configured method.
+func (n *Notifier) send(ctx context.Context, note Notification) error {
+	title, body := sanitize(note.Title), sanitize(note.Body)
+
+	var seq strings.Builder
+	if n.Config.OSC9 {
+		msg := title
+		if body != "" {
+			msg += ": " + body
+		}
+		fmt.Fprintf(&seq, "\x1b]9;%s\x07", msg)
+	}
+	if n.Config.OSC777 {
+		fmt.Fprintf(&seq, "\x1b]777;notify;%s;%s\x07", strings.ReplaceAll(title, ";", ","), body)
+	}
+	if n.Config.Bell {
+		seq.WriteString("\a")
+	}
+	if seq.Len() > 0 && n.Out != nil {
+		if _, err := io.WriteString(n.Out, seq.String()); err != nil {
+			return fmt.Errorf("writing terminal notification: %w", err)
+		}
+	}
+
+	if len(n.Config.Command) > 0 {
+		runner := n.Runner
+		if runner == nil {
+			runner = &execRunner{}
+		}
+		ctx, cancel := context.WithTimeout(ctx, hookTimeout)
+		defer cancel()
+		args := append(append([]string{}, n.Config.Command...), title, body)
+		if _, stderr, err := runner.Exec(ctx, "", args...); err != nil {
+			if msg := strings.TrimSpace(string(stderr)); msg != "" {
+				return fmt.Errorf("notify hook %s: %w: %s", n.Config.Command[0], err, msg)
+			}
+			return fmt.Errorf("notify hook %s: %w", n.Config.Command[0], err)
+		}
+	}
+	return nil
+}
+
+func (n *Notifier) now() time.Time {
+	if n.Now != nil {
+		return n.Now()
+	}
+	return time.Now()
+}
+
+// sanitize strips control characters that would end or corrupt an escape sequence.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return ' '
+		case r < 0x20 || r == 0x7f:
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// execRunner executes the command hook using os/exec.
+type execRunner struct{}
+
+func (r *execRunner) Exec(ctx context.Context, workDir string, args ...string) ([]byte, []byte, error) {
+	if len(args) == 0 {
+		return nil, nil, fmt.Errorf("no command specified")
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = workDir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
@@ -0,0 +1,216 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+// newTestNotifier returns a notifier with a pinned clock writing to buf.
+func newTestNotifier(cfg Config, buf *bytes.Buffer, now *time.Time) *Notifier {
+	n := New(cfg, buf)
+	n.Runner = testutil.NewMockRunner()
+	n.Now = func() time.Time { return *now }
+	return n
+}
+
+// stall returns a snapshot pair where the perch refinery goes down.
+func stall(at time.Time) (*data.Snapshot, *data.Snapshot) {
+	prev, next := baseSnapshot(at), baseSnapshot(at.Add(5*time.Second))
+	next.Town.Rigs[0].Agents[0].Running = false
+	return prev, next
+}
+
+func TestNotifierDelivery(t *testing.T) {
+	var buf bytes.Buffer
+	now := t0
+	n := newTestNotifier(Config{OSC9: true, OSC777: true, Bell: true}, &buf, &now)
+
+	prev, next := stall(t0)
+	n.Observe(context.Background(), prev)
+	delivered, err := n.Observe(context.Background(), next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delivered) != 1 {
+		t.Fatalf("expected 1 delivery, got %d", len(delivered))
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"\x1b]9;Refinery stalled: perch: 1 MRs waiting",
+		"\x1b]777;notify;Refinery stalled: perch;1 MRs waiting",
+		"\a",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output %q", want, out)
+		}
+	}
+}
+
+func TestNotifierCommandHook(t *testing.T) {
+	var buf bytes.Buffer
+	now := t0
+	n := newTestNotifier(Config{Command: []string{"notify-send", "-u", "critical"}}, &buf, &now)
+	mock := n.Runner.(*testutil.MockRunner)
+
+	prev, next := stall(t0)
+	n.Observe(context.Background(), prev)
+	if _, err := n.Observe(context.Background(), next); err != nil {
+		t.Fatal(err)
+	}
+	calls := mock.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 hook call, got %d", len(calls))
+	}
+	args := calls[0].Args
+	if args[0] != "notify-send" || args[3] != "Refinery stalled: perch" || !strings.HasPrefix(args[4], "1 MRs") {
+		t.Errorf("unexpected hook args %q", args)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no terminal output, got %q", buf.String())
+	}
+
+	mock.On([]string{"notify-send"}, nil, []byte("no display"), errors.New("exit status 1"))
+	now = now.Add(time.Hour)
+	n.Observe(context.Background(), prev)
+	_, err := n.Observe(context.Background(), next)
+	if err == nil || !strings.Contains(err.Error(), "no display") {
+		t.Errorf("expected hook error with stderr, got %v", err)
+	}
+}
+
+func TestNotifierDedup(t *testing.T) {
+	var buf bytes.Buffer
+	now := t0
+	n := newTestNotifier(Config{Bell: true, DedupWindow: 10 * time.Minute}, &buf, &now)
+	prev, next := stall(t0)
+
+	n.Observe(context.Background(), prev)
+	if got, _ := n.Observe(context.Background(), next); len(got) != 1 {
+		t.Fatalf("expected first stall to notify, got %d", len(got))
+	}
+
+	// Refinery flaps within the window
+	now = now.Add(time.Minute)
+	n.Observe(context.Background(), prev)
+	if got, _ := n.Observe(context.Background(), next); len(got) != 0 {
+		t.Errorf("expected repeat within window to be suppressed, got %d", len(got))
+	}
+
+	// And again after the window
+	now = now.Add(15 * time.Minute)
+	n.Observe(context.Background(), prev)
+	if got, _ := n.Observe(context.Background(), next); len(got) != 1 {
+		t.Errorf("expected repeat after window to notify, got %d", len(got))
+	}
+}
+
+func TestNotifierKindFilter(t *testing.T) {
+	var buf bytes.Buffer
+	now := t0
+	n := newTestNotifier(Config{Bell: true, Kinds: map[Kind]bool{KindCrash: true}}, &buf, &now)
+	prev, next := stall(t0)
+	n.Observe(context.Background(), prev)
+	if got, _ := n.Observe(context.Background(), next); len(got) != 0 {
+		t.Errorf("expected disabled kind to be dropped, got %d", len(got))
+	}
+}
+
+func TestNotifierQuietHours(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2026, 1, 8, 23, 30, 0, 0, time.UTC)
+	n := newTestNotifier(Config{Bell: true, QuietStart: 22 * time.Hour, QuietEnd: 7 * time.Hour}, &buf, &now)
+	prev, next := stall(now)
+
+	n.Observe(context.Background(), prev)
+	if got, _ := n.Observe(context.Background(), next); len(got) != 0 || buf.Len() != 0 {
+		t.Errorf("expected nothing during quiet hours, got %d", len(got))
+	}
+
+	// Still suppressed just after quiet hours end, since it was already raised
+	now = time.Date(2026, 1, 9, 7, 0, 0, 0, time.UTC)
+	n.Config.DedupWindow = 12 * time.Hour
+	n.Observe(context.Background(), prev)
+	if got, _ := n.Observe(context.Background(), next); len(got) != 0 {
+		t.Errorf("expected quiet-hours alert not to replay, got %d", len(got))
+	}
+}
+
+func TestConfigQuiet(t *testing.T) {
+	day := func(h, m int) time.Time { return time.Date(2026, 1, 8, h, m, 0, 0, time.UTC) }
+	overnight := Config{QuietStart: 22 * time.Hour, QuietEnd: 7 * time.Hour}
+	daytime := Config{QuietStart: 12 * time.Hour, QuietEnd: 13 * time.Hour}
+
+	tests := []struct {
+		cfg  Config
+		at   time.Time
+		want bool
+	}{
+		{overnight, day(23, 0), true},
+		{overnight, day(3, 0), true},
+		{overnight, day(7, 0), false},
+		{overnight, day(12, 0), false},
+		{daytime, day(12, 30), true},
+		{daytime, day(13, 0), false},
+		{Config{}, day(3, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.quiet(tt.at); got != tt.want {
+			t.Errorf("quiet(%s) with %v-%v = %v, want %v", tt.at.Format("15:04"), tt.cfg.QuietStart, tt.cfg.QuietEnd, got, tt.want)
+		}
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	env := map[string]string{
+		"PERCH_NOTIFY":        "osc9, bell",
+		"PERCH_NOTIFY_CMD":    "notify-send -u critical",
+		"PERCH_NOTIFY_EVENTS": "crash,p0_bead",
+		"PERCH_NOTIFY_QUIET":  "22:00-07:30",
+	}
+	cfg, err := ConfigFromEnv(func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.OSC9 || cfg.OSC777 || !cfg.Bell {
+		t.Errorf("unexpected methods: %+v", cfg)
+	}
+	if strings.Join(cfg.Command, " ") != "notify-send -u critical" {
+		t.Errorf("unexpected command %q", cfg.Command)
+	}
+	if !cfg.wants(KindCrash) || cfg.wants(KindLoadError) {
+		t.Errorf("unexpected kinds %v", cfg.Kinds)
+	}
+	if cfg.QuietStart != 22*time.Hour || cfg.QuietEnd != 7*time.Hour+30*time.Minute {
+		t.Errorf("unexpected quiet hours %v-%v", cfg.QuietStart, cfg.QuietEnd)
+	}
+
+	empty, err := ConfigFromEnv(func(string) string { return "" })
+	if err != nil || empty.Enabled() {
+		t.Errorf("expected disabled config from empty env, got %+v, %v", empty, err)
+	}
+
+	for _, bad := range []map[string]string{
+		{"PERCH_NOTIFY": "smoke-signal"},
+		{"PERCH_NOTIFY_EVENTS": "meteor"},
+		{"PERCH_NOTIFY_QUIET": "late"},
+		{"PERCH_NOTIFY_QUIET": "22:00-25:00"},
+	} {
+		if _, err := ConfigFromEnv(func(k string) string { return bad[k] }); err == nil {
+			t.Errorf("expected error for %v", bad)
+		}
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	if got := sanitize("a\x1b]9;b\x07c\nd"); got != "a]9;bc d" {
+		t.Errorf("sanitize = %q", got)
+	}
+}
@@ -0,0 +1,346 @@
+// Package server exposes the data Store and ActionRunner over HTTP/JSON.
+//
+// Read endpoints serve the Store's cached snapshot, /events streams each
+// refresh to subscribers as Server-Sent Events, and /metrics exports town
+// health for Prometheus. Write endpoints map
+// one-to-one onto ActionRunner methods, so they run the same gt/bd commands
+// as the TUI through the same CommandRunner.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/metrics"
+	"github.com/andyrewlee/perch/internal/tui"
+)
+
+// actionTimeout bounds a single write action.
+const actionTimeout = 30 * time.Second
+
+// maxBodyBytes bounds write request bodies.
+const maxBodyBytes = 1 << 20
+
+// Options configures a Server.
+type Options struct {
+	// Token, if set, is required as "Authorization: Bearer <token>" on every request.
+	Token string
+
+	// ReadOnly rejects all write endpoints.
+	ReadOnly bool
+}
+
+// Server serves town data and actions over HTTP.
+type Server struct {
+	store   *data.Store
+	actions *tui.ActionRunner
+	opts    Options
+	mux     *http.ServeMux
+	hub     *hub
+	metrics *metrics.Collector
+}
+
+// New creates a server backed by store and actions.
+// It chains onto store.OnRefresh to stream refreshes, so call it before
+// starting the store's auto-refresh.
+func New(store *data.Store, actions *tui.ActionRunner, opts Options) *Server {
+	s := &Server{
+		store:   store,
+		actions: actions,
+		opts:    opts,
+		mux:     http.NewServeMux(),
+		hub:     newHub(),
+		metrics: metrics.NewCollector(),
+	}
+	s.metrics.Commands = store.CommandStats()
+	s.hub.last = store.Snapshot()
+	s.metrics.Observe(s.hub.last)
+
+	prev := store.OnRefresh
+	store.OnRefresh = func(snap *data.Snapshot) {
+		if prev != nil {
+			prev(snap)
+		}
+		s.metrics.Observe(snap)
+		s.hub.publish(snap)
+	}
+
+	s.routes()
+	return s
+}
+
+// routes registers all endpoints.
+func (s *Server) routes() {
+	// Read endpoints
+	s.mux.HandleFunc("GET /snapshot", s.handleSnapshot)
+	s.mux.HandleFunc("GET /rigs", s.handleRigs)
+	s.mux.HandleFunc("GET /convoys", s.handleConvoys)
+	s.mux.HandleFunc("GET /mq/{rig}", s.handleMergeQueue)
+	s.mux.HandleFunc("GET /issues", s.handleIssues)
+	s.mux.HandleFunc("GET /mail", s.handleMail)
+	s.mux.HandleFunc("GET /errors", s.handleErrors)
+	s.mux.HandleFunc("GET /events", s.handleEvents)
+	s.mux.HandleFunc("GET /metrics", s.handleMetrics)
+
+	// Write endpoints
+	s.mux.HandleFunc("POST /rigs/{rig}/boot", s.write(s.handleBootRig))
+	s.mux.HandleFunc("POST /rigs/{rig}/shutdown", s.write(s.handleShutdownRig))
+	s.mux.HandleFunc("POST /agents/nudge", s.write(s.handleNudge))
+	s.mux.HandleFunc("POST /sling", s.write(s.handleSling))
+	s.mux.HandleFunc("POST /beads/{id}/close", s.write(s.handleCloseBead))
+	s.mux.HandleFunc("POST /mq/{rig}/{id}/retry", s.write(s.handleMQRetry))
+}
+
+// ServeHTTP implements http.Handler, enforcing token auth.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Token != "" && !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="perch"`)
+		writeError(w, http.StatusUnauthorized, "missing or invalid token")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// authorized checks the bearer token in constant time.
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1
+}
+
+// snapshot returns the cached snapshot, writing 503 if none is loaded yet.
+func (s *Server) snapshot(w http.ResponseWriter) *data.Snapshot {
+	snap := s.store.Snapshot()
+	if snap == nil {
+		writeError(w, http.StatusServiceUnavailable, "no snapshot loaded yet")
+	}
+	return snap
+}
+
+func (s *Server) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	if snap := s.snapshot(w); snap != nil {
+		writeJSON(w, http.StatusOK, snap)
+	}
+}
+
+func (s *Server) handleRigs(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot(w)
+	if snap == nil {
+		return
+	}
+	rigs := []data.Rig{}
+	if snap.Town != nil {
+		rigs = snap.Town.Rigs
+	}
+	writeJSON(w, http.StatusOK, rigs)
+}
+
+func (s *Server) handleConvoys(w http.ResponseWriter, r *http.Request) {
+	if snap := s.snapshot(w); snap != nil {
+		writeJSON(w, http.StatusOK, nonNil(snap.Convoys))
+	}
+}
+
+func (s *Server) handleMergeQueue(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot(w)
+	if snap == nil {
+		return
+	}
+	rig := r.PathValue("rig")
+	mrs, ok := snap.MergeQueues[rig]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown rig %q", rig))
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNil(mrs))
+}
+
+// handleIssues serves issues, optionally filtered by ?status=.
+func (s *Server) handleIssues(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot(w)
+	if snap == nil {
+		return
+	}
+	status := r.URL.Query().Get("status")
+	issues := []data.Issue{}
+	for _, issue := range snap.Issues {
+		if status == "" || issue.Status == status {
+			issues = append(issues, issue)
+		}
+	}
+	writeJSON(w, http.StatusOK, issues)
+}
+
+func (s *Server) handleMail(w http.ResponseWriter, r *http.Request) {
+	if snap := s.snapshot(w); snap != nil {
+		writeJSON(w, http.StatusOK, nonNil(snap.Mail))
+	}
+}
+
+// handleMetrics serves the Prometheus text exposition format.
+// Before the first load it still serves the refresh counters.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", metrics.ContentType)
+	_ = s.metrics.Write(w, s.store.Snapshot(), time.Now())
+}
+
+// errorView is a LoadError with its derived guidance.
+type errorView struct {
+	data.LoadError
+	SourceLabel     string `json:"source_label"`
+	SuggestedAction string `json:"suggested_action"`
+}
+
+func (s *Server) handleErrors(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot(w)
+	if snap == nil {
+		return
+	}
+	views := make([]errorView, 0, len(snap.LoadErrors))
+	for _, e := range snap.LoadErrors {
+		views = append(views, errorView{
+			LoadError:       e,
+			SourceLabel:     e.SourceLabel(),
+			SuggestedAction: e.SuggestedAction(),
+		})
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+// actionResult is the response body for a successful write.
+type actionResult struct {
+	OK     bool   `json:"ok"`
+	Action string `json:"action"`
+	Target string `json:"target"`
+}
+
+// actionFunc runs a write action and returns its name and target.
+type actionFunc func(ctx context.Context, r *http.Request) (action, target string, err error)
+
+// write wraps an action with the read-only check, a timeout, and JSON results.
+func (s *Server) write(fn actionFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.opts.ReadOnly {
+			writeError(w, http.StatusForbidden, "server is read-only")
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+
+		ctx, cancel := context.WithTimeout(r.Context(), actionTimeout)
+		defer cancel()
+
+		action, target, err := fn(ctx, r)
+		if err != nil {
+			var bad *badRequestError
+			if errors.As(err, &bad) {
+				writeError(w, http.StatusBadRequest, bad.msg)
+				return
+			}
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, actionResult{OK: true, Action: action, Target: target})
+	}
+}
+
+// badRequestError marks a client error in a write request.
+type badRequestError struct{ msg string }
+
+func (e *badRequestError) Error() string { return e.msg }
+
+// decodeBody decodes a JSON request body into v.
+func decodeBody(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return &badRequestError{msg: "invalid JSON body: " + err.Error()}
+	}
+	return nil
+}
+
+// require returns a bad request error naming the first empty field.
+func require(fields ...string) error {
+	for i := 0; i+1 < len(fields); i += 2 {
+		if strings.TrimSpace(fields[i+1]) == "" {
+			return &badRequestError{msg: fields[i] + " is required"}
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleBootRig(ctx context.Context, r *http.Request) (string, string, error) {
+	rig := r.PathValue("rig")
+	return "boot_rig", rig, s.actions.BootRig(ctx, rig)
+}
+
+func (s *Server) handleShutdownRig(ctx context.Context, r *http.Request) (string, string, error) {
+	rig := r.PathValue("rig")
+	return "shutdown_rig", rig, s.actions.ShutdownRig(ctx, rig)
+}
+
+func (s *Server) handleNudge(ctx context.Context, r *http.Request) (string, string, error) {
+	var body struct {
+		Address string `json:"address"`
+		Message string `json:"message"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		return "", "", err
+	}
+	if err := require("address", body.Address, "message", body.Message); err != nil {
+		return "", "", err
+	}
+	return "nudge", body.Address, s.actions.NudgeAgent(ctx, body.Address, body.Message)
+}
+
+func (s *Server) handleSling(ctx context.Context, r *http.Request) (string, string, error) {
+	var body struct {
+		Bead  string `json:"bead"`
+		Agent string `json:"agent"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		return "", "", err
+	}
+	if err := require("bead", body.Bead, "agent", body.Agent); err != nil {
+		return "", "", err
+	}
+	return "sling", body.Bead + " -> " + body.Agent, s.actions.SlingWork(ctx, body.Bead, body.Agent)
+}
+
+func (s *Server) handleCloseBead(ctx context.Context, r *http.Request) (string, string, error) {
+	id := r.PathValue("id")
+	return "close_bead", id, s.actions.CloseBead(ctx, id)
+}
+
+func (s *Server) handleMQRetry(ctx context.Context, r *http.Request) (string, string, error) {
+	rig, id := r.PathValue("rig"), r.PathValue("id")
+	return "mq_retry", rig + "/" + id, s.actions.MQRetry(ctx, id, rig)
+}
+
+// nonNil returns an empty slice for nil so JSON encodes [] instead of null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
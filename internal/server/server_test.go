@@ -0,0 +1,279 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/testutil"
+	"github.com/andyrewlee/perch/internal/tui"
+)
+
+// newTestServer returns a server whose store has loaded fixture data.
+func newTestServer(t *testing.T, opts Options) (*httptest.Server, *testutil.MockRunner) {
+	t.Helper()
+	srv, mock, _ := newTestServerWithHandler(t, opts)
+	return srv, mock
+}
+
+// newTestServerWithHandler is newTestServer that also returns the handler,
+// so tests can refresh its store and inspect its hub.
+func newTestServerWithHandler(t *testing.T, opts Options) (*httptest.Server, *testutil.MockRunner, *Server) {
+	t.Helper()
+	fixtures := testutil.NewFixtures()
+	mock := testutil.NewMockRunner()
+	mock.On([]string{"gt", "status", "--json"}, fixtures.TownStatusJSON(), nil, nil)
+	mock.On([]string{"gt", "convoy", "list"}, fixtures.ConvoysJSON(), nil, nil)
+	mock.On([]string{"bd", "list", "--json", "--limit", "0"}, fixtures.IssuesJSON(), nil, nil)
+	mock.OnFunc([]string{"gt", "mq", "list"}, func(args []string) ([]byte, []byte, error) {
+		if len(args) >= 4 {
+			return fixtures.MergeQueueJSON(args[3]), nil, nil
+		}
+		return []byte("[]"), nil, nil
+	})
+
+	store := data.NewStoreWithLoader(data.NewLoaderWithRunner("/tmp/town", mock))
+	store.Refresh(context.Background())
+
+	handler := New(store, tui.NewActionRunnerWithRunner("/tmp/town", mock), opts)
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv, mock, handler
+}
+
+// waitForSubscriber blocks until the server's hub has a stream subscriber.
+func waitForSubscriber(t *testing.T, s *Server) {
+	t.Helper()
+	h := s.hub
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		h.mu.Lock()
+		n := len(h.subs)
+		h.mu.Unlock()
+		if n > 0 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for stream subscriber")
+}
+
+func doRequest(t *testing.T, method, url, token, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestReadEndpoints(t *testing.T) {
+	srv, _ := newTestServer(t, Options{})
+
+	t.Run("snapshot", func(t *testing.T) {
+		resp := doRequest(t, "GET", srv.URL+"/snapshot", "", "")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		var snap data.Snapshot
+		if err := json.NewDecoder(resp.Body).Decode(&snap); err != nil {
+			t.Fatalf("decoding snapshot: %v", err)
+		}
+		if snap.Town == nil || snap.Town.Name != "test-town" {
+			t.Errorf("expected test-town snapshot, got %+v", snap.Town)
+		}
+	})
+
+	t.Run("rigs", func(t *testing.T) {
+		var rigs []data.Rig
+		resp := doRequest(t, "GET", srv.URL+"/rigs", "", "")
+		json.NewDecoder(resp.Body).Decode(&rigs)
+		if len(rigs) != 2 {
+			t.Errorf("expected 2 rigs, got %d", len(rigs))
+		}
+	})
+
+	t.Run("mq", func(t *testing.T) {
+		var mrs []data.MergeRequest
+		resp := doRequest(t, "GET", srv.URL+"/mq/perch", "", "")
+		json.NewDecoder(resp.Body).Decode(&mrs)
+		if len(mrs) != 2 {
+			t.Errorf("expected 2 MRs, got %d", len(mrs))
+		}
+	})
+
+	t.Run("mq unknown rig", func(t *testing.T) {
+		resp := doRequest(t, "GET", srv.URL+"/mq/nope", "", "")
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("issues filtered by status", func(t *testing.T) {
+		var issues []data.Issue
+		resp := doRequest(t, "GET", srv.URL+"/issues?status=open", "", "")
+		json.NewDecoder(resp.Body).Decode(&issues)
+		if len(issues) != 1 || issues[0].ID != "gt-002" {
+			t.Errorf("expected only gt-002, got %+v", issues)
+		}
+	})
+
+	t.Run("mail is an empty list, not null", func(t *testing.T) {
+		resp := doRequest(t, "GET", srv.URL+"/mail", "", "")
+		var raw json.RawMessage
+		json.NewDecoder(resp.Body).Decode(&raw)
+		if strings.TrimSpace(string(raw)) != "[]" {
+			t.Errorf("expected [], got %s", raw)
+		}
+	})
+
+	t.Run("errors include suggested action", func(t *testing.T) {
+		var errs []map[string]any
+		resp := doRequest(t, "GET", srv.URL+"/errors", "", "")
+		json.NewDecoder(resp.Body).Decode(&errs)
+		for _, e := range errs {
+			if e["suggested_action"] == "" || e["source_label"] == "" {
+				t.Errorf("expected guidance on error %+v", e)
+			}
+		}
+	})
+}
+
+func TestNoSnapshotYet(t *testing.T) {
+	store := data.NewStoreWithLoader(data.NewLoaderWithRunner("/tmp/town", testutil.NewMockRunner()))
+	srv := httptest.NewServer(New(store, tui.NewActionRunnerWithRunner("/tmp/town", testutil.NewMockRunner()), Options{}))
+	defer srv.Close()
+
+	resp := doRequest(t, "GET", srv.URL+"/snapshot", "", "")
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 before first refresh, got %d", resp.StatusCode)
+	}
+}
+
+func TestWriteEndpoints(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		body string
+		want []string
+	}{
+		{"boot rig", "/rigs/perch/boot", "", []string{"gt", "rig", "boot", "perch"}},
+		{"shutdown rig", "/rigs/perch/shutdown", "", []string{"gt", "rig", "shutdown", "perch"}},
+		{"nudge", "/agents/nudge", `{"address":"perch/polecats/able","message":"wake up"}`, []string{"gt", "nudge", "perch/polecats/able"}},
+		{"sling", "/sling", `{"bead":"gt-001","agent":"perch/polecats/able"}`, []string{"gt", "sling", "gt-001", "perch/polecats/able"}},
+		{"close bead", "/beads/gt-001/close", "", []string{"bd", "close", "gt-001"}},
+		{"mq retry", "/mq/perch/mr-001/retry", "", []string{"gt", "mq", "retry", "mr-001", "--rig", "perch"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, mock := newTestServer(t, Options{})
+			resp := doRequest(t, "POST", srv.URL+tt.path, "", tt.body)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected 200, got %d", resp.StatusCode)
+			}
+			if !mock.CalledWith(tt.want) {
+				t.Errorf("expected %v to be called, calls: %+v", tt.want, mock.Calls())
+			}
+		})
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	t.Run("missing field", func(t *testing.T) {
+		srv, _ := newTestServer(t, Options{})
+		resp := doRequest(t, "POST", srv.URL+"/sling", "", `{"bead":"gt-001"}`)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		srv, _ := newTestServer(t, Options{})
+		resp := doRequest(t, "POST", srv.URL+"/agents/nudge", "", `{`)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("command failure", func(t *testing.T) {
+		srv, mock := newTestServer(t, Options{})
+		mock.On([]string{"bd", "close"}, nil, []byte("no such bead"), errors.New("exit status 1"))
+		resp := doRequest(t, "POST", srv.URL+"/beads/gt-999/close", "", "")
+		if resp.StatusCode != http.StatusBadGateway {
+			t.Errorf("expected 502, got %d", resp.StatusCode)
+		}
+		var body map[string]string
+		json.NewDecoder(resp.Body).Decode(&body)
+		if !strings.Contains(body["error"], "no such bead") {
+			t.Errorf("expected stderr in error, got %q", body["error"])
+		}
+	})
+}
+
+func TestReadOnly(t *testing.T) {
+	srv, mock := newTestServer(t, Options{ReadOnly: true})
+
+	resp := doRequest(t, "POST", srv.URL+"/rigs/perch/boot", "", "")
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", resp.StatusCode)
+	}
+	if mock.CalledWith([]string{"gt", "rig", "boot"}) {
+		t.Error("read-only server should not run commands")
+	}
+
+	if resp := doRequest(t, "GET", srv.URL+"/rigs", "", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("reads should still work, got %d", resp.StatusCode)
+	}
+}
+
+func TestTokenAuth(t *testing.T) {
+	srv, _ := newTestServer(t, Options{Token: "s3cret"})
+
+	if resp := doRequest(t, "GET", srv.URL+"/rigs", "", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without token, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(t, "GET", srv.URL+"/rigs", "wrong", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 with wrong token, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(t, "GET", srv.URL+"/rigs", "s3cret", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 with token, got %d", resp.StatusCode)
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	srv, _, handler := newTestServerWithHandler(t, Options{})
+	handler.store.Refresh(context.Background())
+
+	resp := doRequest(t, "GET", srv.URL+"/metrics", "", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("expected text/plain, got %q", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	for _, line := range []string{
+		`perch_merge_queue_depth{rig="perch"} 2`,
+		"perch_refreshes_total 2",
+		`perch_command_calls_total{command="gt mq list"} 4`,
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("missing %q in metrics:\n%s", line, body)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// streamHeartbeat is how often idle streams send a keep-alive comment.
+const streamHeartbeat = 15 * time.Second
+
+// subscriberBuffer is how many updates a subscriber may fall behind before it is dropped.
+const subscriberBuffer = 16
+
+// update is one refresh fanned out to stream subscribers.
+type update struct {
+	snapshot *data.Snapshot
+	changes  []data.ChangeEvent
+}
+
+// hub fans refreshed snapshots out to stream subscribers.
+// Changes are diffed once per refresh, not once per subscriber.
+type hub struct {
+	mu   sync.Mutex
+	last *data.Snapshot
+	subs map[chan update]struct{}
+}
+
+func newHub() *hub {
+	return &hub{subs: make(map[chan update]struct{})}
+}
+
+// publish diffs snap against the previous refresh and sends it to every subscriber.
+// Subscribers that have fallen too far behind are dropped; clients reconnect
+// and resync from the initial snapshot.
+func (h *hub) publish(snap *data.Snapshot) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	u := update{snapshot: snap, changes: data.DiffSnapshots(h.last, snap)}
+	h.last = snap
+	for ch := range h.subs {
+		select {
+		case ch <- u:
+		default:
+			delete(h.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// subscribe registers a new subscriber.
+func (h *hub) subscribe() chan update {
+	ch := make(chan update, subscriberBuffer)
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch
+}
+
+// unsubscribe removes a subscriber if it is still registered.
+func (h *hub) unsubscribe(ch chan update) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[ch]; ok {
+		delete(h.subs, ch)
+		close(ch)
+	}
+}
+
+// handleEvents streams refreshes as Server-Sent Events.
+//
+// The stream opens with a "snapshot" event carrying the current snapshot.
+// After that, ?mode=changes (the default) sends a "changes" event with the
+// diff for each refresh that changed something, and ?mode=snapshot sends
+// every refreshed snapshot in full.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = "changes"
+	}
+	if mode != "changes" && mode != "snapshot" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown mode %q (want changes or snapshot)", mode))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	ch := s.hub.subscribe()
+	defer s.hub.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if snap := s.store.Snapshot(); snap != nil {
+		if err := writeEvent(w, "snapshot", snap); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case u, ok := <-ch:
+			if !ok {
+				// Dropped for falling behind
+				return
+			}
+			var err error
+			if mode == "snapshot" {
+				err = writeEvent(w, "snapshot", u.snapshot)
+			} else if len(u.changes) > 0 {
+				err = writeEvent(w, "changes", u.changes)
+			}
+			if err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes a single SSE event with a JSON payload.
+func writeEvent(w http.ResponseWriter, event string, v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
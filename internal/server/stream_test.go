@@ -0,0 +1,143 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+// sseEvent is a parsed Server-Sent Event.
+type sseEvent struct {
+	name string
+	data string
+}
+
+// readEvent reads the next event from an SSE stream, skipping comments.
+func readEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+	var ev sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if ev.name != "" {
+				return ev
+			}
+		case strings.HasPrefix(line, "event: "):
+			ev.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			ev.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func openStream(t *testing.T, url string) *bufio.Reader {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %q", ct)
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+func TestEventsStreamChanges(t *testing.T) {
+	srv, mock, handler := newTestServerWithHandler(t, Options{})
+	stream := openStream(t, srv.URL+"/events")
+
+	first := readEvent(t, stream)
+	if first.name != "snapshot" {
+		t.Fatalf("expected initial snapshot event, got %q", first.name)
+	}
+
+	// A new bead appears
+	var issues []map[string]any
+	if err := json.Unmarshal(testutil.NewFixtures().IssuesJSON(), &issues); err != nil {
+		t.Fatal(err)
+	}
+	issues = append(issues, map[string]any{"id": "gt-900", "title": "New work", "status": "open"})
+	body, _ := json.Marshal(issues)
+	mock.On([]string{"bd", "list", "--json", "--limit", "0"}, body, nil, nil)
+	waitForSubscriber(t, handler)
+	handler.store.Refresh(context.Background())
+
+	ev := readEvent(t, stream)
+	if ev.name != "changes" {
+		t.Fatalf("expected changes event, got %q", ev.name)
+	}
+	var changes []data.ChangeEvent
+	if err := json.Unmarshal([]byte(ev.data), &changes); err != nil {
+		t.Fatalf("decoding changes: %v", err)
+	}
+	var found bool
+	for _, c := range changes {
+		if c.Type == data.ChangeBeadCreated && c.ID == "gt-900" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected bead_created for gt-900, got %+v", changes)
+	}
+}
+
+func TestEventsStreamSnapshots(t *testing.T) {
+	srv, _, handler := newTestServerWithHandler(t, Options{})
+	stream := openStream(t, srv.URL+"/events?mode=snapshot")
+	readEvent(t, stream)
+
+	waitForSubscriber(t, handler)
+	handler.store.Refresh(context.Background())
+
+	ev := readEvent(t, stream)
+	if ev.name != "snapshot" {
+		t.Fatalf("expected snapshot event, got %q", ev.name)
+	}
+	var snap data.Snapshot
+	if err := json.Unmarshal([]byte(ev.data), &snap); err != nil {
+		t.Fatalf("decoding snapshot: %v", err)
+	}
+	if snap.Town == nil || snap.Town.Name != "test-town" {
+		t.Errorf("unexpected snapshot: %+v", snap.Town)
+	}
+}
+
+func TestEventsBadMode(t *testing.T) {
+	srv, _ := newTestServer(t, Options{})
+	resp := doRequest(t, "GET", srv.URL+"/events?mode=bogus", "", "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestHubDropsSlowSubscribers(t *testing.T) {
+	h := newHub()
+	ch := h.subscribe()
+	for i := 0; i <= subscriberBuffer; i++ {
+		h.publish(&data.Snapshot{})
+	}
+	// Drain the buffer; the channel must then be closed
+	for range subscriberBuffer {
+		<-ch
+	}
+	if _, ok := <-ch; ok {
+		t.Error("expected slow subscriber to be dropped")
+	}
+	h.unsubscribe(ch) // must not panic after drop
+}
@@ -0,0 +1,110 @@
+package testutil
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"testing/fstest"
+)
+
+// FS matches data.FS interface.
+// Defined here to avoid import cycles.
+type FS interface {
+	ReadFile(name string) ([]byte, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+	Stat(name string) (fs.FileInfo, error)
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	MkdirAll(name string, perm fs.FileMode) error
+	Remove(name string) error
+}
+
+// Ensure MemFS implements FS.
+var _ FS = (*MemFS)(nil)
+
+// MemFS is an in-memory filesystem for testing loaders without touching
+// the disk. Paths are absolute, like the town paths loaders build.
+// Directories exist once a file is added beneath them or they are made.
+type MemFS struct {
+	mu    sync.RWMutex
+	files fstest.MapFS
+}
+
+// NewMemFS creates an empty in-memory filesystem.
+func NewMemFS() *MemFS {
+	return &MemFS{files: fstest.MapFS{}}
+}
+
+// Add adds a file with the given content.
+// Returns the MemFS for chaining.
+func (m *MemFS) Add(name, content string) *MemFS {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[memPath(name)] = &fstest.MapFile{Data: []byte(content), Mode: 0644}
+	return m
+}
+
+// ReadFile implements FS.
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.files.ReadFile(memPath(name))
+}
+
+// ReadDir implements FS.
+func (m *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.files.ReadDir(memPath(name))
+}
+
+// Stat implements FS.
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.files.Stat(memPath(name))
+}
+
+// WriteFile implements FS. Like os.WriteFile, the parent directory must
+// exist.
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := memPath(name)
+	if dir := path.Dir(key); dir != "." {
+		if info, err := m.files.Stat(dir); err != nil || !info.IsDir() {
+			return &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+	}
+	m.files[key] = &fstest.MapFile{Data: append([]byte(nil), data...), Mode: perm}
+	return nil
+}
+
+// MkdirAll implements FS.
+func (m *MemFS) MkdirAll(name string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[memPath(name)] = &fstest.MapFile{Mode: fs.ModeDir | perm}
+	return nil
+}
+
+// Remove implements FS.
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := memPath(name)
+	if _, ok := m.files[key]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	delete(m.files, key)
+	return nil
+}
+
+// memPath converts an absolute path to a MapFS key.
+func memPath(name string) string {
+	key := strings.TrimPrefix(path.Clean(name), "/")
+	if key == "" {
+		return "."
+	}
+	return key
+}
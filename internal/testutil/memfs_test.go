@@ -0,0 +1,33 @@
+package testutil
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+func TestMemFS(t *testing.T) {
+	m := NewMemFS().Add("/town/logs/town.log", "line\n")
+
+	if data, err := m.ReadFile("/town/logs/town.log"); err != nil || string(data) != "line\n" {
+		t.Errorf("unexpected read %q %v", data, err)
+	}
+	entries, err := m.ReadDir("/town")
+	if err != nil || len(entries) != 1 || entries[0].Name() != "logs" || !entries[0].IsDir() {
+		t.Errorf("expected the logs directory, got %v %v", entries, err)
+	}
+	if _, err := m.Stat("/town/missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+
+	if err := m.WriteFile("/town/settings/config.json", []byte("{}"), 0644); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected a write without its directory to fail, got %v", err)
+	}
+	m.MkdirAll("/town/settings", 0755)
+	if err := m.WriteFile("/town/settings/config.json", []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := m.ReadFile("/town/settings/config.json"); string(data) != "{}" {
+		t.Errorf("expected the written file, got %q", data)
+	}
+}
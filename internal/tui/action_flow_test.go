@@ -10,6 +10,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/journal"
 	"github.com/andyrewlee/perch/internal/testutil"
 )
 
@@ -33,6 +34,8 @@ func createTestModel(t *testing.T) (Model, *testutil.MockRunner) {
 	mock := testutil.NewMockRunner()
 	mock.DefaultStdout = []byte("")
 	m.actionRunner = NewActionRunnerWithRunner(tmpDir, mock)
+	m.journal = journal.New(tmpDir + "/actions.jsonl")
+	m.store.History = data.NewHistory(tmpDir + "/history")
 
 	// Set a selected rig for tests that need it
 	m.selectedRig = "perch"
@@ -558,6 +561,7 @@ func TestIsDestructiveComplete(t *testing.T) {
 		{ActionReplyMail, false},
 		{ActionBulkMailRead, false},
 		{ActionBulkMailArchive, false},
+		{ActionFixDoctorCheck, true},
 	}
 
 	for _, tt := range tests {
@@ -598,6 +602,7 @@ func TestActionNameComplete(t *testing.T) {
 		{ActionStopAgent, "Stop agent"},
 		{ActionNudgeAgent, "Nudge"},
 		{ActionMailAgent, "Mail"},
+		{ActionFixDoctorCheck, "Fix check"},
 	}
 
 	for _, tt := range tests {
@@ -610,6 +615,53 @@ func TestActionNameComplete(t *testing.T) {
 	}
 }
 
+// TestFixDoctorCheckAction tests running a doctor check's fix from the
+// operator console.
+func TestFixDoctorCheckAction(t *testing.T) {
+	m, mock := createTestModel(t)
+	mock.On([]string{"gt", "doctor", "--fix"}, []byte("Fixed"), nil, nil)
+	report := &data.DoctorReport{Checks: []data.DoctorCheck{
+		{Name: "town-config", Status: data.CheckPassed},
+		{Name: "orphan-sessions", Status: data.CheckWarning, Message: "2 orphaned sessions",
+			SuggestFix: "Run 'gt doctor --fix'", FixCommand: []string{"gt", "doctor", "--fix"}},
+		{Name: "tmux", Status: data.CheckError, Message: "tmux not found", SuggestFix: "Install tmux"},
+	}}
+	m.doctorReport = report
+	m.sidebar.UpdateFromSnapshot(&data.Snapshot{DoctorReport: report})
+	m.focus = PanelSidebar
+	m.sidebar.Section = SectionOperator
+
+	selected := make(map[string]int)
+	for i, item := range m.sidebar.Operator {
+		selected[item.h.Subsystem] = i
+	}
+	if _, ok := selected["doctor_town-config"]; ok {
+		t.Error("expected passing checks left out of the operator console")
+	}
+
+	m.sidebar.Selection = selected["doctor_tmux"]
+	m, _ = sendKey(m, "F")
+	if m.confirmDialog != nil || m.statusMessage == nil || !m.statusMessage.IsError {
+		t.Fatal("expected a check without a fix command to be refused")
+	}
+
+	m.sidebar.Selection = selected["doctor_orphan-sessions"]
+	m, _ = sendKey(m, "F")
+	if m.confirmDialog == nil || m.confirmDialog.Action != ActionFixDoctorCheck || m.confirmDialog.Target != "orphan-sessions" {
+		t.Fatalf("expected fix confirmation, got %+v", m.confirmDialog)
+	}
+	m, cmd := sendKey(m, "y")
+	if cmd == nil {
+		t.Fatal("expected command after confirmation")
+	}
+	if msg, ok := cmd().(actionCompleteMsg); !ok || msg.err != nil {
+		t.Fatalf("expected fix to complete, got %+v", msg)
+	}
+	if !mock.CalledWith([]string{"gt", "doctor", "--fix"}) {
+		t.Error("expected gt doctor --fix to be called")
+	}
+}
+
 // TestRefreshAction tests the manual refresh action.
 func TestRefreshAction(t *testing.T) {
 	t.Run("RefreshSetsState", func(t *testing.T) {
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -81,6 +83,7 @@ const (
 
 	// Debug/diagnostics
 	ActionExportSnapshot // Export snapshot to JSON for debugging
+	ActionFixDoctorCheck // Run a gt doctor check's suggested fix
 )
 
 // Action represents a user-triggered action with its result.
@@ -118,6 +121,27 @@ type ActionRunner struct {
 
 	// Runner executes commands. If nil, uses real exec.
 	Runner data.CommandRunner
+
+	// FS holds the town's files, for actions that write them directly. If
+	// nil, uses the local disk.
+	FS data.FS
+
+	// ReadOnly refuses every action that changes the town, leaving only
+	// the ones that view logs and output.
+	ReadOnly bool
+}
+
+// ErrReadOnly is returned for actions refused in read-only mode.
+var ErrReadOnly = errors.New("perch is read-only")
+
+// CheckWritable returns ErrReadOnly when the runner is read-only. Writes
+// that do not go through the runner, like saving rig settings, check it
+// first.
+func (r *ActionRunner) CheckWritable() error {
+	if r != nil && r.ReadOnly {
+		return ErrReadOnly
+	}
+	return nil
 }
 
 // NewActionRunner creates a new runner for the given town root.
@@ -153,7 +177,7 @@ func (r *ActionRunner) DeleteRig(ctx context.Context, rig string) error {
 // OpenLogs opens logs for an agent.
 // Runs: gt log --agent <agent-address> -f
 func (r *ActionRunner) OpenLogs(ctx context.Context, agentAddress string) error {
-	return r.runCommand(ctx, "gt", "log", "--agent", agentAddress, "-f")
+	return r.runViewCommand(ctx, "gt", "log", "--agent", agentAddress, "-f")
 }
 
 // AddRig adds a new rig by cloning a repository.
@@ -407,20 +431,33 @@ func (r *ActionRunner) StartSession(ctx context.Context, agentAddress string) er
 // This is a tmux-optional alternative to OpenSession that works without tmux.
 // Runs: gt session capture <agent-address>
 func (r *ActionRunner) ViewSessionOutput(ctx context.Context, agentAddress string) error {
-	return r.runCommand(ctx, "gt", "session", "capture", agentAddress)
+	return r.runViewCommand(ctx, "gt", "session", "capture", agentAddress)
 }
 
 // TogglePlugin enables or disables a plugin by creating/removing a .disabled marker file.
 // pluginPath is the full path to the plugin directory.
 func (r *ActionRunner) TogglePlugin(ctx context.Context, pluginPath string) error {
+	if err := r.CheckWritable(); err != nil {
+		return err
+	}
 	disabledPath := filepath.Join(pluginPath, ".disabled")
+	dry, isDry := r.DryRun()
+	files := data.FilesOrDisk(r.FS)
 
-	if _, err := os.Stat(disabledPath); os.IsNotExist(err) {
+	if _, err := files.Stat(disabledPath); errors.Is(err, fs.ErrNotExist) {
 		// Plugin is enabled, disable it
-		return os.WriteFile(disabledPath, []byte("disabled\n"), 0644)
+		if isDry {
+			dry.record("touch", disabledPath)
+			return nil
+		}
+		return files.WriteFile(disabledPath, []byte("disabled\n"), 0644)
 	}
 	// Plugin is disabled, enable it by removing the marker
-	return os.Remove(disabledPath)
+	if isDry {
+		dry.record("rm", disabledPath)
+		return nil
+	}
+	return files.Remove(disabledPath)
 }
 
 // AddDependency adds a dependency relationship between issues.
@@ -477,6 +514,15 @@ func (r *ActionRunner) RestartDeacon(ctx context.Context) error {
 	return r.runCommand(ctx, "gt", "deacon", "restart")
 }
 
+// FixDoctorCheck runs the fix gt doctor suggests for a failing check.
+// Runs: the check's FixCommand, e.g. gt doctor --fix
+func (r *ActionRunner) FixDoctorCheck(ctx context.Context, check data.DoctorCheck) error {
+	if len(check.FixCommand) == 0 {
+		return fmt.Errorf("check %s has no fix command", check.Name)
+	}
+	return r.runCommand(ctx, check.FixCommand...)
+}
+
 // StartWitness starts a Witness for the given rig.
 // Runs: gt witness start <rig>
 func (r *ActionRunner) StartWitness(ctx context.Context, rig string) error {
@@ -522,24 +568,28 @@ func (r *ActionRunner) MQRetry(ctx context.Context, mrID, rig string) error {
 // MQViewDetails views detailed MR status (blockers, conflicts).
 // Runs: gt mq status <mr-id> --rig <rig>
 func (r *ActionRunner) MQViewDetails(ctx context.Context, mrID, rig string) error {
-	return r.runCommand(ctx, "gt", "mq", "status", mrID, "--rig", rig)
+	return r.runViewCommand(ctx, "gt", "mq", "status", mrID, "--rig", rig)
 }
 
 // MQOpenLogs opens logs for an MR.
 // Runs: gt logs --mr <mr-id>
 func (r *ActionRunner) MQOpenLogs(ctx context.Context, mrID string) error {
-	return r.runCommand(ctx, "gt", "logs", "--mr", mrID)
+	return r.runViewCommand(ctx, "gt", "logs", "--mr", mrID)
 }
 
 // ViewMRLogs opens refinery logs for a rig.
 // Runs: gt log --agent <rig>/refinery -f
 func (r *ActionRunner) ViewMRLogs(ctx context.Context, rig string) error {
-	return r.runCommand(ctx, "gt", "log", "--agent", rig+"/refinery", "-f")
+	return r.runViewCommand(ctx, "gt", "log", "--agent", rig+"/refinery", "-f")
 }
 
 // ExportSnapshot exports the current snapshot to a JSON file for debugging.
 // The file is saved to ~/.perch/last_snapshot.json with timestamp and stale markers.
 func (r *ActionRunner) ExportSnapshot(ctx context.Context, snapshot interface{}) error {
+	if err := r.CheckWritable(); err != nil {
+		return err
+	}
+
 	// Create the .perch directory if it doesn't exist
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -572,8 +622,18 @@ func (r *ActionRunner) ExportSnapshot(ctx context.Context, snapshot interface{})
 	return nil
 }
 
-// runCommand executes a shell command and returns any error.
+// runCommand executes a shell command and returns any error. It is refused
+// in read-only mode.
 func (r *ActionRunner) runCommand(ctx context.Context, args ...string) error {
+	if err := r.CheckWritable(); err != nil {
+		return err
+	}
+	return r.runViewCommand(ctx, args...)
+}
+
+// runViewCommand executes a command that only shows town state, so it also
+// runs in read-only mode.
+func (r *ActionRunner) runViewCommand(ctx context.Context, args ...string) error {
 	_, stderr, err := r.Runner.Exec(ctx, r.TownRoot, args...)
 	if err != nil {
 		errMsg := string(stderr)
@@ -588,6 +648,9 @@ func (r *ActionRunner) runCommand(ctx context.Context, args ...string) error {
 
 // runCommandWithOutput executes a shell command and returns stdout and any error.
 func (r *ActionRunner) runCommandWithOutput(ctx context.Context, args ...string) (string, error) {
+	if err := r.CheckWritable(); err != nil {
+		return "", err
+	}
 	stdout, stderr, err := r.Runner.Exec(ctx, r.TownRoot, args...)
 	if err != nil {
 		errMsg := string(stderr)
@@ -708,6 +771,18 @@ func IsTownLevelBead(beadID string) bool {
 	return strings.HasPrefix(beadID, "hq-")
 }
 
+// IsMutating returns true if the action changes the town. Read-only mode
+// refuses these.
+func IsMutating(action ActionType) bool {
+	switch action {
+	case ActionRefresh, ActionOpenLogs, ActionViewSessionOutput,
+		ActionMQViewDetails, ActionMQOpenLogs, ActionViewMRLogs:
+		return false
+	default:
+		return true
+	}
+}
+
 // IsDestructive returns true if the action type requires confirmation.
 func IsDestructive(action ActionType) bool {
 	switch action {
@@ -716,7 +791,7 @@ func IsDestructive(action ActionType) bool {
 		ActionStopAgent, ActionRestartSession,
 		ActionStopDeacon, ActionRestartDeacon,
 		ActionStopWitness, ActionRestartWitness,
-		ActionStopRefinery:
+		ActionStopRefinery, ActionFixDoctorCheck:
 		return true
 	default:
 		return false
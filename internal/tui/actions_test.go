@@ -1,8 +1,11 @@
 package tui
 
 import (
+	"context"
 	"testing"
 	"time"
+
+	"github.com/andyrewlee/perch/internal/testutil"
 )
 
 func TestStatusMessage(t *testing.T) {
@@ -43,6 +46,26 @@ func TestStatusMessage(t *testing.T) {
 	})
 }
 
+func TestTogglePluginWritesThroughTownFS(t *testing.T) {
+	files := testutil.NewMemFS().Add("/srv/gt/plugins/nightly/plugin.md", "# Nightly")
+	r := NewActionRunnerWithRunner("/srv/gt", testutil.NewMockRunner())
+	r.FS = files
+	ctx := context.Background()
+
+	if err := r.TogglePlugin(ctx, "/srv/gt/plugins/nightly"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := files.Stat("/srv/gt/plugins/nightly/.disabled"); err != nil {
+		t.Fatalf("expected a .disabled marker, got %v", err)
+	}
+	if err := r.TogglePlugin(ctx, "/srv/gt/plugins/nightly"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := files.Stat("/srv/gt/plugins/nightly/.disabled"); err == nil {
+		t.Error("expected the .disabled marker removed")
+	}
+}
+
 func TestIsDestructive(t *testing.T) {
 	tests := []struct {
 		action ActionType
@@ -20,6 +20,11 @@ const (
 	ActivityPatrol     ActivityType = "patrol"
 	ActivityDone       ActivityType = "done"
 	ActivityCrash      ActivityType = "crash"
+	ActivityAgent      ActivityType = "agent"
+	ActivityBead       ActivityType = "bead"
+	ActivityConvoy     ActivityType = "convoy"
+	ActivityMail       ActivityType = "mail"
+	ActivityError      ActivityType = "error"
 )
 
 // ActivityEvent represents a single activity event in the feed.
@@ -37,19 +42,69 @@ type activityState struct {
 	events    []ActivityEvent
 	maxEvents int
 	lastBuilt time.Time
+
+	snapshot  *data.Snapshot       // Snapshot the feed was built from, diffed on the next build
+	changes   []ActivityEvent      // Events from snapshot diffs, newest first
+	firstSeen map[string]time.Time // When each MR (and MR conflict) was first seen
 }
 
-// buildActivityFeed aggregates events from multiple sources into the activity feed.
-func buildActivityFeed(snap *data.Snapshot, prevState *activityState) *activityState {
+// defaultActivityMaxEvents is the activity feed length when none is configured.
+const defaultActivityMaxEvents = 50
+
+// buildActivityFeed aggregates events from multiple sources into the activity feed,
+// keeping at most maxEvents (0 = defaultActivityMaxEvents).
+func buildActivityFeed(snap *data.Snapshot, prevState *activityState, maxEvents int) *activityState {
+	if maxEvents <= 0 {
+		maxEvents = defaultActivityMaxEvents
+	}
 	if snap == nil {
 		if prevState != nil {
 			return prevState
 		}
-		return &activityState{maxEvents: 50}
+		return &activityState{maxEvents: maxEvents}
 	}
 
+	// Diff against the previous snapshot. Going backwards (time travel)
+	// starts a fresh feed instead of reporting changes in reverse.
+	var prevSnap *data.Snapshot
+	if prevState != nil && prevState.snapshot != nil && !snap.LoadedAt.Before(prevState.snapshot.LoadedAt) {
+		prevSnap = prevState.snapshot
+	} else {
+		prevState = nil
+	}
+	changes := data.DiffSnapshots(prevSnap, snap)
+
 	state := &activityState{
-		maxEvents: 50,
+		maxEvents: maxEvents,
+		snapshot:  snap,
+		firstSeen: make(map[string]time.Time),
+	}
+
+	seenAt := snap.LoadedAt
+	if seenAt.IsZero() {
+		seenAt = time.Now()
+	}
+
+	// First-seen times come from the change stream, then earlier builds
+	changeSeen := make(map[string]time.Time)
+	for _, c := range changes {
+		switch c.Type {
+		case data.ChangeMREntered:
+			changeSeen[c.Source+"/"+c.ID] = c.At
+		case data.ChangeMRConflict:
+			changeSeen[c.Source+"/"+c.ID+"#conflict"] = c.At
+		}
+	}
+	firstSeen := func(key string) time.Time {
+		t, ok := changeSeen[key]
+		if !ok && prevState != nil {
+			t, ok = prevState.firstSeen[key]
+		}
+		if !ok || t.IsZero() {
+			t = seenAt
+		}
+		state.firstSeen[key] = t
+		return t
 	}
 
 	var events []ActivityEvent
@@ -57,10 +112,11 @@ func buildActivityFeed(snap *data.Snapshot, prevState *activityState) *activityS
 	// Add MR events (ready, merged, conflicts)
 	for rig, mrs := range snap.MergeQueues {
 		for _, mr := range mrs {
+			key := rig + "/" + mr.ID
 			switch mr.Status {
 			case "ready", "queued":
 				events = append(events, ActivityEvent{
-					Timestamp: time.Now(), // MRs don't have timestamps, use now
+					Timestamp: firstSeen(key),
 					Type:      ActivityMRReady,
 					Source:    rig,
 					Summary:   fmt.Sprintf("%s: %s", mr.Worker, mr.Title),
@@ -69,7 +125,7 @@ func buildActivityFeed(snap *data.Snapshot, prevState *activityState) *activityS
 				})
 			case "merged":
 				events = append(events, ActivityEvent{
-					Timestamp: time.Now(),
+					Timestamp: firstSeen(key),
 					Type:      ActivityMRMerged,
 					Source:    rig,
 					Summary:   fmt.Sprintf("%s: %s", mr.Worker, mr.Title),
@@ -79,7 +135,7 @@ func buildActivityFeed(snap *data.Snapshot, prevState *activityState) *activityS
 			}
 			if mr.HasConflicts || mr.NeedsRebase {
 				events = append(events, ActivityEvent{
-					Timestamp: time.Now(),
+					Timestamp: firstSeen(key + "#conflict"),
 					Type:      ActivityMRConflict,
 					Source:    rig,
 					Summary:   fmt.Sprintf("%s: %s", mr.Worker, mr.Title),
@@ -90,6 +146,20 @@ func buildActivityFeed(snap *data.Snapshot, prevState *activityState) *activityS
 		}
 	}
 
+	// Carry forward change events, newest first
+	for i := len(changes) - 1; i >= 0; i-- {
+		if e, ok := changeActivity(changes[i]); ok {
+			state.changes = append(state.changes, e)
+		}
+	}
+	if prevState != nil {
+		state.changes = append(state.changes, prevState.changes...)
+	}
+	if len(state.changes) > state.maxEvents {
+		state.changes = state.changes[:state.maxEvents]
+	}
+	events = append(events, state.changes...)
+
 	// Add hook events from agents
 	if snap.Town != nil {
 		for _, agent := range snap.Town.Agents {
@@ -174,6 +244,45 @@ func buildActivityFeed(snap *data.Snapshot, prevState *activityState) *activityS
 	return state
 }
 
+// changeActivity converts a snapshot change into a feed event.
+// MR queue changes are already covered by the queue-derived events.
+func changeActivity(c data.ChangeEvent) (ActivityEvent, bool) {
+	e := ActivityEvent{Timestamp: c.At, Source: c.Source, ID: c.ID}
+	switch c.Type {
+	case data.ChangeAgentStarted:
+		e.Type = ActivityAgent
+		e.Summary = "Started: " + c.Source
+	case data.ChangeAgentStopped:
+		e.Type = ActivityAgent
+		e.Summary = "Stopped: " + c.Source
+	case data.ChangeBeadCreated:
+		e.Type = ActivityBead
+		e.Summary = fmt.Sprintf("New %s: %s", c.ID, c.Summary)
+	case data.ChangeBeadStatus:
+		e.Type = ActivityBead
+		e.Summary = fmt.Sprintf("%s: %s → %s", c.ID, c.From, c.To)
+		e.Details = c.Summary
+	case data.ChangeConvoyProgress:
+		e.Type = ActivityConvoy
+		e.Summary = fmt.Sprintf("%s: %s", c.Summary, c.To)
+		e.Details = "Was " + c.From
+	case data.ChangeMailArrived:
+		e.Type = ActivityMail
+		e.Summary = fmt.Sprintf("Mail from %s", c.Source)
+		e.Details = c.Summary
+	case data.ChangeLoadErrorAppeared:
+		e.Type = ActivityError
+		e.Summary = "Load failed: " + c.Source
+		e.Details = c.Summary
+	case data.ChangeLoadErrorCleared:
+		e.Type = ActivityError
+		e.Summary = "Recovered: " + c.Source
+	default:
+		return ActivityEvent{}, false
+	}
+	return e, true
+}
+
 // Activity styles
 var (
 	activityTitleStyle = lipgloss.NewStyle().
@@ -227,6 +336,16 @@ func typeIcon(t ActivityType) string {
 		return "✓"
 	case ActivityCrash:
 		return "💥"
+	case ActivityAgent:
+		return "⚙"
+	case ActivityBead:
+		return "◆"
+	case ActivityConvoy:
+		return "🚚"
+	case ActivityMail:
+		return "✉"
+	case ActivityError:
+		return "✗"
 	default:
 		return "•"
 	}
@@ -239,12 +358,14 @@ func typeStyle(t ActivityType) lipgloss.Style {
 		return activityMRReadyStyle
 	case ActivityMRMerged, ActivityDone:
 		return activityMRMergedStyle
-	case ActivityMRConflict, ActivityCrash:
+	case ActivityMRConflict, ActivityCrash, ActivityError:
 		return activityMRConflictStyle
-	case ActivityHook:
+	case ActivityHook, ActivityBead:
 		return activityHookStyle
-	case ActivityPatrol:
+	case ActivityPatrol, ActivityMail:
 		return activityPatrolStyle
+	case ActivityAgent, ActivityConvoy:
+		return activityMRReadyStyle
 	default:
 		return lipgloss.NewStyle()
 	}
@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+func TestBuildActivityFeedFirstSeen(t *testing.T) {
+	t0 := time.Date(2026, 1, 8, 16, 0, 0, 0, time.UTC)
+	mr := data.MergeRequest{ID: "mr-001", Title: "Add auth", Status: "ready", Worker: "able"}
+
+	first := &data.Snapshot{LoadedAt: t0, MergeQueues: map[string][]data.MergeRequest{"perch": nil}}
+	second := &data.Snapshot{LoadedAt: t0.Add(time.Minute), MergeQueues: map[string][]data.MergeRequest{"perch": {mr}}}
+	third := &data.Snapshot{LoadedAt: t0.Add(2 * time.Minute), MergeQueues: map[string][]data.MergeRequest{"perch": {mr}}}
+
+	state := buildActivityFeed(first, nil, 0)
+	state = buildActivityFeed(second, state, 0)
+	state = buildActivityFeed(third, state, 0)
+
+	var found bool
+	for _, e := range state.events {
+		if e.Type == ActivityMRReady && e.ID == "mr-001" {
+			found = true
+			if !e.Timestamp.Equal(second.LoadedAt) {
+				t.Errorf("expected MR first seen at %v, got %v", second.LoadedAt, e.Timestamp)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected MR ready event")
+	}
+}
+
+func TestBuildActivityFeedChanges(t *testing.T) {
+	t0 := time.Date(2026, 1, 8, 16, 0, 0, 0, time.UTC)
+	prev := &data.Snapshot{
+		LoadedAt: t0,
+		Issues:   []data.Issue{{ID: "gt-001", Title: "Auth", Status: "open"}},
+		Mail:     []data.MailMessage{},
+	}
+	next := &data.Snapshot{
+		LoadedAt: t0.Add(5 * time.Second),
+		Issues:   []data.Issue{{ID: "gt-001", Title: "Auth", Status: "closed"}},
+		Mail:     []data.MailMessage{{ID: "m-1", From: "mayor/", Subject: "hi"}},
+	}
+	later := &data.Snapshot{LoadedAt: t0.Add(10 * time.Second), Issues: next.Issues, Mail: next.Mail}
+
+	state := buildActivityFeed(prev, nil, 0)
+	state = buildActivityFeed(next, state, 0)
+	// Change events persist after the snapshot stops changing
+	state = buildActivityFeed(later, state, 0)
+
+	types := make(map[ActivityType]bool)
+	for _, e := range state.events {
+		types[e.Type] = true
+	}
+	if !types[ActivityBead] {
+		t.Error("expected bead status change in feed")
+	}
+	if !types[ActivityMail] {
+		t.Error("expected mail arrival in feed")
+	}
+
+	// Going back in time starts a fresh feed
+	state = buildActivityFeed(prev, state, 0)
+	if len(state.changes) != 0 {
+		t.Errorf("expected fresh feed after going backwards, got %d changes", len(state.changes))
+	}
+}
@@ -364,14 +364,15 @@ func renderDashboardHints() string {
 
 // AgentDetailDialog shows detailed information about a single agent.
 type AgentDetailDialog struct {
-	Agent         data.Agent
-	RigName       string
-	HealthStatus  AgentHealthStatus
-	WorkAge       time.Duration
-	LastHeartbeat time.Time
-	MailUnread    int
-	SelectedAction int // 0=nudge, 1=attach, 2=mail, 3=handoff/stop/start
-	ShowActions   bool // Toggle action menu visibility
+	Agent          data.Agent
+	RigName        string
+	HealthStatus   AgentHealthStatus
+	WorkAge        time.Duration
+	LastHeartbeat  time.Time
+	MailUnread     int
+	SelectedAction int    // 0=nudge, 1=attach, 2=mail, 3=handoff/stop/start
+	ShowActions    bool   // Toggle action menu visibility
+	Keys           KeyMap // Labels the quick action keys; zero uses the defaults
 
 	// Last activity from audit timeline
 	LastActivitySummary string
@@ -520,7 +521,11 @@ func (d *AgentDetailDialog) Render(width, height int) string {
 	// Footer hint
 	lines = append(lines, "")
 	lines = append(lines, "")
-	lines = append(lines, mutedStyle.Render("enter: execute action • esc: close • q: quit"))
+	if d.ShowActions {
+		lines = append(lines, mutedStyle.Render("enter: execute action • esc: close • q: quit"))
+	} else {
+		lines = append(lines, mutedStyle.Render("esc: close"))
+	}
 
 	// Render dialog box
 	return renderDialogBox(lines, dialogWidth, dialogHeight)
@@ -564,17 +569,21 @@ func (d *AgentDetailDialog) sessionStatus() string {
 
 // getActions returns the list of available actions.
 func (d *AgentDetailDialog) getActions() []string {
+	keys := d.Keys
+	if keys.byKey == nil {
+		keys = defaultKeys
+	}
 	actions := []string{
-		"n: Send nudge",
-		"a: Attach session",
-		"m: Send mail",
+		keys.Hint(KeyDetailNudge, "Send nudge"),
+		keys.Hint(KeyDetailAttach, "Attach session"),
+		keys.Hint(KeyDetailMail, "Send mail"),
 	}
 
 	if d.Agent.Running {
-		actions = append(actions, "h: Handoff work")
-		actions = append(actions, "s: Stop agent")
+		actions = append(actions, keys.Hint(KeyDetailHandoff, "Handoff work"))
+		actions = append(actions, keys.Hint(KeyDetailStop, "Stop agent"))
 	} else {
-		actions = append(actions, "t: Start session")
+		actions = append(actions, keys.Hint(KeyDetailStart, "Start session"))
 	}
 
 	return actions
@@ -1,11 +1,15 @@
 package tui
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
+
 	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/testutil"
 )
 
 func TestBuildAlerts_StaleWhenWatchdogDown(t *testing.T) {
@@ -161,3 +165,41 @@ func TestBuildAlerts_StaleWithNilOperationalState(t *testing.T) {
 		t.Errorf("expected 'failed' when operational state is nil, got: %s", alertText)
 	}
 }
+
+func TestRetryAlertSourceNow(t *testing.T) {
+	bdList := []string{"bd", "list", "--json", "--limit", "0"}
+	mock := testutil.NewMockRunner()
+	mock.On(bdList, nil, []byte("database locked"), errors.New("exit status 1"))
+	m := newTestModelWithMockRunner(t, mock)
+	m.store.Loader().Breakers.Threshold = 1
+	m.store.Loader().Breakers.Backoff = time.Hour
+
+	// The fixture-free town has no deacon, which hides alerts, so select
+	// the issues error directly
+	updated, _ := m.Update(m.loadData())
+	m = updated.(Model)
+	m.sidebar.Alerts = nil
+	for _, e := range m.snapshot.LoadErrors {
+		if e.Source == data.SourceIssues {
+			m.sidebar.Alerts = append(m.sidebar.Alerts, alertItem{e})
+		}
+	}
+	if len(m.sidebar.Alerts) != 1 {
+		t.Fatalf("expected an issues error, got %+v", m.snapshot.LoadErrors)
+	}
+	m.sidebar.Section = SectionAlerts
+	m.sidebar.Selection = 0
+	if action := m.sidebar.Alerts[0].e.SuggestedAction(); !strings.Contains(action, "retry now") {
+		t.Errorf("expected the alert to offer retrying now, got %q", action)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if m = updated.(Model); cmd == nil || !m.isRefreshing {
+		t.Fatal("expected r to retry the selected source")
+	}
+	before := mock.CallCount(bdList)
+	cmd()
+	if after := mock.CallCount(bdList); after <= before {
+		t.Error("expected the retry to run bd despite the open breaker")
+	}
+}
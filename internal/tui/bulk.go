@@ -0,0 +1,390 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// bulkConcurrency bounds how many commands a bulk action runs at once.
+const bulkConcurrency = 4
+
+// CanMark reports whether the current section supports multi-select.
+func (s *SidebarState) CanMark() bool {
+	switch s.Section {
+	case SectionBeads, SectionMail, SectionAgents, SectionWorktrees, SectionMergeQueue:
+		return true
+	}
+	return false
+}
+
+// IsMarked reports whether an item in a section is marked.
+func (s *SidebarState) IsMarked(sec SidebarSection, id string) bool {
+	return s.Marks[sec][id]
+}
+
+func (s *SidebarState) sectionMarks() map[string]bool {
+	if s.Marks == nil {
+		s.Marks = make(map[SidebarSection]map[string]bool)
+	}
+	if s.Marks[s.Section] == nil {
+		s.Marks[s.Section] = make(map[string]bool)
+	}
+	return s.Marks[s.Section]
+}
+
+// ToggleMark marks or unmarks the selected item and makes it the start of
+// the next range.
+func (s *SidebarState) ToggleMark() {
+	item := s.SelectedItem()
+	if item == nil || !s.CanMark() {
+		return
+	}
+	marks := s.sectionMarks()
+	if marks[item.ID()] {
+		delete(marks, item.ID())
+	} else {
+		marks[item.ID()] = true
+	}
+	s.markAnchor = item.ID()
+}
+
+// MarkRange marks every item between the last marked item and the selection.
+func (s *SidebarState) MarkRange() {
+	items := s.CurrentItems()
+	if s.Selection < 0 || s.Selection >= len(items) || !s.CanMark() {
+		return
+	}
+	anchor := s.Selection
+	for i, item := range items {
+		if item.ID() == s.markAnchor {
+			anchor = i
+			break
+		}
+	}
+	marks := s.sectionMarks()
+	for i := min(anchor, s.Selection); i <= max(anchor, s.Selection); i++ {
+		marks[items[i].ID()] = true
+	}
+	s.markAnchor = items[s.Selection].ID()
+}
+
+// MarkedIndexes returns the positions of the marked items in the current
+// section. Marked items that are filtered out or gone are skipped.
+func (s *SidebarState) MarkedIndexes() []int {
+	marks := s.Marks[s.Section]
+	if len(marks) == 0 {
+		return nil
+	}
+	var idx []int
+	for i, item := range s.CurrentItems() {
+		if marks[item.ID()] {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// ClearMarks unmarks every item in a section.
+func (s *SidebarState) ClearMarks(sec SidebarSection) {
+	delete(s.Marks, sec)
+}
+
+// listPrefix returns the two-column prefix of a list item: ">" for the
+// cursor and "*" for items marked for bulk actions.
+func (s *SidebarState) listPrefix(sec SidebarSection, item SelectableItem, selected bool) string {
+	cursor := " "
+	if selected {
+		cursor = ">"
+	}
+	if s.IsMarked(sec, item.ID()) {
+		return cursor + "*"
+	}
+	return cursor + " "
+}
+
+// bulkItem is one target of a bulk action.
+type bulkItem struct {
+	id    string // Bead, mail or MR ID, agent address, or worktree path
+	label string
+	rig   string // Merge request rig
+}
+
+// bulkOp is a bulk action waiting for confirmation or running.
+type bulkOp struct {
+	action  ActionType
+	section SidebarSection
+	noun    string // Plural item kind, e.g. "beads"
+	items   []bulkItem
+	skipped int    // Marked items the action does not apply to
+	input   string // Refile target or sling destination
+}
+
+// summary describes the operation for the confirmation dialog.
+func (op bulkOp) summary() string {
+	labels := make([]string, 0, 3)
+	for i, item := range op.items {
+		if i == 3 {
+			labels = append(labels, fmt.Sprintf("and %d more", len(op.items)-3))
+			break
+		}
+		labels = append(labels, item.label)
+	}
+	text := fmt.Sprintf("%s %d %s (%s)", actionName(op.action), len(op.items), op.noun, strings.Join(labels, ", "))
+	if op.input != "" {
+		text += " to " + op.input
+	}
+	text += "?"
+	if op.skipped > 0 {
+		text += fmt.Sprintf(" %d marked %s do not apply and are skipped.", op.skipped, op.noun)
+	}
+	return text
+}
+
+// bulkResult is the outcome for one item.
+type bulkResult struct {
+	item bulkItem
+	err  error
+}
+
+// bulkCompleteMsg reports every item of a finished bulk action.
+type bulkCompleteMsg struct {
+	op      bulkOp
+	results []bulkResult
+}
+
+// Failed counts the items that failed.
+func (msg bulkCompleteMsg) Failed() int {
+	n := 0
+	for _, r := range msg.results {
+		if r.err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// handleBulkAction runs action across the marked items of the current
+// section. It reports false when nothing is marked or the action has no
+// bulk form, so the key falls through to its single-item behavior.
+func (m Model) handleBulkAction(action KeyAction) (tea.Model, tea.Cmd, bool) {
+	s := m.sidebar
+	marked := s.MarkedIndexes()
+	if m.focus != PanelSidebar || len(marked) == 0 {
+		return m, nil, false
+	}
+
+	op := bulkOp{section: s.Section}
+	add := func(applies bool, item bulkItem) {
+		if applies {
+			op.items = append(op.items, item)
+		} else {
+			op.skipped++
+		}
+	}
+
+	switch {
+	case s.Section == SectionBeads && (action == KeyCloseBead || action == KeyReopenBead || action == KeyRefile || action == KeySling):
+		op.noun = "beads"
+		switch action {
+		case KeyCloseBead:
+			op.action = ActionCloseBead
+		case KeyReopenBead:
+			op.action = ActionReopenBead
+		case KeyRefile:
+			op.action = ActionRefileIssue
+		case KeySling:
+			op.action = ActionSlingWork
+		}
+		for _, i := range marked {
+			issue := s.Beads[i].issue
+			closed := issue.Status == "closed"
+			applies := (action != KeyCloseBead || !closed) && (action != KeyReopenBead || closed)
+			add(applies, bulkItem{id: issue.ID, label: issue.ID})
+		}
+
+	case s.Section == SectionAgents && action == KeyKill:
+		op.action, op.noun = ActionStopAgent, "agents"
+		for _, i := range marked {
+			a := s.Agents[i].a
+			add(true, bulkItem{id: a.Address, label: a.Name})
+		}
+
+	case s.Section == SectionWorktrees && action == KeyClear:
+		op.action, op.noun = ActionRemoveWorktree, "worktrees"
+		for _, i := range marked {
+			wt := s.Worktrees[i].wt
+			// Worktrees with uncommitted changes need --force, as for a single remove
+			add(wt.Clean, bulkItem{id: wt.Path, label: wt.SourceRig + "-" + wt.SourceName})
+		}
+
+	case s.Section == SectionMergeQueue && action == KeyRefresh:
+		op.action, op.noun = ActionMQRetry, "merge requests"
+		for _, i := range marked {
+			mr := s.MRs[i]
+			add(true, bulkItem{id: mr.mr.ID, label: mr.mr.ID, rig: mr.rig})
+		}
+
+	case s.Section == SectionMail && (action == KeyAckMail || action == KeyMail):
+		op.action, op.noun = ActionAckMail, "messages"
+		if action == KeyMail {
+			op.action = ActionMarkMailRead
+		}
+		for _, i := range marked {
+			mail := s.Mail[i].m
+			add(action == KeyAckMail || !mail.Read, bulkItem{id: mail.ID, label: truncate(mail.Subject, 20)})
+		}
+
+	default:
+		return m, nil, false
+	}
+
+	if len(op.items) == 0 {
+		m.setStatus(fmt.Sprintf("%s does not apply to any of the %d marked %s", actionName(op.action), len(marked), op.noun), true)
+		return m, statusExpireCmd(3 * time.Second), true
+	}
+
+	m.bulk = &op
+	switch op.action {
+	case ActionRefileIssue:
+		// Pick the target first, then confirm
+		m.refileDialog = NewRefileDialog(fmt.Sprintf("%d beads", len(op.items)), m.snapshot)
+		return m, nil, true
+	case ActionSlingWork:
+		m.inputDialog = &InputDialog{
+			Title:  "Sling Work",
+			Prompt: fmt.Sprintf("Agent or rig to sling %d beads to: ", len(op.items)),
+			Action: ActionSlingWork,
+		}
+		return m, nil, true
+	}
+	m.confirmBulk()
+	return m, nil, true
+}
+
+// confirmBulk asks once for the whole pending bulk action.
+func (m *Model) confirmBulk() {
+	m.confirmDialog = &ConfirmDialog{
+		Title:   "Confirm Bulk " + actionName(m.bulk.action),
+		Message: m.bulk.summary() + " (y/n)",
+		Action:  m.bulk.action,
+	}
+}
+
+// bulkCmd runs a bulk action, at most bulkConcurrency items at a time.
+func (m Model) bulkCmd(op bulkOp) tea.Cmd {
+	return func() tea.Msg {
+		results := make([]bulkResult, len(op.items))
+		sem := make(chan struct{}, bulkConcurrency)
+		var wg sync.WaitGroup
+		for i, item := range op.items {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				defer cancel()
+				runner, rec := m.recordingRunner()
+				err := runBulkItem(ctx, runner, op, item)
+				m.journalAction(rec, op.action, item.id, op.input, err)
+				results[i] = bulkResult{item: item, err: err}
+			}()
+		}
+		wg.Wait()
+		return bulkCompleteMsg{op: op, results: results}
+	}
+}
+
+// runBulkItem runs the action of op on one item.
+func runBulkItem(ctx context.Context, r *ActionRunner, op bulkOp, item bulkItem) error {
+	switch op.action {
+	case ActionCloseBead:
+		return r.CloseBead(ctx, item.id)
+	case ActionReopenBead:
+		return r.ReopenBead(ctx, item.id)
+	case ActionRefileIssue:
+		return r.RefileIssue(ctx, item.id, op.input)
+	case ActionSlingWork:
+		return r.SlingWork(ctx, item.id, op.input)
+	case ActionStopAgent:
+		return r.StopAgent(ctx, item.id)
+	case ActionRemoveWorktree:
+		return r.RemoveWorktree(ctx, item.id)
+	case ActionMQRetry:
+		return r.MQRetry(ctx, item.id, item.rig)
+	case ActionAckMail:
+		return r.AckMail(ctx, item.id)
+	case ActionMarkMailRead:
+		return r.MarkMailRead(ctx, item.id)
+	}
+	return fmt.Errorf("%s has no bulk form", actionName(op.action))
+}
+
+// handleBulkComplete clears the marks, shows the per-item report and
+// refreshes.
+func (m Model) handleBulkComplete(msg bulkCompleteMsg) (tea.Model, tea.Cmd) {
+	m.sidebar.ClearMarks(msg.op.section)
+	if _, ok := m.actionRunner.DryRun(); ok {
+		m.takeDryRunPreview(fmt.Sprintf("%s %d %s", actionName(msg.op.action), len(msg.results), msg.op.noun))
+		m.setStatus("Dry run: "+actionName(msg.op.action)+" previewed, nothing executed", false)
+		return m, statusExpireCmd(3 * time.Second)
+	}
+	m.bulkReport = &msg
+
+	failed := msg.Failed()
+	status := fmt.Sprintf("%s: %d of %d %s succeeded", actionName(msg.op.action), len(msg.results)-failed, len(msg.results), msg.op.noun)
+	m.setStatus(status, failed > 0)
+	return m, tea.Batch(statusExpireCmd(5*time.Second), m.loadData)
+}
+
+// renderBulkReport renders the per-item results of the last bulk action.
+func (m Model) renderBulkReport() string {
+	report := m.bulkReport
+	width := 70
+	if width > m.width-4 {
+		width = m.width - 4
+	}
+	// Horizontal padding takes 4 columns
+	inner := width - 4
+
+	failed := report.Failed()
+	var b strings.Builder
+	b.WriteString(helpTitleStyle.Render(fmt.Sprintf("%s: %d succeeded, %d failed",
+		actionName(report.op.action), len(report.results)-failed, failed)))
+	b.WriteString("\n\n")
+
+	// Leave room for the border, padding, title and footer
+	maxLines := max(m.height-10, 3)
+	for i, r := range report.results {
+		if i == maxLines {
+			b.WriteString(mutedStyle.Render(fmt.Sprintf("  ... %d more", len(report.results)-i)))
+			b.WriteString("\n")
+			break
+		}
+		if r.err != nil {
+			line := "✗ " + r.item.label + ": " + strings.TrimSpace(r.err.Error())
+			b.WriteString(statusErrorStyle.Render(truncate(line, inner)))
+		} else {
+			b.WriteString(itemStyle.Render("✓ " + truncate(r.item.label, inner-2)))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("Press any key to close"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(1, 2).
+		Width(width).
+		Render(b.String())
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+func bulkTestModel(t *testing.T) (Model, *testutil.MockRunner) {
+	t.Helper()
+	m, mock := createTestModel(t)
+	m.applySnapshot(&data.Snapshot{
+		Town: &data.TownStatus{Rigs: []data.Rig{{Name: "perch"}}},
+		Issues: []data.Issue{
+			{ID: "hq-1", Title: "First", Status: "open"},
+			{ID: "hq-2", Title: "Second", Status: "open"},
+			{ID: "hq-3", Title: "Third", Status: "in_progress"},
+			{ID: "hq-4", Title: "Fourth", Status: "open"},
+		},
+		Mail: []data.MailMessage{
+			{ID: "m-1", Subject: "Merge blocked", Read: true},
+			{ID: "m-2", Subject: "Handoff"},
+		},
+	})
+	m.focus = PanelSidebar
+	m.sidebar.Section = SectionBeads
+	m.sidebar.BeadsScope = BeadsScopeTown
+	m.sidebar.UpdateFromSnapshot(m.snapshot)
+	m.sidebar.Selection = 0
+	return m, mock
+}
+
+func press(t *testing.T, m Model, msg tea.KeyMsg) Model {
+	t.Helper()
+	updated, _ := m.Update(msg)
+	return updated.(Model)
+}
+
+func markedIDs(m Model) []string {
+	var ids []string
+	items := m.sidebar.CurrentItems()
+	for _, i := range m.sidebar.MarkedIndexes() {
+		ids = append(ids, items[i].ID())
+	}
+	return ids
+}
+
+func TestMarkAndRange(t *testing.T) {
+	m, _ := bulkTestModel(t)
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+
+	m = press(t, m, space)
+	m.sidebar.Selection = 2
+	m = pressKey(t, m, "V")
+	if m.showTownMap {
+		t.Fatal("expected V to mark a range, not open the town map")
+	}
+	if got := strings.Join(markedIDs(m), " "); got != "hq-1 hq-2 hq-3" {
+		t.Errorf("expected hq-1..hq-3 marked, got %q", got)
+	}
+
+	m = press(t, m, space)
+	if got := strings.Join(markedIDs(m), " "); got != "hq-1 hq-2" {
+		t.Errorf("expected space to unmark hq-3, got %q", got)
+	}
+	if got := m.sidebar.listPrefix(SectionBeads, m.sidebar.Beads[0], false); got != " *" {
+		t.Errorf("expected marked items to render with *, got %q", got)
+	}
+	if !strings.Contains(m.View(), "2 marked") {
+		t.Error("expected the footer to count marks")
+	}
+
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if len(markedIDs(m)) != 0 {
+		t.Error("expected esc to clear marks")
+	}
+	m = pressKey(t, m, "V")
+	if !m.showTownMap {
+		t.Error("expected V without marks to open the town map")
+	}
+}
+
+func TestBulkCloseReportsEachItem(t *testing.T) {
+	m, mock := bulkTestModel(t)
+	mock.On([]string{"bd", "close", "hq-2"}, nil, []byte("locked"), errors.New("exit status 1"))
+	m.sidebar.Marks = map[SidebarSection]map[string]bool{
+		SectionBeads: {"hq-1": true, "hq-2": true, "hq-3": true},
+	}
+
+	m = pressKey(t, m, m.keyMap().Key(KeyCloseBead))
+	if m.confirmDialog == nil || m.bulk == nil {
+		t.Fatal("expected a single bulk confirmation")
+	}
+	if msg := m.confirmDialog.Message; !strings.Contains(msg, "3 beads (hq-1, hq-2, hq-3)") {
+		t.Errorf("expected one confirmation listing the beads, got %q", msg)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("expected the bulk command")
+	}
+	done, ok := cmd().(bulkCompleteMsg)
+	if !ok {
+		t.Fatal("expected bulkCompleteMsg")
+	}
+	if mock.CallCount([]string{"bd", "close"}) != 3 {
+		t.Errorf("expected three bd close calls, got %v", mock.Calls())
+	}
+
+	updated, _ = m.Update(done)
+	m = updated.(Model)
+	if m.statusMessage == nil || !m.statusMessage.IsError || !strings.Contains(m.statusMessage.Text, "2 of 3 beads succeeded") {
+		t.Errorf("expected a partial failure status, got %+v", m.statusMessage)
+	}
+	if len(markedIDs(m)) != 0 {
+		t.Error("expected marks cleared after the bulk action")
+	}
+	view := m.View()
+	if !strings.Contains(view, "✓ hq-1") || !strings.Contains(view, "✗ hq-2") {
+		t.Errorf("expected a per-item report, got:\n%s", view)
+	}
+	m = pressKey(t, m, "j")
+	if m.bulkReport != nil {
+		t.Error("expected any key to dismiss the report")
+	}
+}
+
+func TestBulkSlingAsksForTargetOnce(t *testing.T) {
+	m, mock := bulkTestModel(t)
+	m.sidebar.Marks = map[SidebarSection]map[string]bool{
+		SectionBeads: {"hq-1": true, "hq-4": true},
+	}
+
+	m = pressKey(t, m, m.keyMap().Key(KeySling))
+	if m.inputDialog == nil {
+		t.Fatal("expected the sling target prompt")
+	}
+	for _, r := range "perch" {
+		m = pressKey(t, m, string(r))
+	}
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.confirmDialog == nil || !strings.Contains(m.confirmDialog.Message, "to perch") {
+		t.Fatalf("expected a confirmation naming the target, got %+v", m.confirmDialog)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	cmd()
+	for _, id := range []string{"hq-1", "hq-4"} {
+		if !mock.CalledWith([]string{"gt", "sling", id, "perch"}) {
+			t.Errorf("expected %s slung to perch, got %v", id, mock.Calls())
+		}
+	}
+}
+
+func TestBulkSkipsItemsActionDoesNotApply(t *testing.T) {
+	m, mock := bulkTestModel(t)
+	m.sidebar.Section = SectionMail
+	m.sidebar.Marks = map[SidebarSection]map[string]bool{
+		SectionMail: {"m-1": true, "m-2": true},
+	}
+
+	m = pressKey(t, m, m.keyMap().Key(KeyMail))
+	if m.confirmDialog == nil {
+		t.Fatal("expected a bulk confirmation")
+	}
+	if msg := m.confirmDialog.Message; !strings.Contains(msg, "1 messages") || !strings.Contains(msg, "1 marked messages do not apply") {
+		t.Errorf("expected read m-1 to be skipped, got %q", msg)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	cmd()
+	if mock.CalledWith([]string{"gt", "mail", "read", "m-1"}) || !mock.CalledWith([]string{"gt", "mail", "read", "m-2"}) {
+		t.Errorf("expected only m-2 marked read, got %v", mock.Calls())
+	}
+}
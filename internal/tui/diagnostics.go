@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// renderSourceDiagnostics renders when each data source last loaded, how
+// long it took and how often it reloads, flagging sources past their
+// staleness budget, then the latency of each command, slowest first.
+func renderSourceDiagnostics(snap *data.Snapshot, schedule data.Schedule, stats *data.CommandStats) string {
+	var lines []string
+	lines = append(lines, headerStyle.Render("Data Sources"))
+	lines = append(lines, "")
+	if snap == nil {
+		lines = append(lines, mutedStyle.Render("No data loaded yet"))
+		return strings.Join(lines, "\n")
+	}
+
+	t := now()
+	lines = append(lines, mutedStyle.Render(fmt.Sprintf("%-16s %8s %7s %7s", "Source", "Loaded", "Took", "Every")))
+	for _, source := range data.AllSources {
+		policy := schedule.Policy(source)
+		every := formatDuration(policy.Interval)
+		if policy.OnChange {
+			every = "change"
+		}
+		took := "-"
+		if timing, ok := snap.Timings[source]; ok {
+			took = formatLoadDuration(timing.Duration)
+		}
+		loaded := "never"
+		loadedAt, ok := sourceLoadedAt(snap, source)
+		if ok {
+			loaded = formatDuration(t.Sub(loadedAt)) + " ago"
+		}
+		line := fmt.Sprintf("%-16s %8s %7s %7s", truncateStr(source, 16), loaded, took, every)
+		switch {
+		case !ok:
+			lines = append(lines, statusErrorStyle.Render(line+"  failed"))
+		case schedule.Stale(source, loadedAt, t):
+			lines = append(lines, warningStyle.Render(line+"  stale"))
+		default:
+			lines = append(lines, line)
+		}
+	}
+
+	if stats != nil {
+		if commands := stats.All(); len(commands) > 0 {
+			lines = append(lines, "")
+			lines = append(lines, headerStyle.Render("Commands"))
+			lines = append(lines, "")
+			lines = append(lines, mutedStyle.Render(fmt.Sprintf("%-18s %5s %7s %7s %6s", "Command", "Calls", "Avg", "Max", "Errors")))
+			for _, c := range commands {
+				lines = append(lines, fmt.Sprintf("%-18s %5d %7s %7s %6d", truncateStr(c.Command, 18), c.Calls,
+					formatLoadDuration(c.Mean()), formatLoadDuration(c.Max), c.Errors))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sourceLoadedAt returns when source last loaded successfully. Merge
+// queues load per rig, so they count from the oldest rig.
+func sourceLoadedAt(snap *data.Snapshot, source string) (time.Time, bool) {
+	if source != data.SourceMergeQueue {
+		at, ok := snap.LastSuccess[source]
+		return at, ok
+	}
+	var oldest time.Time
+	for _, rig := range snap.RigNames() {
+		at, ok := snap.LastSuccess[data.SourceMergeQueue+"_"+rig]
+		if !ok {
+			return time.Time{}, false
+		}
+		if oldest.IsZero() || at.Before(oldest) {
+			oldest = at
+		}
+	}
+	return oldest, !oldest.IsZero()
+}
+
+// formatLoadDuration formats how long a load took, in ms below a second.
+func formatLoadDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+func TestTickRefreshesOnlyDueSources(t *testing.T) {
+	mock := testutil.NewMockRunner()
+	mock.On([]string{"gt", "status"}, testutil.NewFixtures().TownStatusJSON(), nil, nil)
+	m := newTestModelWithMockRunner(t, mock)
+	m.refreshInterval = 10 * time.Second
+	m.applySchedule()
+	m.loadData()
+
+	start := time.Now()
+	defer setNow(start.Add(time.Second))()
+	updated, _ := m.Update(tickMsg(now()))
+	if m = updated.(Model); m.isRefreshing {
+		t.Error("expected no refresh before any source is due")
+	}
+
+	setNow(start.Add(6 * time.Second))
+	due := m.store.Due(now())
+	if len(due) == 0 || len(due) == len(data.AllSources) {
+		t.Errorf("expected only the fast sources due, got %v", due)
+	}
+	updated, _ = m.Update(tickMsg(now()))
+	if m = updated.(Model); !m.isRefreshing {
+		t.Error("expected the due sources to refresh")
+	}
+}
+
+func TestRenderSourceDiagnostics(t *testing.T) {
+	at := time.Date(2026, 1, 2, 7, 0, 0, 0, time.UTC)
+	defer setNow(at)()
+	snap := &data.Snapshot{
+		LastSuccess: map[string]time.Time{
+			data.SourceTownStatus: at.Add(-2 * time.Second),
+			data.SourceIssues:     at.Add(-5 * time.Minute),
+		},
+		Timings: map[string]data.SourceTiming{
+			data.SourceTownStatus: {Started: at.Add(-2 * time.Second), Duration: 340 * time.Millisecond},
+			data.SourceDoctor:     {Started: at.Add(-time.Minute), Duration: 12 * time.Second},
+		},
+	}
+
+	stats := data.NewCommandStats()
+	stats.Record("gt mq list", 1500*time.Millisecond, nil)
+	stats.Record("gt status", 80*time.Millisecond, nil)
+	out := renderSourceDiagnostics(snap, data.DefaultSchedule(10*time.Second).Watching(time.Minute), stats)
+	lines := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines[fields[0]] = line
+		}
+	}
+	if line := lines[data.SourceTownStatus]; !strings.Contains(line, "2s ago") || !strings.Contains(line, "340ms") || strings.Contains(line, "stale") {
+		t.Errorf("expected town status fresh, got %q", line)
+	}
+	if line := lines[data.SourceIssues]; !strings.Contains(line, "stale") {
+		t.Errorf("expected issues stale, got %q", line)
+	}
+	if line := lines[data.SourceDoctor]; !strings.Contains(line, "12.0s") || !strings.Contains(line, "failed") {
+		t.Errorf("expected doctor timed and failed, got %q", line)
+	}
+	if line := lines[data.SourcePlugins]; !strings.Contains(line, "change") {
+		t.Errorf("expected watched plugins to reload on change, got %q", line)
+	}
+	if mq, status := strings.Index(out, "gt mq list"), strings.Index(out, "gt status"); mq < 0 || status < mq {
+		t.Errorf("expected command latency, slowest first, got:\n%s", out)
+	}
+}
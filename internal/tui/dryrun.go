@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// dryRunIssueID stands in for the ID of an issue a dry run did not create.
+const dryRunIssueID = "NEW-ISSUE"
+
+// DryRunRunner records the commands an action would run instead of running
+// them. It only wraps ActionRunner.Runner; the data loader keeps its own
+// runner, so reads work normally in dry-run mode.
+type DryRunRunner struct {
+	// Inner is the runner that is restored when dry run is turned off.
+	Inner data.CommandRunner
+
+	mu       sync.Mutex
+	commands [][]string
+}
+
+// Exec implements data.CommandRunner by recording args. Commands that
+// create an issue with --json get a placeholder ID so the steps after them
+// are previewed too.
+func (d *DryRunRunner) Exec(ctx context.Context, workDir string, args ...string) ([]byte, []byte, error) {
+	d.record(args...)
+	if len(args) > 2 && args[1] == "create" && args[len(args)-1] == "--json" {
+		return []byte(`{"id":"` + dryRunIssueID + `"}`), nil, nil
+	}
+	return nil, nil, nil
+}
+
+func (d *DryRunRunner) record(args ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.commands = append(d.commands, append([]string(nil), args...))
+}
+
+// Take returns the commands recorded since the last call and forgets them.
+func (d *DryRunRunner) Take() [][]string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	commands := d.commands
+	d.commands = nil
+	return commands
+}
+
+// SetDryRun switches the runner between running and recording commands.
+func (r *ActionRunner) SetDryRun(on bool) {
+	dry, isDry := r.Runner.(*DryRunRunner)
+	switch {
+	case on && !isDry:
+		r.Runner = &DryRunRunner{Inner: r.Runner}
+	case !on && isDry:
+		r.Runner = dry.Inner
+	}
+}
+
+// DryRun returns the recording runner when dry run is on.
+func (r *ActionRunner) DryRun() (*DryRunRunner, bool) {
+	if r == nil {
+		return nil, false
+	}
+	runner := r.Runner
+	// Look through the journal's recorder
+	if rec, ok := runner.(*journalRecorder); ok {
+		runner = rec.inner
+	}
+	dry, ok := runner.(*DryRunRunner)
+	return dry, ok
+}
+
+// shellQuote renders argv the way it would be typed in a shell.
+func shellQuote(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\$`*?[]{}()<>|&;#~!") {
+			arg = strconv.Quote(arg)
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+// takeDryRunPreview moves the commands recorded by a dry-run action into
+// the preview pane.
+func (m *Model) takeDryRunPreview(action string) {
+	dry, ok := m.actionRunner.DryRun()
+	if !ok {
+		return
+	}
+	m.dryRunPreview = &dryRunPreview{action: action, commands: dry.Take()}
+}
+
+// dryRunPreview lists the commands a dry-run action would have run.
+type dryRunPreview struct {
+	action   string
+	commands [][]string
+}
+
+// renderDryRunPreview renders the preview pane.
+func (m Model) renderDryRunPreview() string {
+	preview := m.dryRunPreview
+	width := min(100, m.width-4)
+	// Horizontal padding takes 4 columns
+	inner := width - 4
+
+	var b strings.Builder
+	b.WriteString(helpTitleStyle.Render("Dry Run: " + preview.action))
+	b.WriteString("\n\n")
+	if len(preview.commands) == 0 {
+		b.WriteString(mutedStyle.Render("No gt/bd commands would run"))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(mutedStyle.Render("Would run, in " + m.actionRunner.TownRoot + ":"))
+		b.WriteString("\n")
+	}
+	// Leave room for the border, padding, title and footer
+	maxLines := max(m.height-10, 3)
+	for i, args := range preview.commands {
+		if i == maxLines {
+			b.WriteString(mutedStyle.Render("  ... " + strconv.Itoa(len(preview.commands)-i) + " more"))
+			b.WriteString("\n")
+			break
+		}
+		b.WriteString(itemStyle.Render("$ " + truncate(shellQuote(args), inner-2)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("Nothing was executed • Press any key to close"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#FF9900")).
+		Padding(1, 2).
+		Width(width).
+		Render(b.String())
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
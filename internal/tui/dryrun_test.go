@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDryRunPreviewsCommands(t *testing.T) {
+	m, mock := createTestModel(t)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	m = updated.(Model)
+	if _, ok := m.actionRunner.DryRun(); !ok {
+		t.Fatal("expected ctrl+d to turn dry run on")
+	}
+	if !strings.Contains(m.View(), "DRY RUN") {
+		t.Error("expected a DRY RUN badge")
+	}
+
+	msg := m.actionCmd(ActionDeleteRig, "perch")()
+	updated, _ = m.Update(msg)
+	m = updated.(Model)
+
+	if mock.Called() {
+		t.Errorf("expected nothing to run, got %v", mock.Calls())
+	}
+	if m.dryRunPreview == nil || len(m.dryRunPreview.commands) != 1 {
+		t.Fatalf("expected one previewed command, got %+v", m.dryRunPreview)
+	}
+	if view := m.View(); !strings.Contains(view, "$ gt rig remove perch") || !strings.Contains(view, "Nothing was executed") {
+		t.Errorf("expected the preview pane, got:\n%s", view)
+	}
+	if entries, _ := m.journal.Load(0); len(entries) != 0 {
+		t.Errorf("expected dry runs to stay out of the journal, got %+v", entries)
+	}
+
+	m, _ = sendKey(m, "j")
+	if m.dryRunPreview != nil {
+		t.Error("expected any key to close the preview")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	m = updated.(Model)
+	m.actionCmd(ActionDeleteRig, "perch")()
+	if !mock.CalledWith([]string{"gt", "rig", "remove", "perch"}) {
+		t.Error("expected actions to run once dry run is off")
+	}
+}
+
+func TestDryRunFollowsMultiStepActions(t *testing.T) {
+	m, _ := createTestModel(t)
+	m.actionRunner.SetDryRun(true)
+
+	m.createWorkCmd("Fix it", "", "task", 2, "perch", "", false)()
+	dry, _ := m.actionRunner.DryRun()
+	commands := dry.Take()
+	if len(commands) != 2 || shellQuote(commands[1]) != "gt sling "+dryRunIssueID+" perch" {
+		t.Errorf("expected bd create then gt sling, got %v", commands)
+	}
+
+	// Plugin toggles write marker files, which a dry run must not do either
+	plugin := t.TempDir()
+	m.actionCmd(ActionTogglePlugin, plugin)()
+	if _, err := os.Stat(filepath.Join(plugin, ".disabled")); !os.IsNotExist(err) {
+		t.Error("expected no marker file in dry run")
+	}
+	if got := dry.Take(); len(got) != 1 || got[0][0] != "touch" {
+		t.Errorf("expected a previewed touch, got %v", got)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	got := shellQuote([]string{"gt", "mail", "send", "perch/ace", "-s", "Nudge: go", "-m", ""})
+	if got != `gt mail send perch/ace -s "Nudge: go" -m ""` {
+		t.Errorf("unexpected quoting %s", got)
+	}
+}
@@ -1,9 +1,9 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 
-	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -50,42 +50,28 @@ func (h *HelpOverlay) buildContent() string {
 	sb.WriteString(helpTitleStyle.Render("Keyboard Shortcuts"))
 	sb.WriteString("\n\n")
 
-	// Navigation section
-	sb.WriteString(helpSectionStyle.Render("Navigation"))
-	sb.WriteString("\n")
-	sb.WriteString(h.formatBinding(h.keyMap.Up))
-	sb.WriteString(h.formatBinding(h.keyMap.Down))
-	sb.WriteString(h.formatBinding(h.keyMap.Left))
-	sb.WriteString(h.formatBinding(h.keyMap.Right))
-	sb.WriteString(h.formatBinding(h.keyMap.NextPanel))
-	sb.WriteString(h.formatBinding(h.keyMap.PrevPanel))
-	sb.WriteString("\n")
-
-	// Actions section
-	sb.WriteString(helpSectionStyle.Render("Actions"))
-	sb.WriteString("\n")
-	sb.WriteString(h.formatBinding(h.keyMap.Select))
-	sb.WriteString(h.formatBinding(h.keyMap.Refresh))
-	sb.WriteString("\n")
-
-	// General section
-	sb.WriteString(helpSectionStyle.Render("General"))
-	sb.WriteString("\n")
-	sb.WriteString(h.formatBinding(h.keyMap.Help))
-	sb.WriteString(h.formatBinding(h.keyMap.Quit))
+	for i, group := range helpGroups {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(helpSectionStyle.Render(group))
+		sb.WriteString("\n")
+		for _, b := range h.keyMap.Group(group) {
+			sb.WriteString(h.formatBinding(b))
+		}
+	}
 
 	// Footer
-	sb.WriteString("\n\n")
-	sb.WriteString(helpFooterStyle.Render("Press ? or Esc to close"))
+	sb.WriteString("\n")
+	sb.WriteString(helpFooterStyle.Render("Press any key to close"))
 
 	return sb.String()
 }
 
 // formatBinding formats a single keybinding line
-func (h *HelpOverlay) formatBinding(b key.Binding) string {
-	help := b.Help()
-	keyStr := helpKeyStyle.Render(padRight(help.Key, 12))
-	descStr := helpDescStyle.Render(help.Desc)
+func (h *HelpOverlay) formatBinding(b Binding) string {
+	keyStr := helpKeyStyle.Render(padRight(b.Label(), 12))
+	descStr := helpDescStyle.Render(b.Desc)
 	return keyStr + descStr + "\n"
 }
 
@@ -156,3 +142,108 @@ var (
 			Foreground(muted).
 			Italic(true)
 )
+
+// helpKeymapLines renders the live keybindings for the dashboard help overlay.
+// Read-only mode leaves out the keys that only run actions.
+func (m Model) helpKeymapLines() []string {
+	keys := m.keyMap()
+	var lines []string
+	for _, group := range helpGroups {
+		var bindings []Binding
+		for _, b := range keys.Group(group) {
+			if !m.readOnly() || !actionKeys[b.Action] {
+				bindings = append(bindings, b)
+			}
+		}
+		if len(bindings) == 0 {
+			continue
+		}
+		lines = append(lines, "", helpHeaderStyle.Render(group), "")
+		for _, b := range bindings {
+			label := b.Label()
+			pad := 11 - lipgloss.Width(label)
+			if pad < 1 {
+				pad = 1
+			}
+			lines = append(lines, helpKeyStyle.Render(label)+strings.Repeat(" ", pad)+b.Desc)
+		}
+	}
+	return lines
+}
+
+// footerHints returns the context-aware key hints shown in the footer.
+func (m Model) footerHints() []string {
+	keys := m.keyMap()
+	var hints []string
+	// hint adds a key hint unless read-only mode disables the key here
+	hint := func(action KeyAction, label string) {
+		if !m.readOnlyBlocks(action) {
+			hints = append(hints, keys.Hint(action, label))
+		}
+	}
+	if m.focus == PanelSidebar {
+		if n := len(m.sidebar.MarkedIndexes()); n > 0 {
+			hints = append(hints, fmt.Sprintf("%d marked", n))
+			hint(KeyMark, "mark")
+			hint(KeyTownMap, "mark range")
+			hints = append(hints, "esc: unmark")
+		}
+		hints = append(hints,
+			keys.Key(KeyDown)+"/"+keys.Key(KeyUp)+": select",
+			keys.Key(KeyLeft)+"/"+keys.Key(KeyRight)+": section",
+			keys.Key(KeySectionIdentity)+"-"+keys.Key(KeySectionAlerts)+": jump",
+		)
+		switch m.sidebar.Section {
+		case SectionRigs:
+			hint(KeyEdit, "edit settings")
+		case SectionMergeQueue:
+			hint(KeyNudge, "nudge")
+		case SectionConvoys:
+			hint(KeyHandoff, "history")
+		case SectionAgents:
+			hint(KeyBoot, "start")
+			hint(KeyStopIdle, "stop idle")
+			hint(KeyStopAllIdle, "stop all idle")
+		case SectionLifecycle:
+			hint(KeyEdit, "type filter")
+			hint(KeyAssigneeFilter, "agent filter")
+			hint(KeyClear, "clear")
+		case SectionMail:
+			hint(KeyMail, "read/unread")
+			hint(KeyAckMail, "ack")
+		case SectionWorktrees:
+			hint(KeyClear, "remove")
+		case SectionAlerts, SectionErrors:
+			hint(KeyRefresh, "retry now")
+		case SectionOperator:
+			hint(KeyBoot, "start")
+			hint(KeyShutdown, "stop")
+			hint(KeyRefresh, "restart")
+			hint(KeyFix, "fix")
+		}
+	}
+	hint(KeyNewWork, "new work")
+	hint(KeyAddRig, "add rig")
+	hint(KeyAttachTown, "attach")
+	hint(KeyRefresh, "refresh")
+	hint(KeyBoot, "boot")
+	hint(KeyShutdown, "stop")
+	hint(KeyDelete, "delete")
+	hint(KeyLogs, "logs")
+	if m.sidebar != nil && m.sidebar.Section == SectionAgents {
+		hint(KeyNudge, "nudge")
+		hint(KeyAttachSession, "attach")
+		hint(KeyRestart, "restart")
+		hint(KeyKill, "kill")
+		hint(KeyMail, "mail")
+		hint(KeySling, "sling")
+		hint(KeyHandoff, "handoff")
+	}
+	if m.sidebar != nil && m.sidebar.Section == SectionPlugins {
+		hint(KeyEdit, "toggle")
+	}
+	hint(KeyPalette, "palette")
+	hint(KeyHelp, "help")
+	hint(KeyQuit, "quit")
+	return hints
+}
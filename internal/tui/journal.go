@@ -0,0 +1,332 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/journal"
+)
+
+// journalLimit is how many entries the journal panel loads.
+const journalLimit = 500
+
+// journalActions names the actions that can be undone, or undo another,
+// with the stable keys stored in journal entries.
+var journalActions = map[string]ActionType{
+	"close_bead":        ActionCloseBead,
+	"reopen_bead":       ActionReopenBead,
+	"add_dependency":    ActionAddDependency,
+	"remove_dependency": ActionRemoveDependency,
+	"mark_mail_read":    ActionMarkMailRead,
+	"mark_mail_unread":  ActionMarkMailUnread,
+	"stop_polecat":      ActionStopPolecat,
+	"start_session":     ActionStartSession,
+	"toggle_plugin":     ActionTogglePlugin,
+	"start_deacon":      ActionStartDeacon,
+	"stop_deacon":       ActionStopDeacon,
+	"start_witness":     ActionStartWitness,
+	"stop_witness":      ActionStopWitness,
+	"start_refinery":    ActionStartRefinery,
+	"stop_refinery":     ActionStopRefinery,
+}
+
+// inverseActions maps each reversible action to the action that undoes it.
+var inverseActions = map[ActionType]ActionType{
+	ActionCloseBead:        ActionReopenBead,
+	ActionReopenBead:       ActionCloseBead,
+	ActionAddDependency:    ActionRemoveDependency,
+	ActionRemoveDependency: ActionAddDependency,
+	ActionMarkMailRead:     ActionMarkMailUnread,
+	ActionMarkMailUnread:   ActionMarkMailRead,
+	ActionStopPolecat:      ActionStartSession,
+	ActionTogglePlugin:     ActionTogglePlugin,
+	ActionStartDeacon:      ActionStopDeacon,
+	ActionStopDeacon:       ActionStartDeacon,
+	ActionStartWitness:     ActionStopWitness,
+	ActionStopWitness:      ActionStartWitness,
+	ActionStartRefinery:    ActionStopRefinery,
+	ActionStopRefinery:     ActionStartRefinery,
+}
+
+// journalKey returns the stable journal key of an action.
+func journalKey(action ActionType) string {
+	for key, a := range journalActions {
+		if a == action {
+			return key
+		}
+	}
+	return ""
+}
+
+// journalRecorder wraps a command runner to capture the commands an action
+// runs and their output.
+type journalRecorder struct {
+	inner data.CommandRunner
+
+	mu       sync.Mutex
+	commands []string
+	output   strings.Builder
+}
+
+// Exec implements data.CommandRunner.
+func (r *journalRecorder) Exec(ctx context.Context, workDir string, args ...string) ([]byte, []byte, error) {
+	stdout, stderr, err := r.inner.Exec(ctx, workDir, args...)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands = append(r.commands, strings.Join(args, " "))
+	for _, out := range [][]byte{stdout, stderr} {
+		if text := strings.TrimSpace(string(out)); text != "" {
+			if r.output.Len() > 0 {
+				r.output.WriteString("\n")
+			}
+			r.output.WriteString(text)
+		}
+	}
+	return stdout, stderr, err
+}
+
+// recordingRunner returns a copy of the action runner whose commands are
+// captured for the action journal.
+func (m Model) recordingRunner() (*ActionRunner, *journalRecorder) {
+	rec := &journalRecorder{inner: m.actionRunner.Runner}
+	runner := *m.actionRunner
+	runner.Runner = rec
+	return &runner, rec
+}
+
+// journalAction appends an action's outcome to the action journal. It is
+// called from commands, so a failed write is kept on the journal and shown
+// in the journal panel rather than in the status bar.
+func (m Model) journalAction(rec *journalRecorder, action ActionType, target, input string, err error) {
+	// Dry runs and refused actions change nothing, so there is nothing to
+	// account for
+	if _, dry := m.actionRunner.DryRun(); m.journal == nil || dry || errors.Is(err, ErrReadOnly) {
+		return
+	}
+	e := journal.Entry{
+		Time:   now(),
+		Town:   m.townRoot,
+		Action: actionName(action),
+		Target: target,
+		Input:  input,
+		UndoOf: m.undoOf,
+	}
+	rec.mu.Lock()
+	e.Commands = rec.commands
+	e.Output = rec.output.String()
+	rec.mu.Unlock()
+	if err != nil {
+		e.Error = err.Error()
+	} else if inverse, ok := inverseActions[action]; ok {
+		e.Undo = &journal.Undo{Action: journalKey(inverse), Target: target, Input: input}
+	}
+	m.journal.Append(e)
+}
+
+// undoCmd runs the action that reverses a journal entry. The undo is
+// journaled with a link back to the entry.
+func (m Model) undoCmd(e journal.Entry) tea.Cmd {
+	action, ok := journalActions[e.Undo.Action]
+	if !ok {
+		return func() tea.Msg {
+			return actionCompleteMsg{action: ActionRefresh, err: fmt.Errorf("unknown undo action %q", e.Undo.Action)}
+		}
+	}
+	// The commands below capture m, so the link only reaches this undo
+	m.undoOf = e.ID
+	switch action {
+	case ActionMarkMailRead, ActionMarkMailUnread:
+		return m.mailActionCmd(action, e.Undo.Target)
+	case ActionAddDependency:
+		return m.addDependencyCmd(e.Undo.Target, e.Undo.Input)
+	case ActionRemoveDependency:
+		return m.removeDependencyCmd(e.Undo.Target, e.Undo.Input)
+	}
+	return m.actionCmdWithInput(action, e.Undo.Target, e.Undo.Input, "")
+}
+
+// JournalView is the action journal browser.
+type JournalView struct {
+	Entries   []journal.Entry // Newest first
+	Undone    map[string]bool
+	Selection int
+	Err       error
+}
+
+// openJournal loads the journal into the browser.
+func (m *Model) openJournal() {
+	view := &JournalView{}
+	if m.journal == nil {
+		view.Err = fmt.Errorf("no action journal is configured")
+		m.journalView = view
+		return
+	}
+	entries, err := m.journal.Load(journalLimit)
+	if err == nil {
+		err = m.journal.LastError()
+	}
+	view.Err = err
+	view.Undone = journal.Undone(entries)
+	for i := len(entries) - 1; i >= 0; i-- {
+		view.Entries = append(view.Entries, entries[i])
+	}
+	m.journalView = view
+}
+
+// Selected returns the highlighted entry.
+func (v *JournalView) Selected() (journal.Entry, bool) {
+	if v.Selection < 0 || v.Selection >= len(v.Entries) {
+		return journal.Entry{}, false
+	}
+	return v.Entries[v.Selection], true
+}
+
+// CanUndo reports whether an entry can still be undone.
+func (v *JournalView) CanUndo(e journal.Entry) bool {
+	return e.Undo != nil && !e.Failed() && !v.Undone[e.ID]
+}
+
+// handleJournalKey handles key presses in the journal browser.
+func (m Model) handleJournalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	view := m.journalView
+	switch msg.String() {
+	case "esc", "q":
+		m.journalView = nil
+		return m, nil
+
+	case "j", "down":
+		if view.Selection < len(view.Entries)-1 {
+			view.Selection++
+		}
+		return m, nil
+
+	case "k", "up":
+		if view.Selection > 0 {
+			view.Selection--
+		}
+		return m, nil
+
+	case "u":
+		e, ok := view.Selected()
+		if !ok {
+			return m, nil
+		}
+		if m.readOnly() {
+			return m.refuseReadOnly()
+		}
+		if !view.CanUndo(e) {
+			m.setStatus(e.Action+" cannot be undone", true)
+			return m, statusExpireCmd(3 * time.Second)
+		}
+		undo := journalActions[e.Undo.Action]
+		m.journalView = nil
+		m.pendingUndo = &e
+		m.confirmDialog = &ConfirmDialog{
+			Title:   "Confirm Undo",
+			Message: fmt.Sprintf("Undo %s %s by %s? This runs %s. (y/n)", strings.ToLower(e.Action), e.Target, e.User, strings.ToLower(actionName(undo))),
+			Action:  undo,
+			Target:  e.Undo.Target,
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// renderJournal renders the action journal browser.
+func (m Model) renderJournal() string {
+	view := m.journalView
+	width := min(110, m.width-4)
+	// Horizontal padding takes 4 columns
+	inner := width - 4
+
+	var b strings.Builder
+	b.WriteString(helpTitleStyle.Render("Action Journal"))
+	b.WriteString("\n\n")
+	if view.Err != nil {
+		b.WriteString(statusErrorStyle.Render(truncate("Journal: "+view.Err.Error(), inner)))
+		b.WriteString("\n\n")
+	}
+
+	// Leave room for the border, padding, title, details and footer
+	rows := max(m.height-18, 3)
+	if len(view.Entries) == 0 {
+		b.WriteString(mutedStyle.Render("No actions recorded yet"))
+		b.WriteString("\n")
+	}
+	start := 0
+	if view.Selection >= rows {
+		start = view.Selection - rows + 1
+	}
+	for i := start; i < len(view.Entries) && i < start+rows; i++ {
+		e := view.Entries[i]
+		mark := "✓"
+		switch {
+		case e.Failed():
+			mark = "✗"
+		case view.Undone[e.ID]:
+			mark = "↶"
+		}
+		line := fmt.Sprintf("%s %s %-16s %s %s", e.Time.Local().Format("01-02 15:04"), mark, truncate(e.User, 16), e.Action, e.Target)
+		line = truncate(line, inner-2)
+		if i == view.Selection {
+			b.WriteString(selectedItemStyle.Render("> " + line))
+		} else if e.Failed() {
+			b.WriteString(statusErrorStyle.Render("  " + line))
+		} else {
+			b.WriteString(itemStyle.Render("  " + line))
+		}
+		b.WriteString("\n")
+	}
+
+	if e, ok := view.Selected(); ok {
+		b.WriteString("\n")
+		details := []string{}
+		if e.Input != "" {
+			details = append(details, "Input:   "+e.Input)
+		}
+		for _, cmd := range e.Commands {
+			details = append(details, "Command: "+cmd)
+		}
+		if e.Error != "" {
+			details = append(details, "Error:   "+e.Error)
+		} else if e.Output != "" {
+			details = append(details, "Output:  "+strings.SplitN(e.Output, "\n", 2)[0])
+		}
+		switch {
+		case e.UndoOf != "":
+			details = append(details, "Undoes an earlier action")
+		case view.Undone[e.ID]:
+			details = append(details, "Undone")
+		case view.CanUndo(e) && !m.readOnly():
+			details = append(details, "u: undo ("+actionName(journalActions[e.Undo.Action])+" "+e.Undo.Target+")")
+		}
+		for _, line := range details {
+			b.WriteString(mutedStyle.Render(truncate(line, inner)))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if m.readOnly() {
+		b.WriteString(mutedStyle.Render("j/k: select • esc: close"))
+	} else {
+		b.WriteString(mutedStyle.Render("j/k: select • u: undo • esc: close"))
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(1, 2).
+		Width(width).
+		Render(b.String())
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestActionsAreJournaled(t *testing.T) {
+	m, mock := createTestModel(t)
+	mock.On([]string{"bd", "close", "pe-1"}, []byte("Closed pe-1"), nil, nil)
+	mock.On([]string{"gt", "rig", "boot", "perch"}, nil, []byte("no such rig"), errors.New("exit status 1"))
+
+	m.actionCmd(ActionCloseBead, "pe-1")()
+	m.actionCmd(ActionBootRig, "perch")()
+
+	entries, err := m.journal.Load(0)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d (%v)", len(entries), err)
+	}
+	closed := entries[0]
+	if closed.Action != "Close bead" || closed.Target != "pe-1" || closed.User == "" || closed.Town == "" {
+		t.Errorf("unexpected entry %+v", closed)
+	}
+	if len(closed.Commands) != 1 || closed.Commands[0] != "bd close pe-1" || closed.Output != "Closed pe-1" {
+		t.Errorf("expected the command and its output, got %q %q", closed.Commands, closed.Output)
+	}
+	if closed.Undo == nil || closed.Undo.Action != "reopen_bead" || closed.Undo.Target != "pe-1" {
+		t.Errorf("expected a reopen undo, got %+v", closed.Undo)
+	}
+
+	boot := entries[1]
+	if !boot.Failed() || !strings.Contains(boot.Error, "no such rig") || boot.Undo != nil {
+		t.Errorf("expected a failed boot without undo, got %+v", boot)
+	}
+}
+
+func TestJournalUndo(t *testing.T) {
+	m, mock := createTestModel(t)
+	m.actionCmd(ActionCloseBead, "pe-1")()
+
+	m, _ = sendKey(m, "J")
+	if m.journalView == nil || len(m.journalView.Entries) != 1 {
+		t.Fatalf("expected the journal with one entry, got %+v", m.journalView)
+	}
+	if view := m.View(); !strings.Contains(view, "Action Journal") || !strings.Contains(view, "u: undo (Reopen bead pe-1)") {
+		t.Errorf("expected the entry and its undo, got:\n%s", view)
+	}
+
+	m, _ = sendKey(m, "u")
+	if m.confirmDialog == nil || m.pendingUndo == nil {
+		t.Fatal("expected an undo confirmation")
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(Model)
+	if msg, ok := cmd().(actionCompleteMsg); !ok || msg.action != ActionReopenBead || msg.err != nil {
+		t.Fatalf("expected a reopen, got %+v", msg)
+	}
+	if !mock.CalledWith([]string{"bd", "update", "pe-1", "--status", "open"}) {
+		t.Errorf("expected bd update pe-1 --status open, got %v", mock.Calls())
+	}
+	if m.undoOf != "" {
+		t.Error("expected the undo link not to leak into the model")
+	}
+
+	m, _ = sendKey(m, "J")
+	view := m.journalView
+	if len(view.Entries) != 2 || view.Entries[0].UndoOf != view.Entries[1].ID {
+		t.Fatalf("expected the reopen linked to the close, got %+v", view.Entries)
+	}
+	if view.CanUndo(view.Entries[1]) {
+		t.Error("expected the close to be undone already")
+	}
+	view.Selection = 1
+	m, _ = sendKey(m, "u")
+	if m.confirmDialog != nil || m.statusMessage == nil || !m.statusMessage.IsError {
+		t.Error("expected a second undo of the same entry to be refused")
+	}
+}
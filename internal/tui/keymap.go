@@ -1,91 +1,389 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"fmt"
+	"sort"
+	"strings"
 
-// KeyMap defines all keybindings for the TUI.
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyAction names a bindable command in the main view. The names are used
+// as setting names in the [keys] section of the config file.
+type KeyAction string
+
+// General
+const (
+	KeyQuit         KeyAction = "quit"
+	KeyHelp         KeyAction = "help"
+	KeyPalette      KeyAction = "palette"
+	KeySearch       KeyAction = "search"
+	KeyJournal      KeyAction = "journal"
+	KeyDryRun       KeyAction = "dry_run"
+	KeyRefresh      KeyAction = "refresh"
+	KeyReloadConfig KeyAction = "reload_config"
+	KeyAttachTown   KeyAction = "attach_town"
+	KeyTowns        KeyAction = "towns"
+	KeyTownMap      KeyAction = "town_map"
+	KeyExport       KeyAction = "export_snapshot"
+)
+
+// Navigation
+const (
+	KeyUp        KeyAction = "up"
+	KeyDown      KeyAction = "down"
+	KeyLeft      KeyAction = "left"
+	KeyRight     KeyAction = "right"
+	KeyNextPanel KeyAction = "next_panel"
+	KeyPrevPanel KeyAction = "prev_panel"
+	KeySelect    KeyAction = "select"
+	KeyMark      KeyAction = "mark"
+
+	KeySectionIdentity   KeyAction = "section_identity"
+	KeySectionRigs       KeyAction = "section_rigs"
+	KeySectionConvoys    KeyAction = "section_convoys"
+	KeySectionMergeQueue KeyAction = "section_merge_queue"
+	KeySectionAgents     KeyAction = "section_agents"
+	KeySectionMail       KeyAction = "section_mail"
+	KeySectionLifecycle  KeyAction = "section_lifecycle"
+	KeySectionWorktrees  KeyAction = "section_worktrees"
+	KeySectionPlugins    KeyAction = "section_plugins"
+	KeySectionAlerts     KeyAction = "section_alerts"
+	KeySectionOperator   KeyAction = "section_operator"
+)
+
+// Town, rig and merge queue actions
+const (
+	KeyAddRig      KeyAction = "add_rig"
+	KeyNewWork     KeyAction = "new_work"
+	KeyBoot        KeyAction = "boot"
+	KeyShutdown    KeyAction = "shutdown"
+	KeyDelete      KeyAction = "delete"
+	KeyEdit        KeyAction = "edit"
+	KeyLogs        KeyAction = "logs"
+	KeyBlockers    KeyAction = "blockers"
+	KeyNudge       KeyAction = "nudge"
+	KeyStopIdle    KeyAction = "stop_idle"
+	KeyStopAllIdle KeyAction = "stop_all_idle"
+	KeyClear       KeyAction = "clear"
+	KeyFix         KeyAction = "fix"
+)
+
+// Agent actions
+const (
+	KeySling         KeyAction = "sling"
+	KeyHandoff       KeyAction = "handoff"
+	KeyKill          KeyAction = "kill"
+	KeyRestart       KeyAction = "restart"
+	KeyMail          KeyAction = "mail"
+	KeyAttachSession KeyAction = "attach_session"
+	KeyOpenSession   KeyAction = "open_session"
+	KeyViewOutput    KeyAction = "view_output"
+)
+
+// Mail actions
+const (
+	KeyAckMail        KeyAction = "ack_mail"
+	KeyMailRigFilter  KeyAction = "mail_rig_filter"
+	KeyMailRoleFilter KeyAction = "mail_role_filter"
+	KeyUnreadFilter   KeyAction = "unread_filter"
+	KeyMarkAllRead    KeyAction = "mark_all_read"
+	KeyArchiveAll     KeyAction = "archive_all"
+)
+
+// Bead actions
+const (
+	KeyBeadsFilter    KeyAction = "beads_filter"
+	KeyPriorityFilter KeyAction = "priority_filter"
+	KeyAssigneeFilter KeyAction = "assignee_filter"
+	KeyRefile         KeyAction = "refile"
+	KeyCloseBead      KeyAction = "close_bead"
+	KeyReopenBead     KeyAction = "reopen_bead"
+)
+
+// Snapshot history
+const (
+	KeyHistoryBack        KeyAction = "history_back"
+	KeyHistoryForward     KeyAction = "history_forward"
+	KeyHistoryJumpBack    KeyAction = "history_jump_back"
+	KeyHistoryJumpForward KeyAction = "history_jump_forward"
+	KeyHistoryHourBack    KeyAction = "history_hour_back"
+	KeyHistoryHourForward KeyAction = "history_hour_forward"
+)
+
+// Agent detail dialog
+const (
+	KeyDetailNudge   KeyAction = "detail_nudge"
+	KeyDetailAttach  KeyAction = "detail_attach"
+	KeyDetailMail    KeyAction = "detail_mail"
+	KeyDetailHandoff KeyAction = "detail_handoff"
+	KeyDetailStop    KeyAction = "detail_stop"
+	KeyDetailStart   KeyAction = "detail_start"
+)
+
+// Binding is the keys for one action and how help describes it.
+type Binding struct {
+	Action KeyAction
+	Keys   []string
+	Group  string // Help section
+	Desc   string // Help description
+}
+
+// Label returns the keys as shown in help, e.g. "j/down". Unbound actions
+// show as "-".
+func (b Binding) Label() string {
+	if len(b.Keys) == 0 {
+		return "-"
+	}
+	return strings.Join(b.Keys, "/")
+}
+
+// Help sections, in display order.
+const (
+	groupGeneral    = "General"
+	groupNavigation = "Navigation"
+	groupActions    = "Actions"
+	groupAgents     = "Agent Actions"
+	groupMail       = "Mail Actions"
+	groupBeads      = "Bead Actions"
+	groupHistory    = "Snapshot History"
+	groupDetail     = "Agent Details"
+)
+
+// dialogGroups are help sections whose keys are only read inside a dialog.
+// They may reuse main-view keys, but not each other's.
+var dialogGroups = map[string]bool{groupDetail: true}
+
+// defaultBindings lists every action with its default keys, in help order.
+var defaultBindings = []Binding{
+	{KeyHelp, []string{"?"}, groupGeneral, "Show this help"},
+	{KeyQuit, []string{"q", "ctrl+c"}, groupGeneral, "Quit"},
+	{KeyPalette, []string{"ctrl+p", ":"}, groupGeneral, "Command palette"},
+	{KeySearch, []string{"/"}, groupGeneral, "Search the snapshot"},
+	{KeyJournal, []string{"J"}, groupGeneral, "Action journal and undo"},
+	{KeyDryRun, []string{"ctrl+d"}, groupGeneral, "Toggle dry run (preview commands)"},
+	{KeyRefresh, []string{"r"}, groupGeneral, "Refresh data / Retry MR / Restart"},
+	{KeyReloadConfig, []string{"ctrl+r"}, groupGeneral, "Reload config file"},
+	{KeyAttachTown, []string{"A"}, groupGeneral, "Attach to a different town"},
+	{KeyTowns, []string{"W"}, groupGeneral, "Towns overview and switcher"},
+	{KeyTownMap, []string{"V"}, groupGeneral, "Town map / Mark range"},
+	{KeyExport, []string{"D"}, groupGeneral, "Export snapshot to JSON"},
+
+	{KeyUp, []string{"k", "up"}, groupNavigation, "Move up"},
+	{KeyDown, []string{"j", "down"}, groupNavigation, "Move down"},
+	{KeyLeft, []string{"h", "left"}, groupNavigation, "Previous section"},
+	{KeyRight, []string{"l", "right"}, groupNavigation, "Next section / Open MR logs"},
+	{KeyNextPanel, []string{"tab"}, groupNavigation, "Next panel"},
+	{KeyPrevPanel, []string{"shift+tab"}, groupNavigation, "Previous panel"},
+	{KeySelect, []string{"enter"}, groupNavigation, "Agent details"},
+	{KeyMark, []string{"space"}, groupNavigation, "Mark item for bulk actions"},
+	{KeySectionIdentity, []string{"0"}, groupNavigation, "Jump to Identity"},
+	{KeySectionRigs, []string{"1"}, groupNavigation, "Jump to Rigs"},
+	{KeySectionConvoys, []string{"2"}, groupNavigation, "Jump to Convoys"},
+	{KeySectionMergeQueue, []string{"3"}, groupNavigation, "Jump to Merge Queue"},
+	{KeySectionAgents, []string{"4"}, groupNavigation, "Jump to Agents"},
+	{KeySectionMail, []string{"5"}, groupNavigation, "Jump to Mail"},
+	{KeySectionLifecycle, []string{"6"}, groupNavigation, "Jump to Lifecycle"},
+	{KeySectionWorktrees, []string{"7"}, groupNavigation, "Jump to Worktrees"},
+	{KeySectionPlugins, []string{"8"}, groupNavigation, "Jump to Plugins"},
+	{KeySectionAlerts, []string{"9"}, groupNavigation, "Jump to Alerts"},
+	{KeySectionOperator, []string{"-"}, groupNavigation, "Operator console"},
+
+	{KeyAddRig, []string{"a"}, groupActions, "Add new rig"},
+	{KeyNewWork, []string{"w"}, groupActions, "Create new work"},
+	{KeyBoot, []string{"b"}, groupActions, "Boot rig / Start agent / Edit bead"},
+	{KeyShutdown, []string{"s"}, groupActions, "Shutdown rig / Stop subsystem"},
+	{KeyDelete, []string{"d"}, groupActions, "Delete rig / MR details / Deps"},
+	{KeyEdit, []string{"e"}, groupActions, "Edit rig / Cycle filter / Plugin"},
+	{KeyLogs, []string{"o"}, groupActions, "Open agent or refinery logs"},
+	{KeyBlockers, []string{"v"}, groupActions, "View MR blockers"},
+	{KeyNudge, []string{"n"}, groupActions, "Nudge agent or MR polecat"},
+	{KeyStopIdle, []string{"c"}, groupActions, "Stop idle polecat / Comment"},
+	{KeyStopAllIdle, []string{"C"}, groupActions, "Stop all idle polecats in rig"},
+	{KeyClear, []string{"x"}, groupActions, "Remove worktree / Clear filters"},
+	{KeyFix, []string{"F"}, groupActions, "Run a doctor check's fix"},
+
+	{KeySling, []string{"S"}, groupAgents, "Sling work to agent"},
+	{KeyHandoff, []string{"H"}, groupAgents, "Handoff / Toggle convoy history"},
+	{KeyKill, []string{"K"}, groupAgents, "Kill/stop agent"},
+	{KeyRestart, []string{"R"}, groupAgents, "Restart agent session"},
+	{KeyMail, []string{"m"}, groupAgents, "Mail agent / Toggle read"},
+	{KeyAttachSession, []string{"t"}, groupAgents, "Attach session / Type filter"},
+	{KeyOpenSession, []string{"T"}, groupAgents, "Open session (advanced)"},
+	{KeyViewOutput, []string{"L"}, groupAgents, "View recent output"},
+
+	{KeyAckMail, []string{"y"}, groupMail, "Acknowledge mail"},
+	{KeyMailRigFilter, []string{"G"}, groupMail, "Cycle rig filter"},
+	{KeyMailRoleFilter, []string{"O"}, groupMail, "Cycle role filter"},
+	{KeyUnreadFilter, []string{"u"}, groupMail, "Toggle unread only"},
+	{KeyMarkAllRead, []string{"B"}, groupMail, "Mark all visible mail read"},
+	{KeyArchiveAll, []string{"X"}, groupMail, "Archive all visible mail"},
+
+	{KeyBeadsFilter, []string{"f"}, groupBeads, "Open filter dialog"},
+	{KeyPriorityFilter, []string{"p"}, groupBeads, "Cycle priority filter"},
+	{KeyAssigneeFilter, []string{"g"}, groupBeads, "Filter by assignee or agent"},
+	{KeyRefile, []string{"M"}, groupBeads, "Move bead to another rig"},
+	{KeyCloseBead, []string{"z"}, groupBeads, "Close bead"},
+	{KeyReopenBead, []string{"Z"}, groupBeads, "Reopen bead"},
+
+	{KeyHistoryBack, []string{"["}, groupHistory, "Browse history / Step back"},
+	{KeyHistoryForward, []string{"]"}, groupHistory, "Step forward"},
+	{KeyHistoryJumpBack, []string{"{"}, groupHistory, "Jump back one minute"},
+	{KeyHistoryJumpForward, []string{"}"}, groupHistory, "Jump forward one minute"},
+	{KeyHistoryHourBack, []string{"("}, groupHistory, "Jump back one hour"},
+	{KeyHistoryHourForward, []string{")"}, groupHistory, "Jump forward one hour"},
+
+	{KeyDetailNudge, []string{"n"}, groupDetail, "Send a preset nudge"},
+	{KeyDetailAttach, []string{"a"}, groupDetail, "Attach session"},
+	{KeyDetailMail, []string{"m"}, groupDetail, "Send mail"},
+	{KeyDetailHandoff, []string{"h"}, groupDetail, "Handoff work (running)"},
+	{KeyDetailStop, []string{"s"}, groupDetail, "Stop agent (running)"},
+	{KeyDetailStart, []string{"t"}, groupDetail, "Start session (stopped)"},
+}
+
+// helpGroups lists help sections in display order.
+var helpGroups = []string{groupGeneral, groupNavigation, groupActions, groupAgents, groupMail, groupBeads, groupHistory, groupDetail}
+
+// KeyMap is the registry of keybindings for the main view.
 type KeyMap struct {
-	// Left moves to the previous panel.
-	Left key.Binding
-	// Right moves to the next panel.
-	Right key.Binding
-	// Up moves up within the current panel.
-	Up key.Binding
-	// Down moves down within the current panel.
-	Down key.Binding
-	// NextPanel cycles to the next panel (tab).
-	NextPanel key.Binding
-	// PrevPanel cycles to the previous panel (shift+tab).
-	PrevPanel key.Binding
-
-	// Select activates the selected item.
-	Select key.Binding
-
-	// Refresh reloads the current view.
-	Refresh key.Binding
-	// Help toggles the help screen.
-	Help key.Binding
-	// Quit exits the application.
-	Quit key.Binding
-}
-
-// DefaultKeyMap returns the default keybindings configured for the TUI.
+	bindings []Binding
+	byKey    map[string]KeyAction
+	byDialog map[string]map[string]KeyAction // Keys by dialog group
+}
+
+// DefaultKeyMap returns the default keybindings.
 func DefaultKeyMap() KeyMap {
-	return KeyMap{
-		Left: key.NewBinding(
-			key.WithKeys("h", "left"),
-			key.WithHelp("h/←", "panel left"),
-		),
-		Right: key.NewBinding(
-			key.WithKeys("l", "right"),
-			key.WithHelp("l/→", "panel right"),
-		),
-		Up: key.NewBinding(
-			key.WithKeys("k", "up"),
-			key.WithHelp("k/↑", "move up"),
-		),
-		Down: key.NewBinding(
-			key.WithKeys("j", "down"),
-			key.WithHelp("j/↓", "move down"),
-		),
-		NextPanel: key.NewBinding(
-			key.WithKeys("tab"),
-			key.WithHelp("tab", "next panel"),
-		),
-		PrevPanel: key.NewBinding(
-			key.WithKeys("shift+tab"),
-			key.WithHelp("S-tab", "prev panel"),
-		),
-		Select: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "select"),
-		),
-		Refresh: key.NewBinding(
-			key.WithKeys("r"),
-			key.WithHelp("r", "refresh"),
-		),
-		Help: key.NewBinding(
-			key.WithKeys("?"),
-			key.WithHelp("?", "help"),
-		),
-		Quit: key.NewBinding(
-			key.WithKeys("q", "ctrl+c"),
-			key.WithHelp("q", "quit"),
-		),
+	km, err := NewKeyMap(nil)
+	if err != nil {
+		panic("tui: default keybindings conflict: " + err.Error())
+	}
+	return km
+}
+
+// NewKeyMap returns the default keybindings with overrides applied.
+// Each override replaces all keys for an action; an empty list unbinds it.
+// Unknown actions and keys bound to more than one action in the same view
+// are errors.
+func NewKeyMap(overrides map[string][]string) (KeyMap, error) {
+	km := KeyMap{byKey: make(map[string]KeyAction), byDialog: make(map[string]map[string]KeyAction)}
+	known := make(map[KeyAction]bool, len(defaultBindings))
+	for _, b := range defaultBindings {
+		known[b.Action] = true
 	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !known[KeyAction(name)] {
+			return KeyMap{}, fmt.Errorf("keys.%s: unknown action", name)
+		}
+		for _, k := range overrides[name] {
+			if k == "" {
+				return KeyMap{}, fmt.Errorf("keys.%s: empty key", name)
+			}
+		}
+	}
+
+	for _, b := range defaultBindings {
+		if keys, ok := overrides[string(b.Action)]; ok {
+			b.Keys = append([]string(nil), keys...)
+		}
+		byKey := km.byKey
+		if dialogGroups[b.Group] {
+			if km.byDialog[b.Group] == nil {
+				km.byDialog[b.Group] = make(map[string]KeyAction)
+			}
+			byKey = km.byDialog[b.Group]
+		}
+		for _, k := range b.Keys {
+			if other, dup := byKey[k]; dup && other != b.Action {
+				return KeyMap{}, fmt.Errorf("keys: %q is bound to both %s and %s", k, other, b.Action)
+			}
+			byKey[k] = b.Action
+		}
+		km.bindings = append(km.bindings, b)
+	}
+	return km, nil
+}
+
+// Lookup returns the action bound to a key (as reported by tea.KeyMsg.String),
+// or "" if none is. The space bar reports " " but is bound as "space".
+func (k KeyMap) Lookup(keyName string) KeyAction {
+	if keyName == " " {
+		keyName = "space"
+	}
+	return k.byKey[keyName]
+}
+
+// LookupDialog is Lookup for the keys of one dialog's help section.
+func (k KeyMap) LookupDialog(group, keyName string) KeyAction {
+	return k.byDialog[group][keyName]
+}
+
+// Binding returns the binding for an action.
+func (k KeyMap) Binding(action KeyAction) Binding {
+	for _, b := range k.bindings {
+		if b.Action == action {
+			return b
+		}
+	}
+	return Binding{Action: action}
+}
+
+// Key returns the first key bound to an action, for hints like "press 4".
+func (k KeyMap) Key(action KeyAction) string {
+	b := k.Binding(action)
+	if len(b.Keys) == 0 {
+		return "-"
+	}
+	return b.Keys[0]
+}
+
+// Hint formats a footer hint such as "r: refresh".
+func (k KeyMap) Hint(action KeyAction, label string) string {
+	return k.Key(action) + ": " + label
+}
+
+// Bindings returns every binding in help order.
+func (k KeyMap) Bindings() []Binding {
+	return k.bindings
+}
+
+// Group returns the bindings in one help section.
+func (k KeyMap) Group(name string) []Binding {
+	var out []Binding
+	for _, b := range k.bindings {
+		if b.Group == name {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// keyBinding converts a binding for the bubbles help component.
+func (b Binding) keyBinding() key.Binding {
+	return key.NewBinding(key.WithKeys(b.Keys...), key.WithHelp(b.Label(), strings.ToLower(b.Desc)))
 }
 
 // ShortHelp returns keybindings to show in the mini help view.
 // It implements the help.KeyMap interface.
 func (k KeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Help, k.Quit}
+	return []key.Binding{k.Binding(KeyHelp).keyBinding(), k.Binding(KeyQuit).keyBinding()}
 }
 
 // FullHelp returns keybindings for the expanded help view.
 // It implements the help.KeyMap interface.
 func (k KeyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{
-		{k.Up, k.Down, k.Left, k.Right},
-		{k.NextPanel, k.PrevPanel, k.Select},
-		{k.Refresh, k.Help, k.Quit},
+	var groups [][]key.Binding
+	for _, name := range helpGroups {
+		var group []key.Binding
+		for _, b := range k.Group(name) {
+			group = append(group, b.keyBinding())
+		}
+		groups = append(groups, group)
 	}
+	return groups
 }
@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/config"
+)
+
+func TestDefaultKeyMap(t *testing.T) {
+	km := DefaultKeyMap()
+	tests := map[string]KeyAction{
+		"q":      KeyQuit,
+		"ctrl+c": KeyQuit,
+		"j":      KeyDown,
+		"down":   KeyDown,
+		"4":      KeySectionAgents,
+		"[":      KeyHistoryBack,
+		"ctrl+r": KeyReloadConfig,
+		"Z":      KeyReopenBead,
+		"F":      KeyFix,
+		"Q":      "",
+	}
+	for k, want := range tests {
+		if got := km.Lookup(k); got != want {
+			t.Errorf("Lookup(%q) = %q, want %q", k, got, want)
+		}
+	}
+
+	for _, b := range km.Bindings() {
+		if b.Desc == "" || b.Group == "" {
+			t.Errorf("binding %s needs a group and description for help", b.Action)
+		}
+	}
+}
+
+func TestNewKeyMapOverrides(t *testing.T) {
+	km, err := NewKeyMap(map[string][]string{
+		"refresh":     {"f5", "r"},
+		"archive_all": {},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if km.Lookup("f5") != KeyRefresh || km.Lookup("r") != KeyRefresh {
+		t.Error("expected f5 and r to refresh")
+	}
+	if km.Lookup("X") != "" {
+		t.Error("expected X unbound")
+	}
+	if got := km.Binding(KeyArchiveAll).Label(); got != "-" {
+		t.Errorf("expected unbound label -, got %q", got)
+	}
+	if got := km.Hint(KeyRefresh, "refresh"); got != "f5: refresh" {
+		t.Errorf("unexpected hint %q", got)
+	}
+}
+
+func TestNewKeyMapErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		want      string
+	}{
+		{"unknown action", map[string][]string{"refersh": {"f5"}}, "keys.refersh: unknown action"},
+		{"empty key", map[string][]string{"refresh": {""}}, "keys.refresh: empty key"},
+		{"conflicts with default", map[string][]string{"refresh": {"x"}}, `"x" is bound to both refresh and clear`},
+		{"conflicting overrides", map[string][]string{"quit": {"Q"}, "help": {"Q"}}, `"Q" is bound to both help and quit`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyMap(tt.overrides)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestReboundKeysDriveModel(t *testing.T) {
+	m := NewTestModel(t)
+	m.ready = true
+	m.width, m.height = 200, 40
+
+	cfg := config.Default()
+	cfg.TownRoot = m.townRoot
+	cfg.Keys = map[string][]string{"help": {"H"}, "handoff": {"ctrl+h"}}
+	if err := m.applySettings(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	m = pressKey(t, m, "?")
+	if m.showHelp {
+		t.Error("expected ? to be unbound")
+	}
+	m = pressKey(t, m, "H")
+	if !m.showHelp {
+		t.Fatal("expected H to open help")
+	}
+	if view := m.renderHelpOverlay(); !strings.Contains(view, "ctrl+h") {
+		t.Error("expected help overlay to show the rebound handoff key")
+	}
+
+	m.showHelp = false
+	if hints := strings.Join(m.footerHints(), " | "); !strings.Contains(hints, "H: help") || strings.Contains(hints, "?: help") {
+		t.Errorf("expected footer to show the rebound help key, got %q", hints)
+	}
+}
+
+func TestReloadRejectsConflictingKeys(t *testing.T) {
+	m := NewTestModel(t)
+	cfg := config.Default()
+	cfg.TownRoot = m.townRoot
+	cfg.RefreshInterval = 99 * DefaultRefreshInterval
+	cfg.Keys = map[string][]string{"refresh": {"q"}}
+
+	updated, _ := m.Update(configReloadedMsg{cfg: cfg})
+	m = updated.(Model)
+
+	if m.statusMessage == nil || !strings.Contains(m.statusMessage.Text, "bound to both") {
+		t.Fatalf("expected conflict error, got %+v", m.statusMessage)
+	}
+	if m.refreshInterval != DefaultRefreshInterval || m.keyMap().Lookup("q") != KeyQuit {
+		t.Error("expected previous settings kept after a rejected reload")
+	}
+}
+
+func TestReboundAgentDetailKeys(t *testing.T) {
+	m := NewTestModel(t)
+	cfg := config.Default()
+	cfg.TownRoot = m.townRoot
+	// e is a main-view key too; dialog keys only conflict with each other
+	cfg.Keys = map[string][]string{"detail_mail": {"e"}}
+	if err := m.applySettings(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	open := func() Model {
+		m.agentDetailDialog = NewAgentDetailDialog(AgentEntry{Agent: data.Agent{Address: "perch/nux", Running: true}})
+		m.agentDetailDialog.Keys = m.keyMap()
+		return m
+	}
+	if actions := strings.Join(open().agentDetailDialog.getActions(), " | "); !strings.Contains(actions, "e: Send mail") {
+		t.Errorf("expected the dialog to show the rebound mail key, got %q", actions)
+	}
+
+	if got, _ := sendKey(open(), "m"); got.inputDialog != nil {
+		t.Error("expected m to be unbound in the dialog")
+	}
+	got, _ := sendKey(open(), "e")
+	if got.inputDialog == nil || got.inputDialog.Title != "Send Mail" || got.agentDetailDialog != nil {
+		t.Errorf("expected e to open the mail dialog, got %+v", got.inputDialog)
+	}
+
+	if _, err := NewKeyMap(map[string][]string{"detail_stop": {"n"}}); err == nil || !strings.Contains(err.Error(), "detail_nudge and detail_stop") {
+		t.Errorf("expected a conflict between dialog keys, got %v", err)
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"time"
 
 	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/config"
+	"github.com/andyrewlee/perch/internal/journal"
+	"github.com/andyrewlee/perch/internal/notify"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -35,6 +38,7 @@ type Model struct {
 	store    *data.Store
 	snapshot *data.Snapshot
 	townRoot string
+	remote   string // ssh destination when the town is on another machine
 
 	// Sidebar state
 	sidebar *SidebarState
@@ -69,6 +73,28 @@ type Model struct {
 	// Attach town dialog
 	attachDialog *AttachDialog
 
+	// Command palette and global search
+	palette *CommandPalette
+	search  *SearchOverlay
+
+	// Bulk action awaiting confirmation or input, and the last bulk report
+	bulk       *bulkOp
+	bulkReport *bulkCompleteMsg
+
+	// Action journal, its browser, and an undo awaiting confirmation.
+	// undoOf links the entries of an undo command to the entry it reverses.
+	journal     *journal.Journal
+	journalView *JournalView
+	pendingUndo *journal.Entry
+	undoOf      string
+
+	// Commands the last dry-run action would have run
+	dryRunPreview *dryRunPreview
+
+	// One store per registered town, and the towns overview
+	towns     *data.Towns
+	townsView *TownsView
+
 	// Rig settings form
 	rigSettingsForm *RigSettingsForm
 
@@ -98,6 +124,7 @@ type Model struct {
 	lastRefresh     time.Time
 	errorCount      int
 	isRefreshing    bool
+	watcher         *data.Watcher // Reloads sources as their files change (nil when polling)
 
 	// Queue health panel (shown when Merge Queue selected in sidebar)
 	queueHealthPanel *QueueHealthPanel
@@ -113,6 +140,20 @@ type Model struct {
 	// Town map view (interactive rig tiles)
 	townMapView  *TownMapView
 	showTownMap  bool // True when town map view is active
+
+	// Time travel through recorded snapshot history (nil when showing live data)
+	timeTravel *timeTravelState
+
+	// Desktop/terminal notifications for critical events (nil when disabled)
+	notifier *notify.Notifier
+
+	// Keybindings for the main view (zero value uses the defaults)
+	keys KeyMap
+
+	// User settings, and how to reread them (nil when reload is unavailable)
+	settings     config.Config
+	reloadConfig func() (config.Config, error)
+	nudges       []PresetNudge // Configured preset nudges (nil uses PresetNudges)
 }
 
 // GetDefaultTownRoot returns the default Gas Town root directory.
@@ -136,6 +177,7 @@ const DefaultRefreshInterval = 10 * time.Second
 type refreshMsg struct {
 	snapshot *data.Snapshot
 	err      error
+	partial  bool // Only some sources reloaded, after a file change
 }
 
 // tickMsg triggers periodic refresh
@@ -146,29 +188,59 @@ func New() Model {
 	return NewWithTownRoot(GetDefaultTownRoot())
 }
 
-// NewWithTownRoot creates a new Model with a custom town root.
+// NewWithTownRoot creates a new Model with a custom town root and default
+// settings. Notifications are configured from PERCH_NOTIFY* variables.
 func NewWithTownRoot(townRoot string) Model {
-	// Check if town exists
-	if !TownExists(townRoot) {
+	cfg := config.Default()
+	cfg.TownRoot = townRoot
+	err := cfg.Notify.ApplyEnv(os.Getenv)
+	if err != nil {
+		cfg.Notify = notify.Config{}
+	}
+	m := NewWithConfig(cfg, nil)
+	if err != nil {
+		m.setStatus("Notifications disabled: "+err.Error(), true)
+	}
+	return m
+}
+
+// NewWithConfig creates a new Model from user settings. reload rereads the
+// settings for a runtime reload (ctrl+r or ReloadConfigMsg); nil disables it.
+func NewWithConfig(cfg config.Config, reload func() (config.Config, error)) Model {
+	townRoot := cfg.TownRoot
+
+	// Check if town exists. A remote town is checked by its first load.
+	if cfg.Remote == "" && !TownExists(townRoot) {
 		// Show setup wizard for first-run
 		return Model{
-			townRoot:    townRoot,
-			setupWizard: NewSetupWizard(),
+			townRoot:     townRoot,
+			setupWizard:  NewSetupWizard(),
+			settings:     cfg,
+			reloadConfig: reload,
 		}
 	}
 
-	store := data.NewStore(townRoot)
-	store.RefreshInterval = 5 * time.Second
-
-	return Model{
+	m := Model{
 		focus:           PanelSidebar,
-		store:           store,
 		townRoot:        townRoot,
+		remote:          cfg.Remote,
 		sidebar:         NewSidebarState(),
-		actionRunner:    NewActionRunner(townRoot),
-		refreshInterval: DefaultRefreshInterval,
 		queueHealthData: make(map[string]QueueHealth),
+		reloadConfig:    reload,
 	}
+	m.store = m.dashboardStore(townRoot, nil)
+	m.actionRunner = m.newActionRunner(townRoot)
+	if path, err := journal.DefaultPath(); err == nil {
+		m.journal = journal.New(path)
+	}
+	m.actionRunner.SetDryRun(cfg.DryRun)
+	if err := m.applySettings(cfg); err != nil {
+		// Start with the default keys rather than refusing to start
+		cfg.Keys = nil
+		m.applySettings(cfg)
+		m.setStatus("Keybindings ignored: "+err.Error(), true)
+	}
+	return m
 }
 
 // NewFirstRun creates a new Model with help overlay shown (for first-time users).
@@ -240,24 +312,105 @@ func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.loadData,
 		m.tickCmd(),
+		m.startWatchCmd(),
 	)
 }
 
 // loadData loads data from the store
 func (m Model) loadData() tea.Msg {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), m.loadTimeout())
 	defer cancel()
 
 	snap := m.store.Refresh(ctx)
 	return refreshMsg{snapshot: snap, err: nil}
 }
 
-// tickCmd creates a tick command for periodic refresh
+// loadSourcesCmd reloads only the given sources into the store.
+func (m Model) loadSourcesCmd(sources []string) tea.Cmd {
+	store := m.store
+	timeout := m.loadTimeout()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		snap := store.RefreshSources(ctx, sources...)
+		return refreshMsg{snapshot: snap}
+	}
+}
+
+// retrySourceCmd reloads the source behind a load error now, closing its
+// circuit breaker first.
+func (m Model) retrySourceCmd(e data.LoadError) tea.Cmd {
+	store := m.store
+	timeout := m.loadTimeout()
+	key := e.Source
+	if e.Breaker != nil {
+		key = e.Breaker.Key
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		snap := store.Retry(ctx, key)
+		return refreshMsg{snapshot: snap}
+	}
+}
+
+// applySnapshot makes snap the displayed snapshot and updates derived state.
+func (m *Model) applySnapshot(snap *data.Snapshot) {
+	m.snapshot = snap
+	m.sidebar.UpdateFromSnapshot(snap)
+	m.updateQueueHealth(snap)
+
+	// Validate selected rig still exists, reset if not
+	if m.selectedRig != "" && snap != nil && snap.Town != nil {
+		found := false
+		for _, rig := range snap.Town.Rigs {
+			if rig.Name == m.selectedRig {
+				found = true
+				break
+			}
+		}
+		if !found {
+			m.selectedRig = ""
+		}
+	}
+
+	// Set default selection if none
+	if m.selectedRig == "" && snap != nil && snap.Town != nil && len(snap.Town.Rigs) > 0 {
+		m.selectedRig = snap.Town.Rigs[0].Name
+	}
+
+	// Update selected agent from sidebar
+	m.updateSelectedFromSidebar()
+
+	if snap != nil && snap.HasErrors() {
+		m.errorCount = len(snap.Errors)
+	} else {
+		m.errorCount = 0
+	}
+}
+
+// minTickInterval keeps ticks from spinning while a due source loads.
+const minTickInterval = time.Second
+
+// tickCmd creates a tick command for when the next source is due to
+// refresh, at most refreshInterval away.
 func (m Model) tickCmd() tea.Cmd {
 	if m.refreshInterval <= 0 {
 		return nil
 	}
-	return tea.Tick(m.refreshInterval, func(t time.Time) tea.Msg {
+	wait := m.refreshInterval
+	if m.store != nil {
+		t := now()
+		if until := m.store.NextDue(t).Sub(t); until < wait {
+			wait = until
+		}
+		if wait < minTickInterval {
+			wait = minTickInterval
+		}
+	}
+	return tea.Tick(wait, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
@@ -306,82 +459,88 @@ func (m Model) actionCmdWithInput(action ActionType, target, input, extraInput s
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
+		runner, rec := m.recordingRunner()
 		var err error
 		switch action {
 		case ActionBootRig:
-			err = m.actionRunner.BootRig(ctx, target)
+			err = runner.BootRig(ctx, target)
 		case ActionShutdownRig:
-			err = m.actionRunner.ShutdownRig(ctx, target)
+			err = runner.ShutdownRig(ctx, target)
 		case ActionDeleteRig:
-			err = m.actionRunner.DeleteRig(ctx, target)
+			err = runner.DeleteRig(ctx, target)
 		case ActionOpenLogs:
-			err = m.actionRunner.OpenLogs(ctx, target)
+			err = runner.OpenLogs(ctx, target)
 		case ActionViewMRLogs:
-			err = m.actionRunner.ViewMRLogs(ctx, target)
+			err = runner.ViewMRLogs(ctx, target)
 		case ActionNudgeRefinery:
-			err = m.actionRunner.NudgeRefinery(ctx, target)
+			err = runner.NudgeRefinery(ctx, target)
 		case ActionRestartRefinery, ActionRestartRefineryAlt:
-			err = m.actionRunner.RestartRefinery(ctx, target)
+			err = runner.RestartRefinery(ctx, target)
 		case ActionStopPolecat:
-			err = m.actionRunner.StopPolecat(ctx, target)
+			err = runner.StopPolecat(ctx, target)
 		case ActionStopAllIdle:
-			err = m.actionRunner.StopAllIdlePolecats(ctx, target)
+			err = runner.StopAllIdlePolecats(ctx, target)
 		case ActionRemoveWorktree:
-			err = m.actionRunner.RemoveWorktree(ctx, target)
+			err = runner.RemoveWorktree(ctx, target)
 		case ActionSlingWork:
-			err = m.actionRunner.SlingWork(ctx, input, target)
+			err = runner.SlingWork(ctx, input, target)
 		case ActionHandoff:
-			err = m.actionRunner.Handoff(ctx, target)
+			err = runner.Handoff(ctx, target)
 		case ActionStopAgent:
-			err = m.actionRunner.StopAgent(ctx, target)
+			err = runner.StopAgent(ctx, target)
 		case ActionNudgeAgent:
-			err = m.actionRunner.NudgeAgent(ctx, target, input)
+			err = runner.NudgeAgent(ctx, target, input)
 		case ActionMailAgent:
-			err = m.actionRunner.MailAgent(ctx, target, input, extraInput)
+			err = runner.MailAgent(ctx, target, input, extraInput)
 		case ActionTogglePlugin:
-			err = m.actionRunner.TogglePlugin(ctx, target)
+			err = runner.TogglePlugin(ctx, target)
 		case ActionOpenSession:
-			err = m.actionRunner.OpenSession(ctx, target)
+			err = runner.OpenSession(ctx, target)
 		case ActionStartSession:
-			err = m.actionRunner.StartSession(ctx, target)
+			err = runner.StartSession(ctx, target)
 		case ActionRestartSession:
-			err = m.actionRunner.RestartSession(ctx, target)
+			err = runner.RestartSession(ctx, target)
 		case ActionPresetNudge:
-			err = m.actionRunner.NudgeAgent(ctx, target, input)
+			err = runner.NudgeAgent(ctx, target, input)
 		case ActionCloseBead:
-			err = m.actionRunner.CloseBead(ctx, target)
+			err = runner.CloseBead(ctx, target)
 		case ActionReopenBead:
-			err = m.actionRunner.ReopenBead(ctx, target)
+			err = runner.ReopenBead(ctx, target)
 		// Infrastructure agent controls
 		case ActionStartDeacon:
-			err = m.actionRunner.StartDeacon(ctx)
+			err = runner.StartDeacon(ctx)
 		case ActionStopDeacon:
-			err = m.actionRunner.StopDeacon(ctx)
+			err = runner.StopDeacon(ctx)
 		case ActionRestartDeacon:
-			err = m.actionRunner.RestartDeacon(ctx)
+			err = runner.RestartDeacon(ctx)
 		case ActionStartWitness:
-			err = m.actionRunner.StartWitness(ctx, target)
+			err = runner.StartWitness(ctx, target)
 		case ActionStopWitness:
-			err = m.actionRunner.StopWitness(ctx, target)
+			err = runner.StopWitness(ctx, target)
 		case ActionRestartWitness:
-			err = m.actionRunner.RestartWitness(ctx, target)
+			err = runner.RestartWitness(ctx, target)
 		case ActionStartRefinery:
-			err = m.actionRunner.StartRefinery(ctx, target)
+			err = runner.StartRefinery(ctx, target)
 		case ActionStopRefinery:
-			err = m.actionRunner.StopRefinery(ctx, target)
+			err = runner.StopRefinery(ctx, target)
 		case ActionMQRetry:
 			// input contains mrID, target contains rig
-			err = m.actionRunner.MQRetry(ctx, input, target)
+			err = runner.MQRetry(ctx, input, target)
 		case ActionMQViewDetails:
 			// input contains mrID, target contains rig
-			err = m.actionRunner.MQViewDetails(ctx, input, target)
+			err = runner.MQViewDetails(ctx, input, target)
 		case ActionMQOpenLogs:
 			// input contains mrID, target is ignored
-			err = m.actionRunner.MQOpenLogs(ctx, input)
+			err = runner.MQOpenLogs(ctx, input)
 		case ActionExportSnapshot:
 			// Export current snapshot to JSON for debugging
-			err = m.actionRunner.ExportSnapshot(ctx, m.snapshot)
+			err = runner.ExportSnapshot(ctx, m.snapshot)
+		case ActionFixDoctorCheck:
+			// target is the check name
+			check, _ := m.doctorReport.Check(target)
+			err = runner.FixDoctorCheck(ctx, check)
 		}
+		m.journalAction(rec, action, target, input, err)
 
 		return actionCompleteMsg{action: action, target: target, err: err}
 	}
@@ -393,7 +552,9 @@ func (m Model) refileCmd(issueID, target string) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		err := m.actionRunner.RefileIssue(ctx, issueID, target)
+		runner, rec := m.recordingRunner()
+		err := runner.RefileIssue(ctx, issueID, target)
+		m.journalAction(rec, ActionRefileIssue, issueID, target, err)
 		return actionCompleteMsg{action: ActionRefileIssue, target: issueID, err: err}
 	}
 }
@@ -423,8 +584,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case refreshMsg:
-		m.isRefreshing = false
+		if !msg.partial {
+			m.isRefreshing = false
+		}
 		m.lastRefresh = now()
+		if m.watcher != nil && msg.snapshot != nil {
+			m.watcher.SetRigs(msg.snapshot.RigNames())
+		}
 
 		if msg.err != nil {
 			m.errorCount++
@@ -432,51 +598,73 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, statusExpireCmd(5 * time.Second)
 		}
 
-		m.snapshot = msg.snapshot
-		m.sidebar.UpdateFromSnapshot(msg.snapshot)
-		m.updateQueueHealth(msg.snapshot)
+		// Notify on live data even while browsing history
+		notifyCmd := m.notifyCmd(msg.snapshot)
 
-		// Validate selected rig still exists, reset if not
-		if m.selectedRig != "" && msg.snapshot != nil && msg.snapshot.Town != nil {
-			found := false
-			for _, rig := range msg.snapshot.Town.Rigs {
-				if rig.Name == m.selectedRig {
-					found = true
-					break
-				}
-			}
-			if !found {
-				m.selectedRig = ""
-			}
+		// While browsing history, keep the live snapshot for when we return
+		if m.timeTravel != nil {
+			m.timeTravel.live = msg.snapshot
+			return m, notifyCmd
 		}
 
-		// Set default selection if none
-		if m.selectedRig == "" && msg.snapshot != nil && msg.snapshot.Town != nil && len(msg.snapshot.Town.Rigs) > 0 {
-			m.selectedRig = msg.snapshot.Town.Rigs[0].Name
-		}
+		m.applySnapshot(msg.snapshot)
+		return m, notifyCmd
 
-		// Update selected agent from sidebar
-		m.updateSelectedFromSidebar()
+	case ReloadConfigMsg:
+		return m, m.reloadConfigCmd()
 
-		if msg.snapshot != nil && msg.snapshot.HasErrors() {
-			m.errorCount = len(msg.snapshot.Errors)
-		} else {
-			m.errorCount = 0
+	case configReloadedMsg:
+		return m.handleConfigReloaded(msg)
+
+	case notifyFailedMsg:
+		m.setStatus("Notification failed: "+msg.err.Error(), true)
+		return m, statusExpireCmd(5 * time.Second)
+
+	case watchStartedMsg:
+		return m.handleWatchStarted(msg)
+
+	case filesChangedMsg:
+		return m.handleFilesChanged(msg)
+
+	case watchStoppedMsg:
+		if msg.watcher == m.watcher {
+			m.watcher = nil
+			m.applySchedule()
 		}
 		return m, nil
 
+	case historyEntriesMsg:
+		return m.handleHistoryEntries(msg)
+
+	case historySnapshotMsg:
+		return m.handleHistorySnapshot(msg)
+
 	case tickMsg:
-		// Auto-refresh on tick, schedule next tick
+		// Auto-refresh the sources that are due, schedule next tick
 		if !m.isRefreshing {
-			m.isRefreshing = true
-			return m, tea.Batch(m.tickCmd(), m.loadData)
+			if due := m.store.Due(now()); len(due) > 0 {
+				m.isRefreshing = true
+				return m, tea.Batch(m.tickCmd(), m.loadSourcesCmd(due))
+			}
 		}
-		// Already refreshing, just schedule next tick
+		// Already refreshing or nothing due, just schedule next tick
 		return m, m.tickCmd()
 
 	case actionCompleteMsg:
 		return m.handleActionComplete(msg)
 
+	case bulkCompleteMsg:
+		return m.handleBulkComplete(msg)
+
+	case townsRefreshedMsg:
+		return m.handleTownsRefreshed(msg)
+
+	case townsTickMsg:
+		if msg.view == m.townsView {
+			return m, m.refreshTownsCmd(msg.view)
+		}
+		return m, nil
+
 	case statusExpiredMsg:
 		m.statusMessage = nil
 
@@ -556,11 +744,10 @@ func (m Model) handleSetupWizard(msg tea.Msg) (tea.Model, tea.Cmd) {
 		townRoot := m.setupWizard.TownRoot()
 		m.setupWizard = nil
 		m.townRoot = townRoot
-		m.store = data.NewStore(townRoot)
-		m.store.RefreshInterval = 5 * time.Second
+		m.store = m.dashboardStore(townRoot, nil)
 		m.sidebar = NewSidebarState()
-		m.actionRunner = NewActionRunner(townRoot)
-		m.refreshInterval = DefaultRefreshInterval
+		m.actionRunner = m.newActionRunner(townRoot)
+		m.applySettings(m.settings)
 		m.firstRun = true
 		m.showHelp = true // Show help on first run
 
@@ -578,6 +765,36 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Any key dismisses the bulk action report and the dry-run preview
+	if m.bulkReport != nil {
+		m.bulkReport = nil
+		return m, nil
+	}
+	if m.dryRunPreview != nil {
+		m.dryRunPreview = nil
+		return m, nil
+	}
+
+	// Handle command palette
+	if m.palette != nil {
+		return m.handlePaletteKey(msg)
+	}
+
+	// Handle global search
+	if m.search != nil {
+		return m.handleSearchKey(msg)
+	}
+
+	// Handle action journal browser
+	if m.journalView != nil {
+		return m.handleJournalKey(msg)
+	}
+
+	// Handle towns overview
+	if m.townsView != nil {
+		return m.handleTownsKey(msg)
+	}
+
 	// Handle input dialog first
 	if m.inputDialog != nil {
 		return m.handleInputKey(msg)
@@ -643,33 +860,100 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleAgentDetailKey(msg)
 	}
 
+	// Handle time travel through snapshot history
+	if m.timeTravel != nil {
+		if model, cmd, handled := m.handleTimeTravelKey(msg); handled {
+			return model, cmd
+		}
+	}
+
 	// Handle town map view navigation
 	if m.showTownMap {
 		return m.handleTownMapKey(msg)
 	}
 
-	switch msg.String() {
-	case "q", "ctrl+c":
+	// Esc unmarks the current section before anything else
+	if msg.String() == "esc" && m.focus == PanelSidebar && len(m.sidebar.Marks[m.sidebar.Section]) > 0 {
+		m.sidebar.ClearMarks(m.sidebar.Section)
+		m.setStatus("Marks cleared", false)
+		return m, statusExpireCmd(2 * time.Second)
+	}
+
+	return m.handleAction(m.keyMap().Lookup(msg.String()))
+}
+
+// handleAction runs a main view action. Keys and the command palette both
+// dispatch through it, so they share the same checks and dialogs.
+func (m Model) handleAction(action KeyAction) (tea.Model, tea.Cmd) {
+	if m.readOnlyBlocks(action) {
+		return m.refuseReadOnly()
+	}
+
+	// Marked items take the action in bulk where it has a bulk form
+	if model, cmd, handled := m.handleBulkAction(action); handled {
+		return model, cmd
+	}
+
+	switch action {
+	case KeyQuit:
 		return m, tea.Quit
 
-	case "?":
+	case KeyHelp:
 		m.showHelp = true
 		return m, nil
 
-	case "A":
+	case KeyPalette:
+		m.openPalette()
+		return m, nil
+
+	case KeySearch:
+		m.search = NewSearchOverlay(buildSearchIndex(m.snapshot))
+		return m, nil
+
+	case KeyJournal:
+		m.openJournal()
+		return m, nil
+
+	case KeyTowns:
+		return m, m.openTowns()
+
+	case KeyDryRun:
+		_, on := m.actionRunner.DryRun()
+		m.actionRunner.SetDryRun(!on)
+		if on {
+			m.setStatus("Dry run off: actions run for real", false)
+		} else {
+			m.setStatus("Dry run on: actions preview their commands without running them", false)
+		}
+		return m, statusExpireCmd(3 * time.Second)
+
+	case KeyHistoryBack:
+		// Browse snapshot history (time travel)
+		return m.startTimeTravel()
+
+	case KeyReloadConfig:
+		// Reread the config file
+		if m.reloadConfig == nil {
+			m.setStatus("No config file to reload", true)
+			return m, statusExpireCmd(3 * time.Second)
+		}
+		m.setStatus("Reloading config...", false)
+		return m, m.reloadConfigCmd()
+
+	case KeyAttachTown:
 		// Show attach town dialog (Shift+A to switch towns)
 		m.attachDialog = NewAttachDialog()
 		return m, nil
 
-	case "tab":
+	case KeyNextPanel:
 		m.focus = (m.focus + 1) % 4
 		return m, nil
 
-	case "shift+tab":
+	case KeyPrevPanel:
 		m.focus = (m.focus + 3) % 4
 		return m, nil
 
-	case "r":
+	case KeyRefresh:
 		// Context-dependent: MR retry (MergeQueue), Manual refresh OR restart subsystem (Operator section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionMergeQueue {
 			// Retry failed merge request
@@ -681,6 +965,14 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.setStatus("Retrying MR "+mr.mr.ID+"...", false)
 			return m, m.actionCmdWithInput(ActionMQRetry, mr.rig, mr.mr.ID, "")
 		}
+		// Retry the selected alert's source now, even while it's paused
+		if m.focus == PanelSidebar && m.sidebar.Section == SectionAlerts && m.store != nil {
+			if alert, ok := m.sidebar.SelectedItem().(alertItem); ok {
+				m.isRefreshing = true
+				m.setStatus("Retrying "+alert.e.SourceLabel()+"...", false)
+				return m, m.retrySourceCmd(alert.e)
+			}
+		}
 		// Restart infrastructure (Operator section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionOperator {
 			// Restart selected infrastructure subsystem
@@ -691,7 +983,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.setStatus("Refreshing data...", false)
 		return m, m.loadData
 
-	case "b":
+	case KeyBoot:
 		// Context-dependent: Beads form (Beads section), start infrastructure (Operator section), start agent (Agents section), or boot rig (Rigs section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionBeads {
 			// Open beads form for create or edit
@@ -746,10 +1038,10 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.setStatus("Booting rig "+m.selectedRig+"...", false)
 		return m, m.actionCmd(ActionBootRig, m.selectedRig)
 
-	case "M":
+	case KeyRefile:
 		// Move/refile issue to different scope (only in Beads section)
 		if m.sidebar.Section != SectionBeads {
-			m.setStatus("Switch to Beads section (press 9) to refile issues", true)
+			m.setStatus("Switch to Beads section to refile issues", true)
 			return m, statusExpireCmd(3 * time.Second)
 		}
 		if m.sidebar.Selection < 0 || m.sidebar.Selection >= len(m.sidebar.Beads) {
@@ -760,7 +1052,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.refileDialog = NewRefileDialog(issue.ID, m.snapshot)
 		return m, nil
 
-	case "s":
+	case KeyShutdown:
 		// Context-dependent: Stop infrastructure (Operator section), toggle beads scope (Beads section), or shutdown rig (Rigs section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionOperator {
 			// Stop selected infrastructure subsystem
@@ -779,7 +1071,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "d":
+	case KeyDelete:
 		// Context-dependent: MR details (MergeQueue), Manage dependencies (Beads section) or Delete rig (Rigs section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionMergeQueue {
 			// View MR details (blockers, conflicts)
@@ -815,15 +1107,15 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "f":
+	case KeyBeadsFilter:
 		// Open beads filter dialog (only when Beads section is active)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionBeads {
 			return m, m.openBeadsFilterDialog()
 		}
-		m.setStatus("Press 1 to switch to Beads section first", true)
+		m.setStatus("Switch to Beads section first", true)
 		return m, statusExpireCmd(2 * time.Second)
 
-	case "o":
+	case KeyLogs:
 		// Context-dependent: Open refinery logs (Merge Queue section) or agent logs (Agents section)
 		if m.sidebar.Section == SectionMergeQueue {
 			// Open logs for the refinery processing MRs
@@ -843,10 +1135,10 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.setStatus("Opening logs for "+m.selectedAgent+"...", false)
 		return m, m.actionCmd(ActionOpenLogs, m.selectedAgent)
 
-	case "v":
+	case KeyBlockers:
 		// View MR blockers/conflicts (Merge Queue section only)
 		if m.sidebar.Section != SectionMergeQueue {
-			m.setStatus("Switch to Merge Queue section (press 3) to view blockers", true)
+			m.setStatus("Switch to Merge Queue section (press "+m.keyMap().Key(KeySectionMergeQueue)+") to view blockers", true)
 			return m, statusExpireCmd(3 * time.Second)
 		}
 		if m.sidebar.Selection < 0 || m.sidebar.Selection >= len(m.sidebar.MRs) {
@@ -875,12 +1167,12 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.setStatus(blockerInfo, false)
 		return m, statusExpireCmd(10 * time.Second)
 
-	case "a":
+	case KeyAddRig:
 		// Open add rig form
 		m.addRigForm = NewAddRigForm()
 		return m, nil
 
-	case "w":
+	case KeyNewWork:
 		// Open create work form
 		var rigs []string
 		if m.snapshot != nil && m.snapshot.Town != nil {
@@ -891,7 +1183,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.createWorkForm = NewCreateWorkForm(rigs)
 		return m, nil
 
-	case "c":
+	case KeyStopIdle:
 		// Context-dependent: Add comment (Beads section) or stop polecat (Agents section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionBeads {
 			// Add comment to selected bead
@@ -905,7 +1197,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		// Stop selected idle polecat (only when Agents section is active)
 		if m.sidebar.Section != SectionAgents {
-			m.setStatus("Switch to Agents section (press 4) to stop polecats", true)
+			m.setStatus("Switch to Agents section (press "+m.keyMap().Key(KeySectionAgents)+") to stop polecats", true)
 			return m, statusExpireCmd(3 * time.Second)
 		}
 		if m.sidebar.Selection < 0 || m.sidebar.Selection >= len(m.sidebar.Agents) {
@@ -935,7 +1227,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "C":
+	case KeyStopAllIdle:
 		// Stop all idle polecats in selected rig
 		if m.selectedRig == "" {
 			m.setStatus("No rig selected. Use j/k to select a rig.", true)
@@ -949,12 +1241,12 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "D":
+	case KeyExport:
 		// Debug: export snapshot to JSON
 		m.setStatus("Exporting snapshot to ~/.perch/last_snapshot.json...", false)
 		return m, m.actionCmd(ActionExportSnapshot, "")
 
-	case "n":
+	case KeyNudge:
 		// Context-sensitive nudge: merge queue or agents section
 		if m.sidebar.Section == SectionMergeQueue {
 			// Nudge polecat to resolve merge issues
@@ -984,7 +1276,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.setStatus("Switch to Merge Queue or Agents section to nudge", true)
 		return m, statusExpireCmd(3 * time.Second)
 
-	case "S":
+	case KeySling:
 		// Sling work to selected agent (opens input dialog)
 		if m.selectedAgent == "" {
 			m.setStatus("No agent selected. Use j/k to select an agent.", true)
@@ -998,7 +1290,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "H":
+	case KeyHandoff:
 		// Context-dependent: Handoff (Agents section) or toggle convoy history (Convoys section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionConvoys {
 			m.sidebar.ToggleConvoyHistory()
@@ -1017,7 +1309,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.setStatus("Handing off work for "+m.selectedAgent+"...", false)
 		return m, m.actionCmd(ActionHandoff, m.selectedAgent)
 
-	case "K":
+	case KeyKill:
 		// Kill/stop selected agent (requires confirmation)
 		if m.selectedAgent == "" {
 			m.setStatus("No agent selected. Use j/k to select an agent.", true)
@@ -1031,7 +1323,12 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "V":
+	case KeyTownMap:
+		// With marks in the current section, extend them to the cursor
+		if m.focus == PanelSidebar && m.sidebar.CanMark() && len(m.sidebar.Marks[m.sidebar.Section]) > 0 {
+			m.sidebar.MarkRange()
+			return m, nil
+		}
 		// Toggle town map view
 		m.showTownMap = !m.showTownMap
 		if m.showTownMap {
@@ -1041,11 +1338,11 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "T":
+	case KeyOpenSession:
 		// Open agent's underlying session (advanced/hidden action for power users)
 		// Only works in Agents section
 		if m.sidebar.Section != SectionAgents {
-			m.setStatus("Switch to Agents section (press 4) to open session", true)
+			m.setStatus("Switch to Agents section (press "+m.keyMap().Key(KeySectionAgents)+") to open session", true)
 			return m, statusExpireCmd(3 * time.Second)
 		}
 		if m.selectedAgent == "" {
@@ -1055,11 +1352,11 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.setStatus("Opening session for "+m.selectedAgent+"...", false)
 		return m, m.actionCmd(ActionOpenSession, m.selectedAgent)
 
-	case "L":
+	case KeyViewOutput:
 		// View recent session output (tmux-optional fallback)
 		// Only works in Agents section
 		if m.sidebar.Section != SectionAgents {
-			m.setStatus("Switch to Agents section (press 4) to view output", true)
+			m.setStatus("Switch to Agents section (press "+m.keyMap().Key(KeySectionAgents)+") to view output", true)
 			return m, statusExpireCmd(3 * time.Second)
 		}
 		if m.selectedAgent == "" {
@@ -1069,7 +1366,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.setStatus("Capturing output from "+m.selectedAgent+"...", false)
 		return m, m.actionCmd(ActionViewSessionOutput, m.selectedAgent)
 
-	case "m":
+	case KeyMail:
 		// Context-dependent: Mail agent (Agents section) or toggle mail read (Mail section)
 		if m.sidebar != nil && m.sidebar.Section == SectionMail {
 			// Toggle read/unread for selected mail (only in Mail section)
@@ -1099,7 +1396,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "t":
+	case KeyAttachSession:
 		// Cycle beads type filter (only in Beads section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionBeads {
 			m.sidebar.CycleBeadsTypeFilter()
@@ -1119,7 +1416,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.setStatus("Attaching to "+m.selectedAgent+"...", false)
 		return m, m.actionCmd(ActionOpenSession, m.selectedAgent)
 
-	case "R":
+	case KeyRestart:
 		// Restart agent's session (requires confirmation)
 		if m.selectedAgent == "" {
 			m.setStatus("No agent selected. Use j/k to select an agent.", true)
@@ -1133,7 +1430,15 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "enter":
+	case KeyMark:
+		if m.focus != PanelSidebar || !m.sidebar.CanMark() {
+			m.setStatus("Only beads, mail, agents, worktrees and merge requests can be marked", true)
+			return m, statusExpireCmd(3 * time.Second)
+		}
+		m.sidebar.ToggleMark()
+		return m, nil
+
+	case KeySelect:
 		// Open agent detail dialog (only in Agents section)
 		if m.sidebar.Section == SectionAgents {
 			if m.selectedAgent == "" {
@@ -1174,6 +1479,8 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				entry.HealthStatus = AgentIdle
 			}
 			m.agentDetailDialog = NewAgentDetailDialog(entry)
+			m.agentDetailDialog.ShowActions = !m.readOnly()
+			m.agentDetailDialog.Keys = m.keyMap()
 
 			// Populate last activity from audit timeline
 			if m.auditTimelineActor == agentItem.a.Address && len(m.auditTimeline) > 0 {
@@ -1204,7 +1511,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	// Sidebar navigation (only when sidebar focused)
-	case "j", "down":
+	case KeyDown:
 		if m.focus == PanelSidebar {
 			m.sidebar.SelectNext()
 			m.syncSelectedRig()
@@ -1214,7 +1521,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "k", "up":
+	case KeyUp:
 		if m.focus == PanelSidebar {
 			m.sidebar.SelectPrev()
 			m.syncSelectedRig()
@@ -1224,7 +1531,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "h", "left":
+	case KeyLeft:
 		if m.focus == PanelSidebar {
 			m.sidebar.PrevSection()
 			m.syncSelectedRig()
@@ -1234,7 +1541,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "l", "right":
+	case KeyRight:
 		// Open MR logs when in MergeQueue section, otherwise navigate to next section
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionMergeQueue {
 			if m.sidebar.Selection < 0 || m.sidebar.Selection >= len(m.sidebar.MRs) {
@@ -1254,14 +1561,14 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "0":
+	case KeySectionIdentity:
 		if m.focus == PanelSidebar {
 			m.sidebar.Section = SectionIdentity
 			m.sidebar.Selection = 0
 		}
 		return m, nil
 
-	case "1":
+	case KeySectionRigs:
 		if m.focus == PanelSidebar {
 			m.sidebar.Section = SectionRigs
 			m.sidebar.Selection = 0
@@ -1271,7 +1578,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "2":
+	case KeySectionConvoys:
 		if m.focus == PanelSidebar {
 			m.sidebar.Section = SectionConvoys
 			m.sidebar.Selection = 0
@@ -1279,7 +1586,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "3":
+	case KeySectionMergeQueue:
 		if m.focus == PanelSidebar {
 			m.sidebar.Section = SectionMergeQueue
 			m.sidebar.Selection = 0
@@ -1287,7 +1594,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "4":
+	case KeySectionAgents:
 		if m.focus == PanelSidebar {
 			m.sidebar.Section = SectionAgents
 			m.sidebar.Selection = 0
@@ -1297,17 +1604,17 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "5":
+	case KeySectionMail:
 		if m.focus == PanelSidebar {
 			m.sidebar.Section = SectionMail
 			m.sidebar.Selection = 0
 		}
 		return m, nil
 
-	case "y":
+	case KeyAckMail:
 		// Acknowledge selected mail (only in Mail section)
 		if m.sidebar.Section != SectionMail {
-			m.setStatus("Switch to Mail section (press 5) to ack mail", true)
+			m.setStatus("Switch to Mail section (press "+m.keyMap().Key(KeySectionMail)+") to ack mail", true)
 			return m, statusExpireCmd(3 * time.Second)
 		}
 		if m.sidebar.Selection < 0 || m.sidebar.Selection >= len(m.sidebar.Mail) {
@@ -1318,7 +1625,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.setStatus("Acknowledging mail...", false)
 		return m, m.mailActionCmd(ActionAckMail, mail.ID)
 
-	case "G":
+	case KeyMailRigFilter:
 		// Cycle rig filter (only in Mail section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionMail {
 			m.cycleMailRigFilter()
@@ -1332,7 +1639,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "O":
+	case KeyMailRoleFilter:
 		// Cycle role filter (only in Mail section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionMail {
 			m.cycleMailRoleFilter()
@@ -1346,7 +1653,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "u":
+	case KeyUnreadFilter:
 		// Toggle unread filter (only in Mail section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionMail {
 			m.sidebar.MailUnreadOnly = !m.sidebar.MailUnreadOnly
@@ -1360,10 +1667,10 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "B":
+	case KeyMarkAllRead:
 		// Mark all visible mail as read (bulk action, only in Mail section)
 		if m.sidebar.Section != SectionMail {
-			m.setStatus("Switch to Mail section (press 5) for bulk actions", true)
+			m.setStatus("Switch to Mail section (press "+m.keyMap().Key(KeySectionMail)+") for bulk actions", true)
 			return m, statusExpireCmd(3 * time.Second)
 		}
 		if len(m.sidebar.Mail) == 0 {
@@ -1378,10 +1685,10 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "X":
+	case KeyArchiveAll:
 		// Archive all visible mail (bulk action, only in Mail section)
 		if m.sidebar.Section != SectionMail {
-			m.setStatus("Switch to Mail section (press 5) for bulk actions", true)
+			m.setStatus("Switch to Mail section (press "+m.keyMap().Key(KeySectionMail)+") for bulk actions", true)
 			return m, statusExpireCmd(3 * time.Second)
 		}
 		if len(m.sidebar.Mail) == 0 {
@@ -1396,21 +1703,21 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "6":
+	case KeySectionLifecycle:
 		if m.focus == PanelSidebar {
 			m.sidebar.Section = SectionLifecycle
 			m.sidebar.Selection = 0
 		}
 		return m, nil
 
-	case "7":
+	case KeySectionWorktrees:
 		if m.focus == PanelSidebar {
 			m.sidebar.Section = SectionWorktrees
 			m.sidebar.Selection = 0
 		}
 		return m, nil
 
-	case "8":
+	case KeySectionPlugins:
 		if m.focus == PanelSidebar {
 			m.sidebar.Section = SectionPlugins
 			m.sidebar.Selection = 0
@@ -1418,14 +1725,14 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "9":
+	case KeySectionAlerts:
 		if m.focus == PanelSidebar {
 			m.sidebar.Section = SectionAlerts
 			m.sidebar.Selection = 0
 		}
 		return m, nil
 
-	case "-":
+	case KeySectionOperator:
 		// Operator console (subsystem health)
 		if m.focus == PanelSidebar {
 			m.sidebar.Section = SectionOperator
@@ -1433,7 +1740,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "e":
+	case KeyEdit:
 		// Edit rig settings (only when in Rigs section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionRigs {
 			if m.selectedRig == "" {
@@ -1466,7 +1773,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "g":
+	case KeyAssigneeFilter:
 		// Set agent filter to current selection's agent (only in Lifecycle section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionLifecycle {
 			m.setLifecycleAgentFilter()
@@ -1487,7 +1794,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "x":
+	case KeyClear:
 		// Clear lifecycle filters (only in Lifecycle section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionLifecycle {
 			m.sidebar.LifecycleFilter = ""
@@ -1523,7 +1830,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "p":
+	case KeyPriorityFilter:
 		// Cycle beads priority filter (only in Beads section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionBeads {
 			m.sidebar.CycleBeadsPriorityFilter()
@@ -1537,7 +1844,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, statusExpireCmd(2 * time.Second)
 		}
 		return m, nil
-	case "z":
+	case KeyCloseBead:
 		// Close selected bead (only in Beads section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionBeads {
 			if m.sidebar.Selection < 0 || m.sidebar.Selection >= len(m.sidebar.Beads) {
@@ -1559,7 +1866,14 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "Z":
+	case KeyFix:
+		// Run the selected doctor check's fix (only in Operator section)
+		if m.focus == PanelSidebar && m.sidebar.Section == SectionOperator {
+			return m.handleDoctorFix()
+		}
+		return m, nil
+
+	case KeyReopenBead:
 		// Reopen selected bead (only in Beads section)
 		if m.focus == PanelSidebar && m.sidebar.Section == SectionBeads {
 			if m.sidebar.Selection < 0 || m.sidebar.Selection >= len(m.sidebar.Beads) {
@@ -1693,6 +2007,19 @@ func (m Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		dialog := m.confirmDialog
 		m.confirmDialog = nil
 
+		if m.pendingUndo != nil {
+			e := *m.pendingUndo
+			m.pendingUndo = nil
+			m.setStatus("Undoing "+strings.ToLower(e.Action)+" "+e.Target+"...", false)
+			return m, m.undoCmd(e)
+		}
+		if m.bulk != nil {
+			op := *m.bulk
+			m.bulk = nil
+			m.setStatus(fmt.Sprintf("%s on %d %s...", actionName(op.action), len(op.items), op.noun), false)
+			return m, m.bulkCmd(op)
+		}
+
 		// Handle town-level bead action confirmations with pending data
 		switch dialog.Action {
 		case ActionCreateBead:
@@ -1750,6 +2077,8 @@ func (m Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "n", "N", "esc":
 		m.confirmDialog = nil
+		m.bulk = nil
+		m.pendingUndo = nil
 		// Clear pending form data on cancel
 		if m.beadsForm != nil && (m.beadsForm.pendingTitle != "" || m.beadsForm.pendingID != "") {
 			m.beadsForm = nil
@@ -1767,7 +2096,7 @@ func (m Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) handlePresetNudgeMenuKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "j", "down":
-		if m.presetNudgeMenu.Selection < len(PresetNudges)-1 {
+		if m.presetNudgeMenu.Selection < len(m.presetNudges())-1 {
 			m.presetNudgeMenu.Selection++
 		}
 		return m, nil
@@ -1779,7 +2108,7 @@ func (m Model) handlePresetNudgeMenuKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "enter":
-		selected := PresetNudges[m.presetNudgeMenu.Selection]
+		selected := m.presetNudges()[m.presetNudgeMenu.Selection]
 		target := m.presetNudgeMenu.Target
 
 		// If "Custom..." selected, open custom input dialog
@@ -1830,11 +2159,17 @@ func (m Model) handleRefileDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		selected := m.refileDialog.Targets[m.refileDialog.Selection]
 		issueID := m.refileDialog.IssueID
 		m.refileDialog = nil
+		if m.bulk != nil {
+			m.bulk.input = selected.Target
+			m.confirmBulk()
+			return m, nil
+		}
 		m.setStatus("Refile "+issueID+" to "+selected.Target, false)
 		return m, m.refileCmd(issueID, selected.Target)
 
 	case "esc", "q":
 		m.refileDialog = nil
+		m.bulk = nil
 		m.setStatus("Refile cancelled", false)
 		return m, statusExpireCmd(2 * time.Second)
 	}
@@ -1869,7 +2204,9 @@ func (m Model) addRigCmd(name, url, prefix string) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
 		defer cancel()
 
-		err := m.actionRunner.AddRig(ctx, name, url, prefix)
+		runner, rec := m.recordingRunner()
+		err := runner.AddRig(ctx, name, url, prefix)
+		m.journalAction(rec, ActionAddRig, name, url, err)
 		return actionCompleteMsg{action: ActionAddRig, target: name, err: err}
 	}
 }
@@ -2021,7 +2358,7 @@ func (m Model) openRigSettingsCmd(rigName string) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		loader := data.NewLoader(m.townRoot)
+		loader := m.newLoader(m.townRoot)
 		settings, err := loader.LoadRigSettings(ctx, rigName)
 		return rigSettingsLoadedMsg{rigName: rigName, settings: settings, err: err}
 	}
@@ -2033,7 +2370,10 @@ func (m Model) saveRigSettingsCmd(settings *data.RigSettings) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		loader := data.NewLoader(m.townRoot)
+		if err := m.actionRunner.CheckWritable(); err != nil {
+			return rigSettingsSavedMsg{rigName: settings.Name, err: err}
+		}
+		loader := m.newLoader(m.townRoot)
 		err := loader.SaveRigSettings(ctx, settings)
 		return rigSettingsSavedMsg{rigName: settings.Name, err: err}
 	}
@@ -2085,7 +2425,9 @@ func (m Model) createWorkCmd(title, description, issueType string, priority int,
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		err := m.actionRunner.CreateWork(ctx, title, description, issueType, priority, rig, target, skipSling)
+		runner, rec := m.recordingRunner()
+		err := runner.CreateWork(ctx, title, description, issueType, priority, rig, target, skipSling)
+		m.journalAction(rec, ActionCreateWork, title, target, err)
 		return actionCompleteMsg{action: ActionCreateWork, target: title, err: err}
 	}
 }
@@ -2096,7 +2438,9 @@ func (m Model) createBeadCmd(title, description, issueType string, priority int)
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		err := m.actionRunner.CreateBead(ctx, title, description, issueType, priority)
+		runner, rec := m.recordingRunner()
+		err := runner.CreateBead(ctx, title, description, issueType, priority)
+		m.journalAction(rec, ActionCreateBead, title, "", err)
 		return actionCompleteMsg{action: ActionCreateBead, target: title, err: err}
 	}
 }
@@ -2107,7 +2451,9 @@ func (m Model) updateBeadCmd(id, title, description, issueType string, priority
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		err := m.actionRunner.UpdateBead(ctx, id, title, description, issueType, priority)
+		runner, rec := m.recordingRunner()
+		err := runner.UpdateBead(ctx, id, title, description, issueType, priority)
+		m.journalAction(rec, ActionEditBead, id, "", err)
 		return actionCompleteMsg{action: ActionEditBead, target: id, err: err}
 	}
 }
@@ -2118,7 +2464,9 @@ func (m Model) addCommentCmd(issueID, content string) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		err := m.actionRunner.AddComment(ctx, issueID, content)
+		runner, rec := m.recordingRunner()
+		err := runner.AddComment(ctx, issueID, content)
+		m.journalAction(rec, ActionAddComment, issueID, content, err)
 		return actionCompleteMsg{action: ActionAddComment, target: issueID, err: err}
 	}
 }
@@ -2129,7 +2477,9 @@ func (m Model) closeBeadCmd(id string) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		err := m.actionRunner.CloseBead(ctx, id)
+		runner, rec := m.recordingRunner()
+		err := runner.CloseBead(ctx, id)
+		m.journalAction(rec, ActionCloseBead, id, "", err)
 		return actionCompleteMsg{action: ActionCloseBead, target: id, err: err}
 	}
 }
@@ -2140,7 +2490,9 @@ func (m Model) reopenBeadCmd(id string) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		err := m.actionRunner.ReopenBead(ctx, id)
+		runner, rec := m.recordingRunner()
+		err := runner.ReopenBead(ctx, id)
+		m.journalAction(rec, ActionReopenBead, id, "", err)
 		return actionCompleteMsg{action: ActionReopenBead, target: id, err: err}
 	}
 }
@@ -2151,7 +2503,9 @@ func (m Model) nudgeCmd(rig, worker, branch string, hasConflicts bool) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		err := m.actionRunner.NudgePolecat(ctx, rig, worker, branch, hasConflicts)
+		runner, rec := m.recordingRunner()
+		err := runner.NudgePolecat(ctx, rig, worker, branch, hasConflicts)
+		m.journalAction(rec, ActionNudgePolecat, worker, "", err)
 		return actionCompleteMsg{action: ActionNudgePolecat, target: worker, err: err}
 	}
 }
@@ -2162,15 +2516,17 @@ func (m Model) mailActionCmd(action ActionType, mailID string) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
+		runner, rec := m.recordingRunner()
 		var err error
 		switch action {
 		case ActionMarkMailRead:
-			err = m.actionRunner.MarkMailRead(ctx, mailID)
+			err = runner.MarkMailRead(ctx, mailID)
 		case ActionMarkMailUnread:
-			err = m.actionRunner.MarkMailUnread(ctx, mailID)
+			err = runner.MarkMailUnread(ctx, mailID)
 		case ActionAckMail:
-			err = m.actionRunner.AckMail(ctx, mailID)
+			err = runner.AckMail(ctx, mailID)
 		}
+		m.journalAction(rec, action, mailID, "", err)
 
 		return actionCompleteMsg{action: action, target: mailID, err: err}
 	}
@@ -2182,13 +2538,15 @@ func (m Model) bulkMailActionCmd(action ActionType, rig, role string, unreadOnly
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
+		runner, rec := m.recordingRunner()
 		var err error
 		switch action {
 		case ActionBulkMailRead:
-			err = m.actionRunner.BulkMailRead(ctx, rig, role, unreadOnly)
+			err = runner.BulkMailRead(ctx, rig, role, unreadOnly)
 		case ActionBulkMailArchive:
-			err = m.actionRunner.BulkMailArchive(ctx, rig, role, unreadOnly)
+			err = runner.BulkMailArchive(ctx, rig, role, unreadOnly)
 		}
+		m.journalAction(rec, action, rig, "", err)
 
 		return actionCompleteMsg{action: action, target: "", err: err}
 	}
@@ -2207,14 +2565,21 @@ func (m Model) handleInputKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Execute the action
 		m.inputDialog = nil
 		if dialog.Input == "" {
+			m.bulk = nil
 			m.setStatus("Input cancelled (empty)", false)
 			return m, statusExpireCmd(2 * time.Second)
 		}
+		if m.bulk != nil {
+			m.bulk.input = dialog.Input
+			m.confirmBulk()
+			return m, nil
+		}
 		m.setStatus("Executing "+actionName(dialog.Action)+"...", false)
 		return m, m.actionCmdWithInput(dialog.Action, dialog.Target, dialog.Input, dialog.ExtraInput)
 
 	case "esc":
 		m.inputDialog = nil
+		m.bulk = nil
 		m.setStatus("Input cancelled", false)
 		return m, statusExpireCmd(2 * time.Second)
 
@@ -2355,7 +2720,7 @@ func (m Model) loadDependenciesForRemoval() tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		loader := &data.Loader{TownRoot: m.townRoot, Runner: &actionRunner{}}
+		loader := m.newLoader(m.townRoot)
 		deps, _, err := loader.LoadDependencies(ctx, m.depDialog.IssueID)
 		if err != nil {
 			m.depDialog.Status = "Error loading dependencies: " + err.Error()
@@ -2373,7 +2738,7 @@ func (m Model) performDependencySearch() tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		loader := &data.Loader{TownRoot: m.townRoot, Runner: &actionRunner{}}
+		loader := m.newLoader(m.townRoot)
 		results, err := loader.SearchIssues(ctx, m.depDialog.SearchQuery, 10)
 		if err != nil {
 			m.depDialog.Status = "Search error: " + err.Error()
@@ -2581,7 +2946,7 @@ func (m Model) openDependencyDialog(issueID, issueTitle string) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		loader := &data.Loader{TownRoot: m.townRoot, Runner: &actionRunner{}}
+		loader := m.newLoader(m.townRoot)
 		deps, _, err := loader.LoadDependencies(ctx, issueID)
 		if err != nil {
 			m.setStatus("Failed to load dependencies: "+err.Error(), true)
@@ -2605,7 +2970,9 @@ func (m Model) addDependencyCmd(issueID, dependsOnID string) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		err := m.actionRunner.AddDependency(ctx, issueID, dependsOnID)
+		runner, rec := m.recordingRunner()
+		err := runner.AddDependency(ctx, issueID, dependsOnID)
+		m.journalAction(rec, ActionAddDependency, issueID, dependsOnID, err)
 		if err != nil {
 			return actionCompleteMsg{
 				action: ActionAddDependency,
@@ -2624,7 +2991,9 @@ func (m Model) removeDependencyCmd(issueID, dependsOnID string) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		err := m.actionRunner.RemoveDependency(ctx, issueID, dependsOnID)
+		runner, rec := m.recordingRunner()
+		err := runner.RemoveDependency(ctx, issueID, dependsOnID)
+		m.journalAction(rec, ActionRemoveDependency, issueID, dependsOnID, err)
 		if err != nil {
 			return actionCompleteMsg{
 				action: ActionRemoveDependency,
@@ -2644,17 +3013,9 @@ func (m Model) handleAttachKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.attachDialog.IsValid() {
 			newPath := m.attachDialog.ExpandedPath()
 			m.attachDialog = nil
-			// Reinitialize with new town root
-			m.townRoot = newPath
-			m.store = data.NewStore(newPath)
-			m.store.RefreshInterval = 5 * time.Second
-			m.actionRunner = NewActionRunner(newPath)
-			m.snapshot = nil
-			m.sidebar = NewSidebarState()
-			m.selectedRig = ""
-			m.selectedAgent = ""
+			load := m.switchTown(newPath, nil)
 			m.setStatus("Attached to town: "+newPath, false)
-			return m, tea.Batch(m.loadData, statusExpireCmd(3*time.Second))
+			return m, tea.Batch(load, statusExpireCmd(3*time.Second))
 		}
 		// Invalid - just show error (already visible)
 		return m, nil
@@ -2687,6 +3048,15 @@ func (m Model) handleAttachKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) handleAgentDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	dialog := m.agentDetailDialog
 
+	// Without the action menu the dialog only closes
+	if !dialog.ShowActions {
+		switch msg.String() {
+		case "esc", "q":
+			m.agentDetailDialog = nil
+		}
+		return m, nil
+	}
+
 	switch msg.String() {
 	case "esc", "q":
 		// Close dialog
@@ -2757,8 +3127,10 @@ func (m Model) handleAgentDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		default:
 			return m, m.actionCmd(actionType, target)
 		}
+	}
 
-	case "n":
+	switch m.keyMap().LookupDialog(groupDetail, msg.String()) {
+	case KeyDetailNudge:
 		// Quick nudge - show preset nudge menu
 		target := dialog.Agent.Address
 		m.agentDetailDialog = nil
@@ -2768,13 +3140,13 @@ func (m Model) handleAgentDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "a":
+	case KeyDetailAttach:
 		// Quick attach
 		actionType, target := dialog.ExecuteAction(1) // Index 1 = attach
 		m.agentDetailDialog = nil
 		return m, m.actionCmd(actionType, target)
 
-	case "m":
+	case KeyDetailMail:
 		// Quick mail
 		actionType, target := dialog.ExecuteAction(2) // Index 2 = mail
 		m.agentDetailDialog = nil
@@ -2789,7 +3161,7 @@ func (m Model) handleAgentDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "h":
+	case KeyDetailHandoff:
 		// Quick handoff (only if running)
 		if dialog.Agent.Running {
 			actionType, target := dialog.ExecuteAction(3) // Index 3 = handoff
@@ -2804,7 +3176,7 @@ func (m Model) handleAgentDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "s":
+	case KeyDetailStop:
 		// Quick stop (only if running)
 		if dialog.Agent.Running {
 			actionType, target := dialog.ExecuteAction(4) // Index 4 = stop
@@ -2819,7 +3191,7 @@ func (m Model) handleAgentDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "t":
+	case KeyDetailStart:
 		// Quick start (only if not running)
 		if !dialog.Agent.Running {
 			actionType, target := dialog.ExecuteAction(3) // Index 3 = start
@@ -2839,15 +3211,16 @@ func (m Model) handleTownMapKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.townMapView = NewTownMapView(m.snapshot, m.width, m.height-2)
 	}
 
-	switch msg.String() {
-	case "esc":
+	if msg.String() == "esc" {
 		// Exit town map view
 		m.showTownMap = false
 		m.townMapView = nil
 		m.setStatus("Back to main view", false)
 		return m, nil
+	}
 
-	case "enter":
+	switch m.keyMap().Lookup(msg.String()) {
+	case KeySelect:
 		// Select rig and exit town map view
 		if m.townMapView != nil {
 			rigName := m.townMapView.SelectedRig()
@@ -2865,34 +3238,34 @@ func (m Model) handleTownMapKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "j", "down":
+	case KeyDown:
 		if m.townMapView != nil {
 			m.townMapView.MoveSelection("down")
 		}
 		return m, nil
 
-	case "k", "up":
+	case KeyUp:
 		if m.townMapView != nil {
 			m.townMapView.MoveSelection("up")
 		}
 		return m, nil
 
-	case "h", "left":
+	case KeyLeft:
 		if m.townMapView != nil {
 			m.townMapView.MoveSelection("left")
 		}
 		return m, nil
 
-	case "l", "right":
+	case KeyRight:
 		if m.townMapView != nil {
 			m.townMapView.MoveSelection("right")
 		}
 		return m, nil
 
-	case "q", "ctrl+c":
+	case KeyQuit:
 		return m, tea.Quit
 
-	case "?":
+	case KeyHelp:
 		m.showHelp = true
 		return m, nil
 
@@ -2904,6 +3277,16 @@ func (m Model) handleTownMapKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleActionComplete processes the result of an action.
 func (m Model) handleActionComplete(msg actionCompleteMsg) (tea.Model, tea.Cmd) {
+	// A dry run changed nothing, so preview the commands instead of refreshing
+	if _, ok := m.actionRunner.DryRun(); ok {
+		m.takeDryRunPreview(strings.TrimSpace(actionName(msg.action) + " " + msg.target))
+		if msg.err == nil {
+			m.setStatus("Dry run: "+actionName(msg.action)+" previewed, nothing executed", false)
+			return m, statusExpireCmd(3 * time.Second)
+		}
+		m.dryRunPreview = nil
+	}
+
 	if msg.err != nil {
 		errMsg := msg.err.Error()
 
@@ -3239,6 +3622,34 @@ func (m Model) handleInfrastructureRestart() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleDoctorFix asks to run the selected doctor check's suggested fix.
+func (m Model) handleDoctorFix() (tea.Model, tea.Cmd) {
+	if m.sidebar.Selection < 0 || m.sidebar.Selection >= len(m.sidebar.Operator) {
+		m.setStatus("No subsystem selected", true)
+		return m, statusExpireCmd(3 * time.Second)
+	}
+
+	subsystem := m.sidebar.Operator[m.sidebar.Selection].h
+	name, ok := strings.CutPrefix(subsystem.Subsystem, "doctor_")
+	if !ok {
+		m.setStatus("Only doctor checks have fixes", true)
+		return m, statusExpireCmd(3 * time.Second)
+	}
+	check, ok := m.doctorReport.Check(name)
+	if !ok || len(check.FixCommand) == 0 {
+		m.setStatus("No fix command for "+name+"; see the recommended action", true)
+		return m, statusExpireCmd(3 * time.Second)
+	}
+
+	m.confirmDialog = &ConfirmDialog{
+		Title:   "Confirm Fix",
+		Message: "Run '" + strings.Join(check.FixCommand, " ") + "' to fix " + name + "?",
+		Action:  ActionFixDoctorCheck,
+		Target:  name,
+	}
+	return m, nil
+}
+
 // actionName returns a human-readable name for an action type.
 func actionName(action ActionType) string {
 	switch action {
@@ -3300,6 +3711,8 @@ func actionName(action ActionType) string {
 		return "Attach session"
 	case ActionStartSession:
 		return "Start session"
+	case ActionViewSessionOutput:
+		return "View output"
 	case ActionRestartSession:
 		return "Restart session"
 	case ActionPresetNudge:
@@ -3339,6 +3752,8 @@ func actionName(action ActionType) string {
 		return "MR details"
 	case ActionMQOpenLogs:
 		return "MR logs"
+	case ActionFixDoctorCheck:
+		return "Fix check"
 	default:
 		return "Action"
 	}
@@ -3355,8 +3770,9 @@ func (m *Model) updateQueueHealth(snap *data.Snapshot) {
 
 	for rigName, mrs := range snap.MergeQueues {
 		health := QueueHealth{
-			RigName: rigName,
-			State:   RefineryIdle,
+			RigName:    rigName,
+			State:      RefineryIdle,
+			StaleSince: snap.Stale[data.SourceMergeQueue+"_"+rigName],
 		}
 
 		// Add patrol formulas warning if missing
@@ -3378,10 +3794,10 @@ func (m *Model) updateQueueHealth(snap *data.Snapshot) {
 					for _, agent := range rig.Agents {
 						if agent.Role == "refinery" {
 							health.RefineryAgent = agent.Address
-							if agent.Running && agent.HasWork {
-								health.State = RefineryProcessing
-							} else if !agent.Running {
+							if data.RefineryStalled(agent, mrs, now(), m.staleMRThreshold()) {
 								health.State = RefineryStalled
+							} else if agent.Running && agent.HasWork {
+								health.State = RefineryProcessing
 							}
 						}
 					}
@@ -3401,6 +3817,7 @@ func (m *Model) updateQueueHealth(snap *data.Snapshot) {
 				HasConflicts: mr.HasConflicts,
 				NeedsRebase:  mr.NeedsRebase,
 				ConflictInfo: mr.ConflictInfo,
+				CreatedAt:    mr.CreatedAt,
 			}
 			// Set IsClaimed if worker is assigned
 			if mr.Worker != "" {
@@ -3412,11 +3829,6 @@ func (m *Model) updateQueueHealth(snap *data.Snapshot) {
 			}
 			// Age is calculated from current time if not set
 			health.MRs = append(health.MRs, qmr)
-
-			// Check if any MR is stale (indicates potential stall)
-			if qmr.Age > 1*time.Hour && health.State == RefineryIdle {
-				health.State = RefineryStalled
-			}
 		}
 
 		m.queueHealthData[rigName] = health
@@ -3462,6 +3874,14 @@ func (m Model) View() string {
 		return m.renderHelpOverlay()
 	}
 
+	if m.bulkReport != nil {
+		return m.renderBulkReport()
+	}
+
+	if m.dryRunPreview != nil {
+		return m.renderDryRunPreview()
+	}
+
 	if m.addRigForm != nil {
 		return m.addRigForm.View(m.width, m.height)
 	}
@@ -3486,6 +3906,22 @@ func (m Model) View() string {
 		return m.attachDialog.Render(m.width, m.height)
 	}
 
+	if m.palette != nil {
+		return m.renderPalette()
+	}
+
+	if m.search != nil {
+		return m.renderSearch()
+	}
+
+	if m.journalView != nil {
+		return m.renderJournal()
+	}
+
+	if m.townsView != nil {
+		return m.renderTowns()
+	}
+
 	if m.presetNudgeMenu != nil {
 		return m.renderPresetNudgeMenu()
 	}
@@ -3562,12 +3998,12 @@ func (m Model) renderLayout() string {
 
 	// Only pass dependencies when viewing a bead (SectionBeads)
 	var deps *data.IssueDependencies
-	if m.sidebar != nil && m.sidebar.Section == SectionBeads && m.selectedBeadID == m.beadDependencies.IssueID {
+	if m.sidebar != nil && m.sidebar.Section == SectionBeads && m.beadDependencies != nil && m.selectedBeadID == m.beadDependencies.IssueID {
 		deps = m.beadDependencies
 	}
 	// Only pass comments when viewing a bead (SectionBeads)
 	var comments *data.IssueComments
-	if m.sidebar != nil && m.sidebar.Section == SectionBeads && m.selectedBeadID == m.beadComments.IssueID {
+	if m.sidebar != nil && m.sidebar.Section == SectionBeads && m.beadComments != nil && m.selectedBeadID == m.beadComments.IssueID {
 		comments = m.beadComments
 	}
 	details := RenderDetails(m.sidebar, m.snapshot, auditState, detailsWidth, bodyHeight, m.focus == PanelDetails, deps, comments)
@@ -3599,7 +4035,16 @@ func (m Model) renderTownMap() string {
 	}
 
 	// Render title bar
-	title := titleStyle.Render("Town Map")
+	titleText := "Town Map"
+	if label := m.timeTravelLabel(); label != "" {
+		titleText += "  " + label
+	}
+	title := titleStyle.Render(titleText)
+	if m.snapshot != nil {
+		if since, ok := staleSince(m.snapshot.Stale, data.SourceTownStatus, data.SourceMergeQueue); ok {
+			title += "  " + renderStaleBadge(since)
+		}
+	}
 	if m.townMapView != nil {
 		return lipgloss.JoinVertical(lipgloss.Left, title, m.townMapView.Render())
 	}
@@ -3675,6 +4120,9 @@ func (m Model) buildOverviewContent() string {
 		refreshStr := m.lastRefresh.Format("15:04:05")
 		headerLine += "  " + mutedStyle.Render("updated "+refreshStr)
 	}
+	if since, ok := staleSince(m.snapshot.Stale, data.AllSources...); ok {
+		headerLine += "  " + renderStaleBadge(since)
+	}
 	lines = append(lines, headerLine)
 
 	// Operational state banner - always show health status
@@ -3961,7 +4409,7 @@ func (m Model) buildAlerts() []string {
 		seen := make(map[string]bool)
 		for _, err := range m.snapshot.LoadErrors {
 			if !seen[err.Source] {
-				lastSuccess := m.snapshot.LastSuccess[err.Source]
+				lastSuccess, _ := m.snapshot.LastGood(err.Source)
 				sources = append(sources, sourceInfo{
 					label:       err.SourceLabel(),
 					lastSuccess: lastSuccess,
@@ -4035,48 +4483,7 @@ func (m Model) renderFooter() string {
 	} else if m.confirmDialog != nil {
 		rightSide = confirmStyle.Render(m.confirmDialog.Message)
 	} else {
-		// Context-aware help
-		var helpItems []string
-		switch m.focus {
-		case PanelSidebar:
-			helpItems = append(helpItems, "j/k: select", "h/l: section", "0-9: jump")
-			if m.sidebar.Section == SectionRigs {
-				helpItems = append(helpItems, "e: edit settings")
-			}
-			if m.sidebar.Section == SectionMergeQueue {
-				helpItems = append(helpItems, "n: nudge")
-			}
-			if m.sidebar.Section == SectionConvoys {
-				helpItems = append(helpItems, "H: history")
-			}
-			if m.sidebar.Section == SectionAgents {
-				helpItems = append(helpItems, "b: start", "c: stop idle", "C: stop all idle")
-			}
-			if m.sidebar.Section == SectionLifecycle {
-				helpItems = append(helpItems, "e: type filter", "g: agent filter", "x: clear")
-			}
-			if m.sidebar.Section == SectionMail {
-				helpItems = append(helpItems, "m: read/unread", "y: ack")
-			}
-			if m.sidebar.Section == SectionWorktrees {
-				helpItems = append(helpItems, "x: remove")
-			}
-			if m.sidebar.Section == SectionErrors {
-				helpItems = append(helpItems, "r: retry")
-			}
-			if m.sidebar.Section == SectionOperator {
-				helpItems = append(helpItems, "b: start", "s: stop", "r: restart")
-			}
-		}
-		helpItems = append(helpItems, "w: new work", "a: add rig", "A: attach", "r: refresh", "b: boot", "s: stop", "d: delete", "o: logs")
-		if m.sidebar != nil && m.sidebar.Section == SectionAgents {
-			helpItems = append(helpItems, "n: nudge", "t: attach", "R: restart", "K: kill", "m: mail", "S: sling", "H: handoff")
-		}
-		if m.sidebar != nil && m.sidebar.Section == SectionPlugins {
-			helpItems = append(helpItems, "e: toggle")
-		}
-		helpItems = append(helpItems, "?: help", "q: quit")
-		rightSide = mutedStyle.Render(strings.Join(helpItems, " | "))
+		rightSide = mutedStyle.Render(strings.Join(m.footerHints(), " | "))
 	}
 
 	// If there's no status/confirm input, show time-based HUD on left only in non-test runs.
@@ -4127,6 +4534,18 @@ func (m Model) renderHUD() string {
 		parts = append(parts, mutedStyle.Render(timeStr))
 	}
 
+	if _, ok := m.actionRunner.DryRun(); ok {
+		parts = append(parts, hudDryRunStyle.Render("DRY RUN"))
+	}
+	if m.readOnly() {
+		parts = append(parts, hudReadOnlyStyle.Render("READ-ONLY"))
+	}
+
+	// History position when time traveling
+	if label := m.timeTravelLabel(); label != "" {
+		parts = append(parts, hudHistoryStyle.Render(label))
+	}
+
 	// Error count
 	if m.errorCount > 0 {
 		errStr := fmt.Sprintf("%d err", m.errorCount)
@@ -4171,7 +4590,7 @@ func (m Model) renderPresetNudgeMenu() string {
 	b.WriteString("\n\n")
 
 	// Options
-	for i, preset := range PresetNudges {
+	for i, preset := range m.presetNudges() {
 		var line string
 		if i == m.presetNudgeMenu.Selection {
 			line = selectedItemStyle.Render("> " + preset.Label)
@@ -4633,6 +5052,7 @@ func (m Model) renderHelpOverlay() string {
 		welcomeMsg = helpSectionStyle.Render("Welcome! Here's how Gas Town works:\n")
 	}
 
+	keys := m.keyMap()
 	concepts := []string{
 		helpHeaderStyle.Render("Rigs & Agents"),
 		"",
@@ -4662,9 +5082,9 @@ func (m Model) renderHelpOverlay() string {
 		helpKeyStyle.Render("◌=stopped") + "  Agent session not running",
 		"",
 		helpKeyStyle.Render("Convoys") + "    Groups of related work items",
-		"            Press H to toggle active/history view",
+		"            Press " + keys.Key(KeyHandoff) + " to toggle active/history view",
 		helpKeyStyle.Render("Worktrees") + "  Cross-rig git worktrees",
-		"            Press x to remove a worktree",
+		"            Press " + keys.Key(KeyClear) + " to remove a worktree",
 		helpKeyStyle.Render("Beads") + "      Issue tracking (tasks, bugs, features)",
 		"",
 		helpKeyStyle.Render("Plugins") + "    Town/rig extensions run during patrol",
@@ -4676,56 +5096,7 @@ func (m Model) renderHelpOverlay() string {
 		"            Sessions persist even if Perch closes",
 	}
 
-	keymap := []string{
-		"",
-		helpHeaderStyle.Render("Keymap"),
-		"",
-		helpKeyStyle.Render("?") + "          Show this help",
-		helpKeyStyle.Render("q") + "          Quit",
-		helpKeyStyle.Render("tab") + "        Next panel",
-		helpKeyStyle.Render("shift+tab") + "  Previous panel",
-		helpKeyStyle.Render("h/l") + "        Panel left/right, section switch",
-		helpKeyStyle.Render("j/k") + "        Navigate up/down",
-		helpKeyStyle.Render("0-9") + "        Jump to section (0=Identity...9=Alerts)",
-		helpKeyStyle.Render("-") + "          Operator console (system health)",
-		helpKeyStyle.Render("H") + "          Toggle convoy active/history view",
-		helpKeyStyle.Render("x") + "          Remove worktree / clear filters",
-		helpKeyStyle.Render("e") + "          Edit rig settings / Cycle status filter (beads)",
-		helpKeyStyle.Render("g") + "          Filter by assignee (beads/lifecycle)",
-		helpKeyStyle.Render("a") + "          Add new rig",
-		helpKeyStyle.Render("A") + "          Attach to a different town",
-		helpKeyStyle.Render("n") + "          Nudge polecat (merge queue)",
-		helpKeyStyle.Render("c") + "          Stop idle polecat (agents)",
-		helpKeyStyle.Render("C") + "          Stop all idle polecats in rig",
-		helpKeyStyle.Render("D") + "          Export snapshot to JSON (debug)",
-		helpKeyStyle.Render("r") + "          Refresh data",
-		helpKeyStyle.Render("b") + "          Boot rig / Create-edit bead (beads)",
-		helpKeyStyle.Render("s") + "          Shutdown rig / Toggle scope (beads)",
-		helpKeyStyle.Render("t") + "          Cycle type filter (beads)",
-		helpKeyStyle.Render("p") + "          Cycle priority filter (beads)",
-		helpKeyStyle.Render("d") + "          Delete selected rig",
-		helpKeyStyle.Render("o") + "          Open logs for agent",
-		"",
-		helpHeaderStyle.Render("Agent Actions (when in Agents section)"),
-		"",
-		helpKeyStyle.Render("S") + "          Sling work to agent",
-		helpKeyStyle.Render("H") + "          Handoff agent's work",
-		helpKeyStyle.Render("K") + "          Kill/stop agent",
-		helpKeyStyle.Render("n") + "          Nudge agent with message",
-		helpKeyStyle.Render("m") + "          Mail agent",
-		helpKeyStyle.Render("L") + "          View recent output (tmux-optional)",
-		helpKeyStyle.Render("T") + "          Open session (advanced)",
-		"",
-		helpHeaderStyle.Render("Plugin Actions (when in Plugins section)"),
-		"",
-		helpKeyStyle.Render("e") + "          Toggle plugin enabled/disabled",
-		"",
-		helpHeaderStyle.Render("Infrastructure Actions (when in Operator section)"),
-		"",
-		helpKeyStyle.Render("b") + "          Start selected subsystem (Deacon/Witness/Refinery)",
-		helpKeyStyle.Render("s") + "          Stop selected subsystem",
-		helpKeyStyle.Render("r") + "          Restart selected subsystem",
-	}
+	keymap := m.helpKeymapLines()
 
 	dismissMsg := "\n" + mutedStyle.Render("Press any key to dismiss")
 
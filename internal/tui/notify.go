@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// notifyTimeout bounds delivering the notifications for one refresh.
+const notifyTimeout = 15 * time.Second
+
+// notifyFailedMsg signals that a notification could not be delivered.
+type notifyFailedMsg struct {
+	err error
+}
+
+// notifyCmd checks a live snapshot for notification conditions off the UI goroutine.
+func (m Model) notifyCmd(snap *data.Snapshot) tea.Cmd {
+	if m.notifier == nil || snap == nil {
+		return nil
+	}
+	notifier := m.notifier
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+		if _, err := notifier.Observe(ctx, snap); err != nil {
+			return notifyFailedMsg{err: err}
+		}
+		return nil
+	}
+}
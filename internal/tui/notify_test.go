@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/config"
+	"github.com/andyrewlee/perch/internal/notify"
+)
+
+// deaconSnapshot returns a snapshot with the deacon running or stopped.
+func deaconSnapshot(running bool) *data.Snapshot {
+	return &data.Snapshot{
+		LoadedAt: now(),
+		Town: &data.TownStatus{
+			Name:   "test-town",
+			Agents: []data.Agent{{Name: "deacon", Address: "deacon/", Role: "deacon", Running: running}},
+		},
+	}
+}
+
+func TestRefreshNotifies(t *testing.T) {
+	m := NewTestModel(t)
+	var out bytes.Buffer
+	m.notifier = notify.New(notify.Config{Bell: true}, &out)
+
+	updated, cmd := m.Update(refreshMsg{snapshot: deaconSnapshot(true)})
+	m = runCmd(t, updated.(Model), cmd)
+	if out.Len() != 0 {
+		t.Fatalf("baseline refresh should not notify, got %q", out.String())
+	}
+
+	updated, cmd = m.Update(refreshMsg{snapshot: deaconSnapshot(false)})
+	runCmd(t, updated.(Model), cmd)
+	if out.String() != "\a" {
+		t.Errorf("expected a bell when the deacon stops, got %q", out.String())
+	}
+}
+
+func TestRefreshNotifiesWhileTimeTraveling(t *testing.T) {
+	m := NewTestModel(t)
+	var out bytes.Buffer
+	m.notifier = notify.New(notify.Config{Bell: true}, &out)
+
+	updated, cmd := m.Update(refreshMsg{snapshot: deaconSnapshot(true)})
+	m = runCmd(t, updated.(Model), cmd)
+	m.timeTravel = &timeTravelState{entries: []data.HistoryEntry{{}}}
+
+	updated, cmd = m.Update(refreshMsg{snapshot: deaconSnapshot(false)})
+	runCmd(t, updated.(Model), cmd)
+	if out.String() != "\a" {
+		t.Errorf("expected live notifications while browsing history, got %q", out.String())
+	}
+}
+
+func TestReloadKeepsNotificationHistory(t *testing.T) {
+	m := NewTestModel(t)
+	var out bytes.Buffer
+	m.notifier = notify.New(notify.Config{Bell: true}, &out)
+	notifier := m.notifier
+
+	for _, running := range []bool{true, false} {
+		updated, cmd := m.Update(refreshMsg{snapshot: deaconSnapshot(running)})
+		m = runCmd(t, updated.(Model), cmd)
+	}
+	cfg := config.Default()
+	cfg.Notify.Bell = true
+	if err := m.applySettings(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if m.notifier != notifier {
+		t.Fatal("expected the reload to keep the notifier")
+	}
+
+	// The deacon flapping back down is still within the dedup window
+	for _, running := range []bool{true, false} {
+		updated, cmd := m.Update(refreshMsg{snapshot: deaconSnapshot(running)})
+		m = runCmd(t, updated.(Model), cmd)
+	}
+	if out.String() != "\a" {
+		t.Errorf("expected a single bell across the reload, got %q", out.String())
+	}
+}
+
+func TestNotifyFailedShowsStatus(t *testing.T) {
+	m := NewTestModel(t)
+	updated, _ := m.Update(notifyFailedMsg{err: errors.New("notify hook notify-send: exit status 1")})
+	m = updated.(Model)
+	if m.statusMessage == nil || !m.statusMessage.IsError || !strings.Contains(m.statusMessage.Text, "notify-send") {
+		t.Errorf("expected error status, got %+v", m.statusMessage)
+	}
+}
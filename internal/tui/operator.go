@@ -65,6 +65,7 @@ type SubsystemHealth struct {
 	LastHeartbeat time.Time      // Last heartbeat from the service (if available)
 	LastError    string          // Last error message (if any)
 	Rig          string          // Rig name (for per-rig items)
+	FixCommand   []string        // Command that fixes the problem (doctor checks)
 }
 
 // operatorItem wraps SubsystemHealth for sidebar selection.
@@ -139,6 +140,9 @@ func BuildOperatorState(snap *data.Snapshot) *OperatorState {
 		}
 	}
 
+	// 6. Failing doctor checks
+	state.Subsystems = append(state.Subsystems, buildDoctorHealth(snap.DoctorReport)...)
+
 	// Count issues
 	for _, s := range state.Subsystems {
 		if s.Status == SubsystemError {
@@ -153,6 +157,40 @@ func BuildOperatorState(snap *data.Snapshot) *OperatorState {
 	return state
 }
 
+// buildDoctorHealth lists the gt doctor checks that warned or failed, so
+// their fixes can be run from the operator console.
+func buildDoctorHealth(report *data.DoctorReport) []SubsystemHealth {
+	if report == nil {
+		return nil
+	}
+	var subsystems []SubsystemHealth
+	for _, check := range report.Checks {
+		h := SubsystemHealth{
+			Name:        "Check: " + check.Name,
+			Subsystem:   "doctor_" + check.Name,
+			Message:     check.Message,
+			Action:      check.SuggestFix,
+			LastChecked: report.LoadedAt,
+			FixCommand:  check.FixCommand,
+		}
+		switch check.Status {
+		case data.CheckError:
+			h.Status = SubsystemError
+		case data.CheckWarning:
+			h.Status = SubsystemWarning
+		default:
+			continue
+		}
+		details := check.Details
+		if check.Duration > 0 {
+			details = append(details[:len(details):len(details)], "took "+formatLoadDuration(check.Duration))
+		}
+		h.Details = strings.Join(details, "; ")
+		subsystems = append(subsystems, h)
+	}
+	return subsystems
+}
+
 // buildDeaconHealth checks deacon/watchdog health.
 // It evaluates the operational state to determine if deacon is healthy,
 // in degraded mode, has watchdog issues, or has stale heartbeats.
@@ -684,8 +722,8 @@ func RenderOperatorSection(state *OperatorState, selection int, isActive bool, w
 // RenderOperatorDetails renders the details panel for the operator console.
 // It shows a summary of all subsystems and detailed information about
 // the selected subsystem including status, message, details, and
-// recommended actions.
-func RenderOperatorDetails(state *OperatorState, selection int, width int) string {
+// recommended actions. Read-only mode leaves out the action controls.
+func RenderOperatorDetails(state *OperatorState, selection int, width int, readOnly bool) string {
 	var lines []string
 
 	lines = append(lines, headerStyle.Render("Operator Console"))
@@ -765,7 +803,7 @@ func RenderOperatorDetails(state *OperatorState, selection int, width int) strin
 		}
 
 		// Action controls for controllable subsystems
-		if isControllableSubsystem(sub.Subsystem) {
+		if isControllableSubsystem(sub.Subsystem) && !readOnly {
 			lines = append(lines, headerStyle.Render("Controls"))
 			controls := renderActionControls(sub)
 			for _, control := range controls {
@@ -773,10 +811,20 @@ func RenderOperatorDetails(state *OperatorState, selection int, width int) strin
 			}
 			lines = append(lines, "")
 		}
+
+		// Fix for failing doctor checks
+		if len(sub.FixCommand) > 0 && !readOnly {
+			lines = append(lines, headerStyle.Render("Fix"))
+			lines = append(lines, "  "+strings.Join(sub.FixCommand, " "))
+			lines = append(lines, mutedStyle.Render("  Press 'F' to run it"))
+			lines = append(lines, "")
+		}
 	}
 
 	// Quick actions hint
-	lines = append(lines, mutedStyle.Render("Controls: [b] Start  [s] Stop  [r] Restart  [R] Refresh"))
+	if !readOnly {
+		lines = append(lines, mutedStyle.Render("Controls: [b] Start  [s] Stop  [r] Restart  [R] Refresh"))
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -0,0 +1,404 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteMaxResults caps how many matches the palette lists at once.
+const paletteMaxResults = 10
+
+// paletteItem is one palette entry: either an action on the current
+// selection or an entity in the snapshot to jump to.
+type paletteItem struct {
+	label  string // Matched and displayed text
+	detail string // Key for actions, kind for entities
+
+	action KeyAction // Set for actions
+	target entityRef // Set for entities
+}
+
+// entityRef locates an entity in the sidebar.
+type entityRef struct {
+	section SidebarSection
+	id      string // Sidebar item ID to select
+	history bool   // Landed convoy, shown in convoy history
+}
+
+// CommandPalette is the fuzzy finder over actions and entities.
+type CommandPalette struct {
+	input     textinput.Model
+	items     []paletteItem
+	matches   []paletteItem
+	Selection int
+}
+
+// NewCommandPalette creates a palette over items, listed in the given
+// order until a query is typed.
+func NewCommandPalette(items []paletteItem) *CommandPalette {
+	ti := textinput.New()
+	ti.Placeholder = "Search actions, rigs, agents, beads, mail..."
+	ti.CharLimit = 100
+	ti.Width = 50
+	ti.Focus()
+
+	p := &CommandPalette{input: ti, items: items}
+	p.filter()
+	return p
+}
+
+// Query returns the current search text.
+func (p *CommandPalette) Query() string {
+	return p.input.Value()
+}
+
+// Selected returns the highlighted match.
+func (p *CommandPalette) Selected() (paletteItem, bool) {
+	if p.Selection < 0 || p.Selection >= len(p.matches) {
+		return paletteItem{}, false
+	}
+	return p.matches[p.Selection], true
+}
+
+// filter ranks items against the query, best match first.
+func (p *CommandPalette) filter() {
+	query := strings.TrimSpace(p.input.Value())
+	type scored struct {
+		item  paletteItem
+		score int
+	}
+	var ranked []scored
+	for _, item := range p.items {
+		if score, ok := fuzzyScore(query, item.label); ok {
+			ranked = append(ranked, scored{item, score})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+
+	p.matches = p.matches[:0]
+	for _, r := range ranked {
+		p.matches = append(p.matches, r.item)
+	}
+	p.Selection = 0
+}
+
+// openPalette opens the palette over the current selection's actions and
+// every entity in the snapshot.
+func (m *Model) openPalette() {
+	items := m.paletteActions()
+	items = append(items, m.paletteEntities()...)
+	m.palette = NewCommandPalette(items)
+}
+
+// paletteActions lists the actions that apply to the sidebar selection.
+// Each one runs through the same key handler, so it gets the same checks
+// and confirm or input dialogs as its key.
+func (m Model) paletteActions() []paletteItem {
+	keys := m.keyMap()
+	var items []paletteItem
+	add := func(action ActionType, key KeyAction, target string) {
+		if m.readOnly() && IsMutating(action) {
+			return
+		}
+		label := actionName(action)
+		if target != "" {
+			label += " " + target
+		}
+		items = append(items, paletteItem{label: label, detail: keys.Binding(key).Label(), action: key})
+	}
+
+	s := m.sidebar
+	sel := s.Selection
+	switch s.Section {
+	case SectionRigs:
+		if m.selectedRig != "" {
+			add(ActionBootRig, KeyBoot, m.selectedRig)
+			add(ActionShutdownRig, KeyShutdown, m.selectedRig)
+			add(ActionDeleteRig, KeyDelete, m.selectedRig)
+			add(ActionStopAllIdle, KeyStopAllIdle, m.selectedRig)
+		}
+
+	case SectionAgents:
+		if sel >= 0 && sel < len(s.Agents) && m.selectedAgent != "" {
+			a := s.Agents[sel].a
+			if !a.Running {
+				add(ActionStartSession, KeyBoot, a.Name)
+			}
+			add(ActionOpenSession, KeyAttachSession, a.Name)
+			add(ActionViewSessionOutput, KeyViewOutput, a.Name)
+			add(ActionOpenLogs, KeyLogs, a.Name)
+			add(ActionPresetNudge, KeyNudge, a.Name)
+			add(ActionSlingWork, KeySling, a.Name)
+			add(ActionMailAgent, KeyMail, a.Name)
+			add(ActionHandoff, KeyHandoff, a.Name)
+			add(ActionRestartSession, KeyRestart, a.Name)
+			add(ActionStopAgent, KeyKill, a.Name)
+			if a.Role == "polecat" && a.Running && !a.HasWork {
+				add(ActionStopPolecat, KeyStopIdle, a.Name)
+			}
+		}
+
+	case SectionMergeQueue:
+		if sel >= 0 && sel < len(s.MRs) {
+			mr := s.MRs[sel].mr
+			add(ActionMQRetry, KeyRefresh, mr.ID)
+			add(ActionMQViewDetails, KeyDelete, mr.ID)
+			add(ActionMQOpenLogs, KeyRight, mr.ID)
+			add(ActionViewMRLogs, KeyLogs, s.MRs[sel].rig)
+			if mr.HasConflicts || mr.NeedsRebase {
+				add(ActionNudgePolecat, KeyNudge, mr.Worker)
+			}
+		}
+
+	case SectionMail:
+		if sel >= 0 && sel < len(s.Mail) {
+			mail := s.Mail[sel].m
+			if mail.Read {
+				add(ActionMarkMailUnread, KeyMail, "")
+			} else {
+				add(ActionMarkMailRead, KeyMail, "")
+			}
+			add(ActionAckMail, KeyAckMail, "")
+		}
+		if len(s.Mail) > 0 {
+			add(ActionBulkMailRead, KeyMarkAllRead, "")
+			add(ActionBulkMailArchive, KeyArchiveAll, "")
+		}
+
+	case SectionBeads:
+		if sel >= 0 && sel < len(s.Beads) {
+			issue := s.Beads[sel].issue
+			add(ActionEditBead, KeyBoot, issue.ID)
+			add(ActionAddComment, KeyStopIdle, issue.ID)
+			add(ActionRefileIssue, KeyRefile, issue.ID)
+			if issue.Status == "closed" {
+				add(ActionReopenBead, KeyReopenBead, issue.ID)
+			} else {
+				add(ActionCloseBead, KeyCloseBead, issue.ID)
+			}
+		} else {
+			add(ActionCreateBead, KeyBoot, "")
+		}
+
+	case SectionWorktrees:
+		if sel >= 0 && sel < len(s.Worktrees) && s.Worktrees[sel].wt.Clean {
+			add(ActionRemoveWorktree, KeyClear, "")
+		}
+
+	case SectionPlugins:
+		if m.selectedPlugin != "" {
+			add(ActionTogglePlugin, KeyEdit, m.selectedPlugin)
+		}
+
+	case SectionOperator:
+		if sel >= 0 && sel < len(s.Operator) {
+			sub := s.Operator[sel].h
+			switch {
+			case sub.Subsystem == "deacon":
+				add(ActionStartDeacon, KeyBoot, "")
+				add(ActionStopDeacon, KeyShutdown, "")
+				add(ActionRestartDeacon, KeyRefresh, "")
+			case strings.HasPrefix(sub.Subsystem, "witness_") && sub.Rig != "":
+				add(ActionStartWitness, KeyBoot, sub.Rig)
+				add(ActionStopWitness, KeyShutdown, sub.Rig)
+				add(ActionRestartWitness, KeyRefresh, sub.Rig)
+			case strings.HasPrefix(sub.Subsystem, "refinery_") && sub.Rig != "":
+				add(ActionStartRefinery, KeyBoot, sub.Rig)
+				add(ActionStopRefinery, KeyShutdown, sub.Rig)
+				add(ActionRestartRefineryAlt, KeyRefresh, sub.Rig)
+			}
+		}
+	}
+
+	// r retries or restarts in these sections instead of refreshing
+	if s.Section != SectionMergeQueue && s.Section != SectionOperator {
+		add(ActionRefresh, KeyRefresh, "")
+	}
+	add(ActionAddRig, KeyAddRig, "")
+	add(ActionCreateWork, KeyNewWork, "")
+	add(ActionExportSnapshot, KeyExport, "")
+	items = append(items, paletteItem{label: "Action journal", detail: keys.Binding(KeyJournal).Label(), action: KeyJournal})
+	items = append(items, paletteItem{label: "Towns overview", detail: keys.Binding(KeyTowns).Label(), action: KeyTowns})
+	return items
+}
+
+// paletteEntities lists the rigs, agents, convoys, merge requests, beads
+// and mail in the snapshot.
+func (m Model) paletteEntities() []paletteItem {
+	snap := m.snapshot
+	if snap == nil {
+		return nil
+	}
+
+	var items []paletteItem
+	if snap.Town != nil {
+		for _, r := range snap.Town.Rigs {
+			items = append(items, paletteItem{label: r.Name, detail: "rig", target: entityRef{SectionRigs, r.Name, false}})
+		}
+		for _, a := range snap.Town.Agents {
+			items = append(items, paletteItem{label: a.Address, detail: "agent", target: entityRef{SectionAgents, a.Address, false}})
+		}
+	}
+	for _, c := range snap.Convoys {
+		items = append(items, paletteItem{label: c.ID + " " + c.Title, detail: "convoy", target: entityRef{SectionConvoys, c.ID, false}})
+	}
+	for _, c := range snap.ClosedConvoys {
+		items = append(items, paletteItem{label: c.ID + " " + c.Title, detail: "landed convoy", target: entityRef{SectionConvoys, c.ID, true}})
+	}
+
+	rigs := make([]string, 0, len(snap.MergeQueues))
+	for rig := range snap.MergeQueues {
+		rigs = append(rigs, rig)
+	}
+	sort.Strings(rigs)
+	for _, rig := range rigs {
+		for _, mr := range snap.MergeQueues[rig] {
+			items = append(items, paletteItem{label: mr.ID + " " + mr.Title, detail: "MR in " + rig, target: entityRef{SectionMergeQueue, mr.ID, false}})
+		}
+	}
+
+	for _, issue := range snap.Issues {
+		items = append(items, paletteItem{label: issue.ID + " " + issue.Title, detail: "bead", target: entityRef{SectionBeads, issue.ID, false}})
+	}
+	for _, mail := range snap.Mail {
+		items = append(items, paletteItem{label: mail.Subject, detail: "mail from " + mail.From, target: entityRef{SectionMail, mail.ID, false}})
+	}
+	return items
+}
+
+// handlePaletteKey handles key presses while the palette is open.
+func (m Model) handlePaletteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.palette = nil
+		return m, nil
+
+	case "up", "ctrl+p":
+		if m.palette.Selection > 0 {
+			m.palette.Selection--
+		}
+		return m, nil
+
+	case "down", "ctrl+n":
+		if m.palette.Selection < len(m.palette.matches)-1 {
+			m.palette.Selection++
+		}
+		return m, nil
+
+	case "enter":
+		item, ok := m.palette.Selected()
+		m.palette = nil
+		if !ok {
+			return m, nil
+		}
+		// Actions apply to the sidebar selection the palette was built from
+		m.focus = PanelSidebar
+		if item.action != "" {
+			return m.handleAction(item.action)
+		}
+		return m, m.jumpToEntity(item.target, item.label)
+	}
+
+	m.palette.input, _ = m.palette.input.Update(msg)
+	m.palette.filter()
+	return m, nil
+}
+
+// jumpToEntity selects ref in its sidebar section. Beads outside the
+// current scope switch scope; items hidden by filters are reported.
+func (m *Model) jumpToEntity(ref entityRef, label string) tea.Cmd {
+	s := m.sidebar
+	switch ref.section {
+	case SectionConvoys:
+		s.ShowConvoyHistory = ref.history
+	case SectionBeads:
+		scope := BeadsScopeRig
+		if strings.HasPrefix(ref.id, "hq-") {
+			scope = BeadsScopeTown
+		}
+		if s.BeadsScope != scope {
+			s.BeadsScope = scope
+			s.UpdateFromSnapshot(m.snapshot)
+		}
+	}
+
+	m.focus = PanelSidebar
+	s.Section = ref.section
+	s.Selection = 0
+	for i, it := range s.CurrentItems() {
+		if it.ID() == ref.id {
+			s.Selection = i
+			return tea.Batch(m.syncSelection(), m.syncSelectedAgent())
+		}
+	}
+	m.setStatus(label+" is hidden by the "+ref.section.String()+" filters", true)
+	return tea.Batch(m.syncSelection(), statusExpireCmd(3*time.Second))
+}
+
+// renderPalette renders the palette centered over the screen.
+func (m Model) renderPalette() string {
+	p := m.palette
+	width := 70
+	if width > m.width-4 {
+		width = m.width - 4
+	}
+	// Horizontal padding takes 4 columns
+	inner := width - 4
+
+	var b strings.Builder
+	b.WriteString(helpTitleStyle.Render("Command Palette"))
+	b.WriteString("\n\n")
+	b.WriteString(p.input.View())
+	b.WriteString("\n\n")
+
+	if len(p.matches) == 0 {
+		b.WriteString(mutedStyle.Render("  No matches"))
+		b.WriteString("\n")
+	}
+	// Scroll so the selection stays in view
+	start := 0
+	if p.Selection >= paletteMaxResults {
+		start = p.Selection - paletteMaxResults + 1
+	}
+	end := min(start+paletteMaxResults, len(p.matches))
+	for i := start; i < end; i++ {
+		item := p.matches[i]
+		detail := truncate(item.detail, inner/3)
+		label := truncate(item.label, inner-4-lipgloss.Width(detail))
+		gap := inner - 2 - lipgloss.Width(label) - lipgloss.Width(detail)
+		if gap < 1 {
+			gap = 1
+		}
+		if i == p.Selection {
+			b.WriteString(selectedItemStyle.Render("> " + label))
+		} else {
+			b.WriteString("  " + label)
+		}
+		b.WriteString(strings.Repeat(" ", gap))
+		b.WriteString(mutedStyle.Render(detail))
+		b.WriteString("\n")
+	}
+	if len(p.matches) > end {
+		b.WriteString(mutedStyle.Render("  ..."))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("↑/↓: select • enter: run • esc: close"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(1, 2).
+		Width(width).
+		Render(b.String())
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	if _, ok := fuzzyScore("shtdn", "Shutdown gastown"); !ok {
+		t.Error("expected a subsequence to match")
+	}
+	if _, ok := fuzzyScore("boot", "Shutdown gastown"); ok {
+		t.Error("expected out-of-order runes not to match")
+	}
+
+	prefix, _ := fuzzyScore("sr", "Stop refinery")
+	inner, _ := fuzzyScore("sr", "Restart witness")
+	if prefix <= inner {
+		t.Errorf("expected word starts to outrank inner matches, got %d <= %d", prefix, inner)
+	}
+}
+
+func paletteTestModel(t *testing.T) Model {
+	t.Helper()
+	m := NewTestModel(t)
+	m.ready = true
+	m.width, m.height = 120, 40
+	m.applySnapshot(&data.Snapshot{
+		Town: &data.TownStatus{
+			Rigs: []data.Rig{{Name: "gastown"}, {Name: "perch"}},
+			Agents: []data.Agent{
+				{Name: "ace", Address: "perch/polecats/ace", Role: "polecat", Running: true},
+			},
+		},
+		Issues: []data.Issue{
+			{ID: "pe-12", Title: "Fix flaky refresh", Status: "open"},
+			{ID: "hq-3", Title: "Town roadmap", Status: "open"},
+		},
+		Mail: []data.MailMessage{{ID: "m-1", Subject: "Merge blocked", From: "perch/refinery", Read: true}},
+	})
+	return m
+}
+
+func typePalette(t *testing.T, m Model, query string) Model {
+	t.Helper()
+	m = pressKey(t, m, ":")
+	if m.palette == nil {
+		t.Fatal("expected : to open the palette")
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(query)})
+	return updated.(Model)
+}
+
+func enter(m Model) Model {
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return updated.(Model)
+}
+
+func TestPaletteRunsActionThroughConfirm(t *testing.T) {
+	m := paletteTestModel(t)
+	m.sidebar.Section = SectionRigs
+	m.sidebar.Selection = 1
+	m.syncSelectedRig()
+
+	m = typePalette(t, m, "shutdown")
+	if item, ok := m.palette.Selected(); !ok || item.label != "Shutdown perch" {
+		t.Fatalf("expected Shutdown perch first, got %+v", item)
+	}
+	if view := m.View(); !strings.Contains(view, "Command Palette") || !strings.Contains(view, "Shutdown perch") {
+		t.Error("expected the palette overlay to list the match")
+	}
+	m = enter(m)
+
+	if m.palette != nil {
+		t.Error("expected the palette to close")
+	}
+	if m.confirmDialog == nil || m.confirmDialog.Action != ActionShutdownRig || m.confirmDialog.Target != "perch" {
+		t.Fatalf("expected shutdown confirmation for perch, got %+v", m.confirmDialog)
+	}
+}
+
+func TestPaletteActionsFollowSelection(t *testing.T) {
+	m := paletteTestModel(t)
+	m.sidebar.Section = SectionMail
+	m.sidebar.Selection = 0
+
+	var labels []string
+	for _, item := range m.paletteActions() {
+		labels = append(labels, item.label)
+	}
+	got := strings.Join(labels, ", ")
+	if !strings.Contains(got, "Mark unread") || strings.Contains(got, "Mark read") {
+		t.Errorf("expected only Mark unread for read mail, got %q", got)
+	}
+	if strings.Contains(got, "Boot") {
+		t.Errorf("expected no rig actions in the Mail section, got %q", got)
+	}
+}
+
+func TestPaletteJumpsToEntity(t *testing.T) {
+	m := paletteTestModel(t)
+
+	m = enter(typePalette(t, m, "roadmap"))
+	if m.sidebar.Section != SectionBeads || m.sidebar.BeadsScope != BeadsScopeTown {
+		t.Fatalf("expected town beads, got section %s scope %s", m.sidebar.Section, m.sidebar.BeadsScope)
+	}
+	if item := m.sidebar.SelectedItem(); item == nil || item.ID() != "hq-3" {
+		t.Errorf("expected hq-3 selected, got %v", item)
+	}
+
+	m = enter(typePalette(t, m, "polecats/ace"))
+	if m.sidebar.Section != SectionAgents || m.selectedAgent != "perch/polecats/ace" {
+		t.Errorf("expected ace selected in Agents, got %s %q", m.sidebar.Section, m.selectedAgent)
+	}
+}
+
+func TestPaletteReportsFilteredEntity(t *testing.T) {
+	m := paletteTestModel(t)
+	m.sidebar.BeadsStatusFilter = "closed"
+	m.sidebar.UpdateFromSnapshot(m.snapshot)
+
+	m = enter(typePalette(t, m, "flaky refresh"))
+	if m.sidebar.Section != SectionBeads {
+		t.Errorf("expected the Beads section, got %s", m.sidebar.Section)
+	}
+	if m.statusMessage == nil || !strings.Contains(m.statusMessage.Text, "hidden by the Beads filters") {
+		t.Errorf("expected a filtered status, got %+v", m.statusMessage)
+	}
+}
@@ -82,10 +82,20 @@ func (c convoyItem) ID() string     { return c.c.ID }
 func (c convoyItem) Label() string  { return c.c.Title }
 func (c convoyItem) Status() string { return c.c.Status }
 
+// qualify prefixes an item's address with town, or leaves it alone when
+// town is empty because only one town is registered.
+func qualify(town, address string) string {
+	if town == "" {
+		return address
+	}
+	return data.QualifyAddress(town, address)
+}
+
 // mrItem wraps data.MergeRequest for selection
 type mrItem struct {
-	mr  data.MergeRequest
-	rig string
+	mr   data.MergeRequest
+	rig  string
+	town string // Qualifies the rig (see SidebarState.Town)
 }
 
 func (m mrItem) ID() string { return m.mr.ID }
@@ -97,21 +107,22 @@ func (m mrItem) Label() string {
 		indicator = "~"
 	}
 	if indicator != "" {
-		return fmt.Sprintf("[%s]%s %s", m.rig, indicator, m.mr.Title)
+		return fmt.Sprintf("[%s]%s %s", qualify(m.town, m.rig), indicator, m.mr.Title)
 	}
-	return fmt.Sprintf("[%s] %s", m.rig, m.mr.Title)
+	return fmt.Sprintf("[%s] %s", qualify(m.town, m.rig), m.mr.Title)
 }
 func (m mrItem) Status() string { return m.mr.Status }
 
 // agentItem wraps data.Agent for selection
 type agentItem struct {
-	a data.Agent
+	a    data.Agent
+	town string // Qualifies the name (see SidebarState.Town)
 }
 
 func (a agentItem) ID() string { return a.a.Address }
 func (a agentItem) Label() string {
 	badge := agentStatusBadge(a.a.Running, a.a.HasWork, a.a.UnreadMail)
-	label := fmt.Sprintf("%s %s", badge, a.a.Name)
+	label := fmt.Sprintf("%s %s", badge, qualify(a.town, a.a.Name))
 	// Append hooked bead ID if present
 	if a.a.HookedBeadID != "" {
 		label += " " + mutedStyle.Render("["+a.a.HookedBeadID+"]")
@@ -178,6 +189,9 @@ func (a alertItem) Label() string {
 	badge := statusErrorStyle.Render("!")
 	// Truncate error message for display
 	errMsg := a.e.Error
+	if a.e.Breaker != nil && a.e.Breaker.State == data.BreakerOpen {
+		errMsg = "paused: " + errMsg
+	}
 	if len(errMsg) > 30 {
 		errMsg = errMsg[:27] + "..."
 	}
@@ -273,7 +287,8 @@ func (r rigItem) Status() string {
 
 // mailItem wraps data.MailMessage for selection
 type mailItem struct {
-	m data.MailMessage
+	m    data.MailMessage
+	town string // Qualifies the subject (see SidebarState.Town)
 }
 
 func (m mailItem) ID() string { return m.m.ID }
@@ -297,6 +312,8 @@ func (m mailItem) Label() string {
 		subject = subject[:maxLen-3] + "..."
 	}
 
+	subject = qualify(m.town, subject)
+
 	if typeBadge != "" {
 		return fmt.Sprintf("%s %s %s", readBadge, typeBadge, subject)
 	}
@@ -508,6 +525,31 @@ type SidebarState struct {
 
 	// LastSuccess tracks the last successful refresh time
 	LastSuccess time.Time
+
+	// Stale holds the sources showing data kept from an earlier load after
+	// failing, by when that data loaded (see StaleSince)
+	Stale map[string]time.Time
+
+	// ActivityMaxEvents caps the activity feed (0 = defaultActivityMaxEvents)
+	ActivityMaxEvents int
+
+	// ReadOnly hides action controls in the details panel
+	ReadOnly bool
+
+	// Schedule is how often each data source reloads, for diagnostics
+	Schedule data.Schedule
+
+	// Town qualifies agent, mail and merge queue labels with the town's
+	// name when more than one town is registered ("" otherwise)
+	Town string
+
+	// CommandStats is the latency of the commands loading runs (nil when
+	// not recorded)
+	CommandStats *data.CommandStats
+
+	// Items marked for bulk actions, by section and item ID
+	Marks      map[SidebarSection]map[string]bool
+	markAnchor string // Item ID a range mark starts from
 }
 
 // NewSidebarState creates a new sidebar state
@@ -757,6 +799,8 @@ func (s *SidebarState) UpdateFromSnapshot(snap *data.Snapshot) {
 		return
 	}
 
+	s.Stale = snap.Stale
+
 	// Update identity items
 	s.Identity = buildIdentityItems(snap.Identity)
 
@@ -812,7 +856,7 @@ func (s *SidebarState) UpdateFromSnapshot(snap *data.Snapshot) {
 		var newMRs []mrItem
 		for rig, mrs := range snap.MergeQueues {
 			for _, mr := range mrs {
-				newMRs = append(newMRs, mrItem{mr, rig})
+				newMRs = append(newMRs, mrItem{mr, rig, s.Town})
 			}
 		}
 
@@ -858,7 +902,7 @@ func (s *SidebarState) UpdateFromSnapshot(snap *data.Snapshot) {
 	if snap.Town != nil {
 		s.Agents = make([]agentItem, len(snap.Town.Agents))
 		for i, a := range snap.Town.Agents {
-			s.Agents[i] = agentItem{a}
+			s.Agents[i] = agentItem{a, s.Town}
 		}
 		s.AgentsLastRefresh = snap.LoadedAt
 		s.AgentsLoadError = nil
@@ -897,7 +941,7 @@ func (s *SidebarState) UpdateFromSnapshot(snap *data.Snapshot) {
 		// Mail loaded successfully - update the list
 		s.Mail = make([]mailItem, len(snap.Mail))
 		for i, m := range snap.Mail {
-			s.Mail[i] = mailItem{m}
+			s.Mail[i] = mailItem{m, s.Town}
 		}
 		s.MailLastRefresh = snap.LoadedAt
 		s.MailLoadError = nil
@@ -994,7 +1038,7 @@ func (s *SidebarState) UpdateFromSnapshot(snap *data.Snapshot) {
 	}
 
 	// Update activity feed
-	s.Activity = buildActivityFeed(snap, s.Activity)
+	s.Activity = buildActivityFeed(snap, s.Activity, s.ActivityMaxEvents)
 
 	// Clamp selection to valid range
 	s.clampSelection()
@@ -1251,7 +1295,7 @@ func RenderSidebar(state *SidebarState, snap *data.Snapshot, width, height int,
 			// Special handling for mail section with loading/error states
 			list = renderMailList(state, items, isActive, innerWidth, sectionHeight)
 		} else {
-			list = renderItemList(items, state.Selection, isActive, innerWidth, sectionHeight)
+			list = renderItemList(state, sec, items, isActive, innerWidth, sectionHeight)
 		}
 		sections = append(sections, header, list)
 	}
@@ -1304,6 +1348,13 @@ func renderSectionHeader(name string, section SidebarSection, active bool, state
 		header += " " + lipgloss.NewStyle().Foreground(lipgloss.Color("220")).Render("[F]")
 	}
 
+	// Mark sections showing data kept from before a failed load
+	if state != nil {
+		if since, ok := state.StaleSince(section); ok {
+			header += " " + renderStaleBadge(since)
+		}
+	}
+
 	// Add section description when active
 	if active {
 		if help := SectionHelp(section); help != "" {
@@ -1449,7 +1500,7 @@ func renderMQEmptyState(snap *data.Snapshot, opts *SidebarOptions, isActive bool
 	return strings.Join(lines, "\n")
 }
 
-func renderItemList(items []SelectableItem, selection int, isActiveSection bool, width, maxLines int) string {
+func renderItemList(state *SidebarState, sec SidebarSection, items []SelectableItem, isActiveSection bool, width, maxLines int) string {
 	if len(items) == 0 {
 		return mutedStyle.Render("  (empty)")
 	}
@@ -1474,10 +1525,10 @@ func renderItemList(items []SelectableItem, selection int, isActiveSection bool,
 			label = label[:truncateAt] + "..."
 		}
 
-		if isActiveSection && i == selection {
-			lines = append(lines, selectedItemStyle.Render("> "+label))
+		if isActiveSection && i == state.Selection {
+			lines = append(lines, selectedItemStyle.Render(state.listPrefix(sec, item, true)+label))
 		} else {
-			lines = append(lines, itemStyle.Render("  "+label))
+			lines = append(lines, itemStyle.Render(state.listPrefix(sec, item, false)+label))
 		}
 	}
 
@@ -1696,9 +1747,9 @@ func renderMergeQueueList(state *SidebarState, snap *data.Snapshot, opts *Sideba
 		}
 
 		if isActiveSection && i == state.Selection {
-			lines = append(lines, selectedItemStyle.Render("> "+label))
+			lines = append(lines, selectedItemStyle.Render(state.listPrefix(SectionMergeQueue, item, true)+label))
 		} else {
-			lines = append(lines, itemStyle.Render("  "+label))
+			lines = append(lines, itemStyle.Render(state.listPrefix(SectionMergeQueue, item, false)+label))
 		}
 	}
 
@@ -1774,9 +1825,9 @@ func renderAgentsList(state *SidebarState, snap *data.Snapshot, items []Selectab
 		}
 
 		if isActiveSection && i == state.Selection {
-			lines = append(lines, selectedItemStyle.Render("> "+label))
+			lines = append(lines, selectedItemStyle.Render(state.listPrefix(SectionAgents, item, true)+label))
 		} else {
-			lines = append(lines, itemStyle.Render("  "+label))
+			lines = append(lines, itemStyle.Render(state.listPrefix(SectionAgents, item, false)+label))
 		}
 	}
 
@@ -2017,9 +2068,9 @@ func renderMailList(state *SidebarState, items []SelectableItem, isActiveSection
 		}
 
 		if isActiveSection && i == state.Selection {
-			lines = append(lines, selectedItemStyle.Render("> "+label))
+			lines = append(lines, selectedItemStyle.Render(state.listPrefix(SectionMail, item, true)+label))
 		} else {
-			lines = append(lines, itemStyle.Render("  "+label))
+			lines = append(lines, itemStyle.Render(state.listPrefix(SectionMail, item, false)+label))
 		}
 	}
 
@@ -2092,9 +2143,9 @@ func renderBeadsList(state *SidebarState, items []SelectableItem, isActiveSectio
 		}
 
 		if isActiveSection && i == state.Selection {
-			lines = append(lines, selectedItemStyle.Render("> "+label))
+			lines = append(lines, selectedItemStyle.Render(state.listPrefix(SectionBeads, item, true)+label))
 		} else {
-			lines = append(lines, itemStyle.Render("  "+label))
+			lines = append(lines, itemStyle.Render(state.listPrefix(SectionBeads, item, false)+label))
 		}
 	}
 
@@ -2179,6 +2230,11 @@ func RenderDetails(state *SidebarState, snap *data.Snapshot, audit *AuditTimelin
 	}
 
 	title := titleStyle.Render("Details")
+	if state != nil {
+		if since, ok := state.StaleSince(state.Section); ok {
+			title += " " + renderStaleBadge(since)
+		}
+	}
 	content := renderSelectedDetails(state, snap, audit, innerWidth, dependencies, comments)
 
 	// Pad content to fill space
@@ -2258,8 +2314,10 @@ func renderSelectedDetails(state *SidebarState, snap *data.Snapshot, audit *Audi
 		}
 	case SectionAlerts:
 		if state.Selection >= 0 && state.Selection < len(state.Alerts) {
-			return renderAlertDetails(state.Alerts[state.Selection].e, snap, width)
+			return renderAlertDetails(state.Alerts[state.Selection].e, snap, state.Schedule, width)
 		}
+		// No error selected - show how every source is loading
+		return renderSourceDiagnostics(snap, state.Schedule, state.CommandStats)
 	case SectionBeads:
 		if state.Selection >= 0 && state.Selection < len(state.Beads) {
 			return renderBeadDetails(state.Beads[state.Selection].issue, state, width, dependencies, comments)
@@ -2274,14 +2332,14 @@ func renderSelectedDetails(state *SidebarState, snap *data.Snapshot, audit *Audi
 				return renderAgentDashboard(snap, width)
 			}
 		}
-		return RenderOperatorDetails(state.OperatorState, state.Selection, width)
+		return RenderOperatorDetails(state.OperatorState, state.Selection, width, state.ReadOnly)
 	}
 
 	return mutedStyle.Render("Select an item to see details")
 }
 
 // renderAlertDetails renders detailed view of a load error.
-func renderAlertDetails(e data.LoadError, snap *data.Snapshot, width int) string {
+func renderAlertDetails(e data.LoadError, snap *data.Snapshot, schedule data.Schedule, width int) string {
 	var lines []string
 
 	// Header with source
@@ -2312,8 +2370,8 @@ func renderAlertDetails(e data.LoadError, snap *data.Snapshot, width int) string
 	lines = append(lines, "")
 
 	// Last successful load (if available)
-	if snap != nil && snap.LastSuccess != nil {
-		if lastSuccess, ok := snap.LastSuccess[e.Source]; ok {
+	if snap != nil {
+		if lastSuccess, ok := snap.LastGood(e.Source); ok {
 			lines = append(lines, headerStyle.Render("Last Successful Load"))
 			lines = append(lines, fmt.Sprintf("Time:      %s", lastSuccess.Format("2006-01-02 15:04:05")))
 			lines = append(lines, fmt.Sprintf("Ago:       %s", formatDuration(time.Since(lastSuccess))))
@@ -2321,13 +2379,42 @@ func renderAlertDetails(e data.LoadError, snap *data.Snapshot, width int) string
 		}
 	}
 
+	// How the source loads
+	if snap != nil {
+		if timing, ok := snap.Timings[e.Source]; ok {
+			policy := schedule.Policy(e.Source)
+			lines = append(lines, headerStyle.Render("Timing"))
+			lines = append(lines, fmt.Sprintf("Took:      %s", formatLoadDuration(timing.Duration)))
+			if policy.OnChange {
+				lines = append(lines, "Reloads:   when its files change")
+			} else {
+				lines = append(lines, fmt.Sprintf("Reloads:   every %s", formatDuration(policy.Interval)))
+			}
+			if policy.Timeout > 0 {
+				lines = append(lines, fmt.Sprintf("Timeout:   %s", formatDuration(policy.Timeout)))
+			}
+			lines = append(lines, "")
+		}
+	}
+
+	// Circuit breaker, once the source has failed repeatedly
+	if b := e.Breaker; b != nil && b.Failures > 1 {
+		lines = append(lines, headerStyle.Render("Circuit Breaker"))
+		lines = append(lines, fmt.Sprintf("State:     %s", b.State))
+		lines = append(lines, fmt.Sprintf("Failures:  %d in a row", b.Failures))
+		if b.State == data.BreakerOpen {
+			lines = append(lines, fmt.Sprintf("Next try:  %s", b.RetryAt.Format("15:04:05")))
+		}
+		lines = append(lines, "")
+	}
+
 	// Suggested action
 	lines = append(lines, headerStyle.Render("Suggested Action"))
 	lines = append(lines, mutedStyle.Render(e.SuggestedAction()))
 	lines = append(lines, "")
 
 	// Quick actions hint
-	lines = append(lines, mutedStyle.Render("Press 'r' to refresh and retry loading"))
+	lines = append(lines, mutedStyle.Render("Press 'r' to retry this source now"))
 
 	return strings.Join(lines, "\n")
 }
@@ -2822,7 +2909,7 @@ func formatAge(t time.Time) string {
 	if t.IsZero() {
 		return "unknown"
 	}
-	d := time.Since(t)
+	d := since(t)
 	if d < time.Minute {
 		return fmt.Sprintf("%ds", int(d.Seconds()))
 	}
@@ -1550,3 +1550,68 @@ func TestBeadsPanelWithMixedScopes(t *testing.T) {
 		t.Errorf("expected 2 town beads (hq-*), got %d", len(state.Beads))
 	}
 }
+
+func TestSectionHeadersShowStaleBadge(t *testing.T) {
+	at := time.Date(2026, 1, 2, 7, 0, 0, 0, time.Local)
+	defer setNow(at.Add(10 * time.Minute))()
+	snap := &data.Snapshot{
+		Issues: []data.Issue{{ID: "pe-001", Title: "Kept issue", Status: "open"}},
+		Stale: map[string]time.Time{
+			data.SourceIssues:                at,
+			data.SourceMergeQueue + "_perch": at.Add(time.Minute),
+		},
+		LoadedAt: at.Add(10 * time.Minute),
+	}
+	state := NewSidebarState()
+	state.UpdateFromSnapshot(snap)
+
+	if len(state.Beads) != 1 {
+		t.Errorf("expected the kept issue listed, got %d", len(state.Beads))
+	}
+	if header := renderSectionHeader("Beads", SectionBeads, false, state); !strings.Contains(header, "stale since 07:00") {
+		t.Errorf("expected a stale badge on beads, got %q", header)
+	}
+	if header := renderSectionHeader("Merge Queue", SectionMergeQueue, false, state); !strings.Contains(header, "stale since 07:01") {
+		t.Errorf("expected a stale badge on the merge queue, got %q", header)
+	}
+	if header := renderSectionHeader("Mail", SectionMail, false, state); strings.Contains(header, "stale") {
+		t.Errorf("expected no stale badge on mail, got %q", header)
+	}
+}
+
+func TestPanelsShowStaleBadge(t *testing.T) {
+	m := NewTestModel(t)
+	m.ready = true
+	m.width, m.height = 120, 40
+	at := now().Add(-10 * time.Minute)
+	badge := "stale since " + at.Format("15:04")
+	snap := &data.Snapshot{
+		Town:        &data.TownStatus{Name: "test-town", Rigs: []data.Rig{{Name: "perch"}}},
+		MergeQueues: map[string][]data.MergeRequest{"perch": {{ID: "mr-1"}}},
+		Stale: map[string]time.Time{
+			data.SourceTownStatus:            at,
+			data.SourceMergeQueue + "_perch": at,
+		},
+		LoadedAt: now(),
+	}
+	m.applySnapshot(snap)
+	m.sidebar.Section = SectionRigs
+
+	if out := m.buildOverviewContent(); !strings.Contains(out, badge) {
+		t.Errorf("expected a stale badge in the overview, got:\n%s", out)
+	}
+	if out := RenderDetails(m.sidebar, m.snapshot, nil, 60, 20, false, nil, nil); !strings.Contains(out, badge) {
+		t.Errorf("expected a stale badge in the details panel, got:\n%s", out)
+	}
+	if out := m.renderTownMap(); !strings.Contains(out, badge) {
+		t.Errorf("expected a stale badge on the town map, got:\n%s", out)
+	}
+	if out := NewQueueHealthPanel(m.queueHealthData["perch"]).Render(60, 20); !strings.Contains(out, badge) {
+		t.Errorf("expected a stale badge in queue health, got:\n%s", out)
+	}
+
+	m.sidebar.Section = SectionMail
+	if out := RenderDetails(m.sidebar, m.snapshot, nil, 60, 20, false, nil, nil); strings.Contains(out, "stale") {
+		t.Errorf("expected no stale badge on mail details, got:\n%s", out)
+	}
+}
@@ -104,6 +104,8 @@ type QueueHealth struct {
 	PatrolFormulasFix     string // Suggested fix for patrol formulas
 	MigrationWarning      string // Warning message if legacy agent beads detected
 	MigrationFix          string // Suggested fix for migration
+	// When the kept queue last loaded, if its load is failing
+	StaleSince time.Time
 }
 
 // TimeSinceLastMerge returns formatted duration since last merge.
@@ -207,7 +209,11 @@ func (p *QueueHealthPanel) renderHeader() string {
 	}
 
 	state := stateStyle.Render(p.health.State.String())
-	return fmt.Sprintf("%s  Refinery: %s", queueTitleStyle.Render("Queue Health"), state)
+	header := fmt.Sprintf("%s  Refinery: %s", queueTitleStyle.Render("Queue Health"), state)
+	if !p.health.StaleSince.IsZero() {
+		header += "  " + renderStaleBadge(p.health.StaleSince)
+	}
+	return header
 }
 
 func (p *QueueHealthPanel) renderLastMerge() string {
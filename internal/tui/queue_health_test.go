@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/andyrewlee/perch/data"
 )
 
 func TestRefineryStateString(t *testing.T) {
@@ -23,6 +25,28 @@ func TestRefineryStateString(t *testing.T) {
 	}
 }
 
+func TestUpdateQueueHealthStalls(t *testing.T) {
+	m := NewTestModel(t)
+	refinery := data.Agent{Address: "perch/refinery", Role: "refinery", Running: true}
+	snap := &data.Snapshot{
+		Town: &data.TownStatus{Rigs: []data.Rig{
+			{Name: "perch", Agents: []data.Agent{refinery}},
+			{Name: "web", Agents: []data.Agent{{Address: "web/refinery", Role: "refinery"}}},
+		}},
+		MergeQueues: map[string][]data.MergeRequest{
+			"perch": {{ID: "mr-1", CreatedAt: now().Add(-2 * time.Hour)}},
+			"web":   nil,
+		},
+	}
+	m.updateQueueHealth(snap)
+	if got := m.queueHealthData["perch"].State; got != RefineryStalled {
+		t.Errorf("expected an idle refinery with an old MR to be stalled, got %s", got)
+	}
+	if got := m.queueHealthData["web"].State; got != RefineryIdle {
+		t.Errorf("expected a stopped refinery with nothing queued to be idle, got %s", got)
+	}
+}
+
 func TestQueueMRAgeBadge(t *testing.T) {
 	now := time.Now()
 
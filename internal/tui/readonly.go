@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// actionKeys are the keys that only ever run actions or lead to one. They
+// are disabled, and left out of help, in read-only mode.
+var actionKeys = map[KeyAction]bool{
+	KeyDryRun:      true,
+	KeyExport:      true,
+	KeyMark:        true,
+	KeyAddRig:      true,
+	KeyNewWork:     true,
+	KeyBoot:        true,
+	KeyShutdown:    true,
+	KeyNudge:       true,
+	KeyStopIdle:    true,
+	KeyStopAllIdle: true,
+	KeySling:       true,
+	KeyKill:        true,
+	KeyRestart:     true,
+	KeyMail:        true,
+	KeyOpenSession: true,
+	KeyAckMail:     true,
+	KeyMarkAllRead: true,
+	KeyArchiveAll:  true,
+	KeyRefile:      true,
+	KeyCloseBead:   true,
+	KeyReopenBead:  true,
+	KeyFix:         true,
+
+	KeyDetailNudge:   true,
+	KeyDetailAttach:  true,
+	KeyDetailMail:    true,
+	KeyDetailHandoff: true,
+	KeyDetailStop:    true,
+	KeyDetailStart:   true,
+}
+
+// readOnly reports whether actions are disabled.
+func (m Model) readOnly() bool {
+	return m.actionRunner.CheckWritable() != nil
+}
+
+// readOnlyBlocks reports whether read-only mode disables a key where the
+// sidebar is now. Keys that also filter or view, like e and d, stay
+// enabled in the sections where they do.
+func (m Model) readOnlyBlocks(action KeyAction) bool {
+	if !m.readOnly() {
+		return false
+	}
+	if actionKeys[action] {
+		return true
+	}
+	section := SectionIdentity
+	if m.sidebar != nil {
+		section = m.sidebar.Section
+	}
+	sidebar := m.focus == PanelSidebar
+	switch action {
+	case KeyRefresh:
+		// Retries merge requests and restarts subsystems
+		return sidebar && (section == SectionMergeQueue || section == SectionOperator)
+	case KeyDelete:
+		// Shows merge request details
+		return !sidebar || section != SectionMergeQueue
+	case KeyEdit:
+		// Cycles the lifecycle and beads filters
+		return !sidebar || (section != SectionLifecycle && section != SectionBeads)
+	case KeyAttachSession:
+		// Cycles the beads type filter
+		return !sidebar || section != SectionBeads
+	case KeyHandoff:
+		// Toggles convoy history
+		return !sidebar || section != SectionConvoys
+	case KeyClear:
+		// Removes worktrees
+		return sidebar && section == SectionWorktrees
+	}
+	return false
+}
+
+// refuseReadOnly reports an action refused in read-only mode.
+func (m Model) refuseReadOnly() (Model, tea.Cmd) {
+	m.setStatus("Read-only mode: actions are disabled", true)
+	return m, statusExpireCmd(3 * time.Second)
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/perch/internal/config"
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+func TestReadOnlyRunnerRefusesWrites(t *testing.T) {
+	mock := testutil.NewMockRunner()
+	r := NewActionRunnerWithRunner(t.TempDir(), mock)
+	r.ReadOnly = true
+	ctx := context.Background()
+
+	if err := r.CloseBead(ctx, "pe-1"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected close to be refused, got %v", err)
+	}
+	if err := r.CreateWork(ctx, "Fix it", "", "task", 2, "perch", "", false); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected create work to be refused, got %v", err)
+	}
+	if mock.Called() {
+		t.Errorf("expected nothing to run, got %v", mock.Calls())
+	}
+
+	plugin := t.TempDir()
+	if err := r.TogglePlugin(ctx, plugin); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected the plugin toggle to be refused, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(plugin, ".disabled")); !os.IsNotExist(err) {
+		t.Error("expected no .disabled marker")
+	}
+	if err := r.ExportSnapshot(ctx, nil); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected the export to be refused, got %v", err)
+	}
+
+	if err := r.OpenLogs(ctx, "perch/witness"); err != nil {
+		t.Errorf("expected logs to open, got %v", err)
+	}
+	if !mock.CalledWith([]string{"gt", "log", "--agent", "perch/witness", "-f"}) {
+		t.Errorf("expected gt log to run, got %v", mock.Calls())
+	}
+}
+
+func TestReadOnlyDisablesActions(t *testing.T) {
+	m, mock := createTestModel(t)
+	cfg := config.Default()
+	cfg.ReadOnly = true
+	if err := m.applySettings(cfg); err != nil {
+		t.Fatal(err)
+	}
+	m.sidebar.Section = SectionRigs
+
+	m, _ = sendKey(m, "b")
+	if m.confirmDialog != nil || mock.Called() {
+		t.Error("expected boot to do nothing")
+	}
+	if m.statusMessage == nil || !strings.Contains(m.statusMessage.Text, "Read-only") {
+		t.Errorf("expected a read-only status, got %+v", m.statusMessage)
+	}
+	m, _ = sendKey(m, "d")
+	if m.confirmDialog != nil {
+		t.Error("expected delete rig to be disabled")
+	}
+
+	// e still cycles the beads status filter
+	m.sidebar.Section = SectionBeads
+	before := m.sidebar.BeadsStatusFilter
+	m, _ = sendKey(m, "e")
+	if m.sidebar.BeadsStatusFilter == before {
+		t.Error("expected filters to keep working")
+	}
+
+	hints := strings.Join(m.footerHints(), " | ")
+	if strings.Contains(hints, "new work") || strings.Contains(hints, "boot") || !strings.Contains(hints, "help") {
+		t.Errorf("expected only non-action hints, got %q", hints)
+	}
+	help := strings.Join(m.helpKeymapLines(), "\n")
+	if strings.Contains(help, "Close bead") || strings.Contains(help, "Sling work") || !strings.Contains(help, "Open filter dialog") {
+		t.Errorf("expected action keys left out of help, got:\n%s", help)
+	}
+	for _, item := range m.paletteActions() {
+		if item.label == "Create work" || item.label == "Add rig" {
+			t.Errorf("expected no actions in the palette, got %q", item.label)
+		}
+	}
+	if !strings.Contains(m.View(), "READ-ONLY") {
+		t.Error("expected a READ-ONLY badge")
+	}
+}
+
+func TestReadOnlyHidesOperatorControls(t *testing.T) {
+	state := &OperatorState{Subsystems: []SubsystemHealth{{Subsystem: "deacon", Name: "Deacon", Status: SubsystemError}}}
+	if out := RenderOperatorDetails(state, 0, 80, false); !strings.Contains(out, "Controls") {
+		t.Fatalf("expected controls, got:\n%s", out)
+	}
+	if out := RenderOperatorDetails(state, 0, 80, true); strings.Contains(out, "Controls") || strings.Contains(out, "[b]") {
+		t.Errorf("expected no controls in read-only mode, got:\n%s", out)
+	}
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"path/filepath"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// newLoader creates a loader for a town root, over SSH when the dashboard
+// watches a remote town.
+func (m Model) newLoader(root string) *data.Loader {
+	if m.remote != "" {
+		return data.NewRemoteLoader(m.remote, root)
+	}
+	return data.NewLoader(root)
+}
+
+// newStore creates the data store for a town root.
+func (m Model) newStore(root string) *data.Store {
+	return data.NewStoreWithLoader(m.newLoader(root))
+}
+
+// dashboardStore returns the store the dashboard loads a town root with,
+// recording snapshot history. A store the towns overview already loads the
+// town with is reused rather than loading the town twice.
+func (m Model) dashboardStore(root string, existing *data.Store) *data.Store {
+	store := existing
+	if store == nil {
+		store = m.newStore(root)
+	}
+	town := filepath.Clean(root)
+	if m.remote != "" {
+		town = m.remote + ":" + town
+	}
+	if dir, err := data.DefaultHistoryDir(town); err == nil {
+		store.SetHistory(data.NewHistory(dir))
+	}
+	return store
+}
+
+// newActionRunner creates the action runner for a town root. Actions on a
+// remote town run over the same SSH connection as loading, and files they
+// write directly go through it too.
+func (m Model) newActionRunner(root string) *ActionRunner {
+	if m.remote != "" {
+		runner := data.NewSSHRunner(m.remote)
+		return &ActionRunner{TownRoot: root, Runner: runner, FS: data.RemoteFS{Runner: runner}}
+	}
+	return NewActionRunner(root)
+}
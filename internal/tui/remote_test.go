@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/config"
+)
+
+func TestRemoteTownRunsOverSSH(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := config.Default()
+	cfg.Remote = "deploy@build-01"
+	cfg.TownRoot = "/srv/gt"
+
+	m := NewWithConfig(cfg, nil)
+	if m.setupWizard != nil {
+		t.Fatal("expected no setup wizard for a remote town")
+	}
+	runner, ok := m.actionRunner.Runner.(*data.SSHRunner)
+	if !ok || runner.Host != "deploy@build-01" || m.actionRunner.TownRoot != "/srv/gt" {
+		t.Errorf("expected actions over ssh in /srv/gt, got %#v", m.actionRunner)
+	}
+	if _, ok := m.actionRunner.FS.(data.RemoteFS); !ok {
+		t.Errorf("expected actions to write files over ssh, got %#v", m.actionRunner.FS)
+	}
+	if loader := m.newLoader(m.townRoot); loader.FS == nil {
+		t.Error("expected loaders to read files over ssh")
+	}
+	if towns := m.townRegistry(); towns[0].Root != "deploy@build-01:/srv/gt" {
+		t.Errorf("expected the overview to show the host, got %q", towns[0].Root)
+	}
+
+	// Registered towns are local
+	m.switchTown(t.TempDir(), nil)
+	if _, ok := m.actionRunner.Runner.(*actionRunner); !ok || m.remote != "" {
+		t.Errorf("expected local actions after switching, got %#v", m.actionRunner.Runner)
+	}
+	if m.actionRunner.FS != nil {
+		t.Errorf("expected local file writes after switching, got %#v", m.actionRunner.FS)
+	}
+}
@@ -0,0 +1,456 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// searchMaxResults caps how many results the search overlay lists at once.
+const searchMaxResults = 8
+
+// searchField is one searchable string of an entity.
+type searchField struct {
+	name   string // Shown before snippets, e.g. "body"
+	text   string
+	lower  []rune
+	weight int  // Added to the score of matches in this field
+	long   bool // Substring matches only; nearly any query is a subsequence of a long body
+}
+
+// searchDoc is an indexed entity. Field 0 is the title shown in results.
+type searchDoc struct {
+	kind   string
+	target entityRef
+	fields []searchField
+}
+
+func (d *searchDoc) add(name, text string, weight int, long bool) {
+	if text == "" {
+		return
+	}
+	d.fields = append(d.fields, searchField{name: name, text: text, lower: lowerRunes(text), weight: weight, long: long})
+}
+
+// searchHit is a ranked match with the matched rune positions of each field.
+type searchHit struct {
+	doc   *searchDoc
+	score int
+	marks [][]int
+}
+
+// buildSearchIndex indexes every entity in the snapshot. Searching runs
+// against the index in memory, so typing never shells out.
+func buildSearchIndex(snap *data.Snapshot) []searchDoc {
+	if snap == nil {
+		return nil
+	}
+
+	var docs []searchDoc
+	if snap.Town != nil {
+		for _, r := range snap.Town.Rigs {
+			d := searchDoc{kind: "rig", target: entityRef{SectionRigs, r.Name, false}}
+			d.add("", r.Name, 30, false)
+			docs = append(docs, d)
+		}
+		for _, a := range snap.Town.Agents {
+			d := searchDoc{kind: "agent", target: entityRef{SectionAgents, a.Address, false}}
+			d.add("", a.Address, 30, false)
+			d.add("role", a.Role, 5, false)
+			docs = append(docs, d)
+		}
+	}
+	for _, issue := range snap.Issues {
+		d := searchDoc{kind: "bead", target: entityRef{SectionBeads, issue.ID, false}}
+		d.add("", issue.Title, 30, false)
+		d.add("id", issue.ID, 20, false)
+		d.add("labels", strings.Join(issue.Labels, ", "), 10, false)
+		d.add("description", issue.Description, 0, true)
+		docs = append(docs, d)
+	}
+	for _, mail := range snap.Mail {
+		d := searchDoc{kind: "mail", target: entityRef{SectionMail, mail.ID, false}}
+		d.add("", mail.Subject, 30, false)
+		d.add("from", mail.From, 5, false)
+		d.add("body", mail.Body, 0, true)
+		docs = append(docs, d)
+	}
+	for _, c := range snap.Convoys {
+		d := searchDoc{kind: "convoy", target: entityRef{SectionConvoys, c.ID, false}}
+		d.add("", c.Title, 30, false)
+		d.add("id", c.ID, 20, false)
+		docs = append(docs, d)
+	}
+	for _, c := range snap.ClosedConvoys {
+		d := searchDoc{kind: "landed convoy", target: entityRef{SectionConvoys, c.ID, true}}
+		d.add("", c.Title, 30, false)
+		d.add("id", c.ID, 20, false)
+		docs = append(docs, d)
+	}
+
+	rigs := make([]string, 0, len(snap.MergeQueues))
+	for rig := range snap.MergeQueues {
+		rigs = append(rigs, rig)
+	}
+	sort.Strings(rigs)
+	for _, rig := range rigs {
+		for _, mr := range snap.MergeQueues[rig] {
+			d := searchDoc{kind: "MR", target: entityRef{SectionMergeQueue, mr.ID, false}}
+			d.add("", mr.Title, 20, false)
+			d.add("branch", mr.Branch, 30, false)
+			d.add("id", mr.ID, 20, false)
+			d.add("worker", mr.Worker, 5, false)
+			docs = append(docs, d)
+		}
+	}
+	return docs
+}
+
+// matchDoc scores doc against the query terms. Every term must match
+// some field, as a substring or, in short fields, as a subsequence.
+func matchDoc(doc *searchDoc, terms [][]rune) (searchHit, bool) {
+	hit := searchHit{doc: doc, marks: make([][]int, len(doc.fields))}
+	for _, term := range terms {
+		best, bestField := 0, -1
+		var bestMarks []int
+		for i, f := range doc.fields {
+			score, marks, ok := matchField(term, f)
+			if ok && (bestField < 0 || score > best) {
+				best, bestField, bestMarks = score, i, marks
+			}
+		}
+		if bestField < 0 {
+			return hit, false
+		}
+		hit.score += best
+		hit.marks[bestField] = append(hit.marks[bestField], bestMarks...)
+	}
+	return hit, true
+}
+
+func matchField(term []rune, f searchField) (int, []int, bool) {
+	if i := indexRunes(f.lower, term); i >= 0 {
+		score := 10*len(term) + f.weight
+		if i == 0 || isWordBoundary(f.lower[i-1]) {
+			score += 10
+		}
+		marks := make([]int, len(term))
+		for j := range term {
+			marks[j] = i + j
+		}
+		return score, marks, true
+	}
+	if f.long {
+		return 0, nil, false
+	}
+	score, marks, ok := fuzzyMatch(term, f.lower)
+	return score/2 + f.weight, marks, ok
+}
+
+// fuzzyScore reports whether every rune of pattern appears in text in
+// order, ignoring case. Matches at word starts and runs of consecutive
+// runes score higher, so "sr" prefers "Stop refinery" to "Restart".
+func fuzzyScore(pattern, text string) (int, bool) {
+	score, _, ok := fuzzyMatch(lowerRunes(pattern), lowerRunes(text))
+	return score, ok
+}
+
+// fuzzyMatch matches lowercased runes and returns the matched positions.
+func fuzzyMatch(p, t []rune) (int, []int, bool) {
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+
+	var marks []int
+	score, last := 0, -1
+	for ti := 0; ti < len(t) && len(marks) < len(p); ti++ {
+		if t[ti] != p[len(marks)] {
+			continue
+		}
+		score++
+		if ti == 0 || isWordBoundary(t[ti-1]) {
+			score += 8
+		}
+		if last >= 0 && ti == last+1 {
+			score += 5
+		} else if last >= 0 {
+			score -= min(ti-last-1, 5)
+		}
+		last = ti
+		marks = append(marks, ti)
+	}
+	if len(marks) < len(p) {
+		return 0, nil, false
+	}
+	return score, marks, true
+}
+
+func isWordBoundary(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("/-_.:[", r)
+}
+
+// lowerRunes lowercases rune by rune, so positions line up with []rune(s).
+func lowerRunes(s string) []rune {
+	r := []rune(s)
+	for i := range r {
+		r[i] = unicode.ToLower(r[i])
+	}
+	return r
+}
+
+func indexRunes(s, sub []rune) int {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		match := true
+		for j := range sub {
+			if s[i+j] != sub[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// SearchOverlay is the global search over the current snapshot.
+type SearchOverlay struct {
+	input     textinput.Model
+	docs      []searchDoc
+	hits      []searchHit
+	Selection int
+}
+
+// NewSearchOverlay creates a search over docs.
+func NewSearchOverlay(docs []searchDoc) *SearchOverlay {
+	ti := textinput.New()
+	ti.Placeholder = "Search beads, mail, convoys, MRs, agents..."
+	ti.CharLimit = 100
+	ti.Width = 60
+	ti.Focus()
+	return &SearchOverlay{input: ti, docs: docs}
+}
+
+// Selected returns the highlighted result.
+func (o *SearchOverlay) Selected() (searchHit, bool) {
+	if o.Selection < 0 || o.Selection >= len(o.hits) {
+		return searchHit{}, false
+	}
+	return o.hits[o.Selection], true
+}
+
+// search reruns the query over the index, best match first.
+func (o *SearchOverlay) search() {
+	o.hits = o.hits[:0]
+	o.Selection = 0
+	var terms [][]rune
+	for _, t := range strings.Fields(o.input.Value()) {
+		terms = append(terms, lowerRunes(t))
+	}
+	if len(terms) == 0 {
+		return
+	}
+
+	for i := range o.docs {
+		if hit, ok := matchDoc(&o.docs[i], terms); ok {
+			o.hits = append(o.hits, hit)
+		}
+	}
+	sort.SliceStable(o.hits, func(i, j int) bool {
+		return o.hits[i].score > o.hits[j].score
+	})
+}
+
+// handleSearchKey handles key presses while the search overlay is open.
+func (m Model) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.search = nil
+		return m, nil
+
+	case "up", "ctrl+p":
+		if m.search.Selection > 0 {
+			m.search.Selection--
+		}
+		return m, nil
+
+	case "down", "ctrl+n":
+		if m.search.Selection < len(m.search.hits)-1 {
+			m.search.Selection++
+		}
+		return m, nil
+
+	case "enter":
+		hit, ok := m.search.Selected()
+		m.search = nil
+		if !ok {
+			return m, nil
+		}
+		return m, m.jumpToEntity(hit.doc.target, hit.doc.fields[0].text)
+	}
+
+	m.search.input, _ = m.search.input.Update(msg)
+	m.search.search()
+	return m, nil
+}
+
+// renderSearch renders the search overlay centered over the screen.
+func (m Model) renderSearch() string {
+	o := m.search
+	width := 90
+	if width > m.width-4 {
+		width = m.width - 4
+	}
+	// Horizontal padding takes 4 columns
+	inner := width - 4
+	kindWidth := 14
+
+	var b strings.Builder
+	b.WriteString(helpTitleStyle.Render("Search"))
+	b.WriteString("\n\n")
+	b.WriteString(o.input.View())
+	b.WriteString("\n\n")
+
+	switch {
+	case strings.TrimSpace(o.input.Value()) == "":
+		b.WriteString(mutedStyle.Render(fmt.Sprintf("  %d items indexed", len(o.docs))))
+		b.WriteString("\n")
+	case len(o.hits) == 0:
+		b.WriteString(mutedStyle.Render("  No matches"))
+		b.WriteString("\n")
+	}
+
+	// Scroll so the selection stays in view
+	start := 0
+	if o.Selection >= searchMaxResults {
+		start = o.Selection - searchMaxResults + 1
+	}
+	end := min(start+searchMaxResults, len(o.hits))
+	for i := start; i < end; i++ {
+		hit := o.hits[i]
+		base := itemStyle
+		prefix := "  "
+		if i == o.Selection {
+			base = selectedItemStyle
+			prefix = "> "
+		}
+
+		title := hit.doc.fields[0]
+		text, marks := clipRunes(title.text, hit.marks[0], 0, inner-kindWidth-2)
+		line := base.Render(prefix) + highlightMatches(text, marks, base)
+		gap := inner - kindWidth - lipgloss.Width(line)
+		if gap < 1 {
+			gap = 1
+		}
+		b.WriteString(line + strings.Repeat(" ", gap) + mutedStyle.Render(truncate(hit.doc.kind, kindWidth)))
+		b.WriteString("\n")
+
+		// Show where the rest of the query matched
+		for f := 1; f < len(hit.doc.fields); f++ {
+			if len(hit.marks[f]) == 0 {
+				continue
+			}
+			field := hit.doc.fields[f]
+			label := "    " + field.name + ": "
+			from := max(0, slices.Min(hit.marks[f])-(inner-len(label))/3)
+			text, marks := clipRunes(field.text, hit.marks[f], from, inner-len(label))
+			b.WriteString(mutedStyle.Render(label) + highlightMatches(text, marks, mutedStyle))
+			b.WriteString("\n")
+			break
+		}
+	}
+	if len(o.hits) > end {
+		b.WriteString(mutedStyle.Render(fmt.Sprintf("  ... %d more", len(o.hits)-end)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("↑/↓: select • enter: jump • esc: close"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(1, 2).
+		Width(width).
+		Render(b.String())
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// clipRunes cuts text to width runes starting at from, flattening line
+// breaks, and shifts marks to match. Cut ends are shown as "…".
+func clipRunes(text string, marks []int, from, width int) (string, []int) {
+	r := []rune(text)
+	for i, c := range r {
+		if c == '\n' || c == '\r' || c == '\t' {
+			r[i] = ' '
+		}
+	}
+	if width < 1 {
+		return "", nil
+	}
+
+	var out []rune
+	offset := 0
+	if from > 0 {
+		out = append(out, '…')
+		offset = 1
+		width--
+	}
+	end := min(from+width, len(r))
+	if end < len(r) {
+		end--
+	}
+	out = append(out, r[from:end]...)
+	if end < len(r) {
+		out = append(out, '…')
+	}
+
+	var shifted []int
+	for _, p := range marks {
+		if p >= from && p < end {
+			shifted = append(shifted, p-from+offset)
+		}
+	}
+	return string(out), shifted
+}
+
+// highlightMatches renders text in base with the marked runes emphasized.
+func highlightMatches(text string, marks []int, base lipgloss.Style) string {
+	marked := make(map[int]bool, len(marks))
+	for _, p := range marks {
+		marked[p] = true
+	}
+
+	var b, run strings.Builder
+	inMatch := false
+	flush := func() {
+		if run.Len() == 0 {
+			return
+		}
+		if inMatch {
+			b.WriteString(searchMatchStyle.Render(run.String()))
+		} else {
+			b.WriteString(base.Render(run.String()))
+		}
+		run.Reset()
+	}
+	for i, c := range []rune(text) {
+		if marked[i] != inMatch {
+			flush()
+			inMatch = marked[i]
+		}
+		run.WriteRune(c)
+	}
+	flush()
+	return b.String()
+}
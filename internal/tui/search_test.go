@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+func searchTestSnapshot() *data.Snapshot {
+	return &data.Snapshot{
+		Town: &data.TownStatus{
+			Rigs:   []data.Rig{{Name: "perch"}},
+			Agents: []data.Agent{{Name: "ace", Address: "perch/polecats/ace", Role: "polecat"}},
+		},
+		Issues: []data.Issue{
+			{ID: "pe-1", Title: "Retry webhook delivery", Description: "Refresh fails when the tmux socket is gone."},
+			{ID: "pe-2", Title: "Refresh loop stalls", Labels: []string{"perf"}},
+		},
+		Mail: []data.MailMessage{
+			{ID: "m-1", Subject: "Handoff", From: "perch/witness", Body: "Picked up the\nwebhook retries from ace."},
+		},
+		MergeQueues: map[string][]data.MergeRequest{
+			"perch": {{ID: "mr-7", Title: "Speed up loads", Branch: "polecat/ace/fast-loads"}},
+		},
+	}
+}
+
+func searchFor(query string) *SearchOverlay {
+	o := NewSearchOverlay(buildSearchIndex(searchTestSnapshot()))
+	o.input.SetValue(query)
+	o.search()
+	return o
+}
+
+func hitIDs(o *SearchOverlay) []string {
+	var ids []string
+	for _, h := range o.hits {
+		ids = append(ids, h.doc.target.id)
+	}
+	return ids
+}
+
+func TestSearchRanksTitlesFirst(t *testing.T) {
+	o := searchFor("refresh")
+	if got := strings.Join(hitIDs(o), " "); got != "pe-2 pe-1" {
+		t.Errorf("expected title match before description match, got %q", got)
+	}
+
+	// Terms may match different fields
+	if got := strings.Join(hitIDs(searchFor("loop perf")), " "); got != "pe-2" {
+		t.Errorf("expected title and label terms to match pe-2, got %q", got)
+	}
+	if got := strings.Join(hitIDs(searchFor("fast-loads")), " "); got != "mr-7" {
+		t.Errorf("expected MR branch match, got %q", got)
+	}
+}
+
+func TestSearchLongFieldsNeedSubstrings(t *testing.T) {
+	if got := strings.Join(hitIDs(searchFor("rtwd")), " "); got != "pe-1" {
+		t.Errorf("expected a fuzzy title match, got %q", got)
+	}
+	// A subsequence of the pe-1 description, but not a substring
+	if ids := hitIDs(searchFor("tmxsck")); len(ids) != 0 {
+		t.Errorf("expected no fuzzy matches in descriptions, got %v", ids)
+	}
+}
+
+func TestSearchHighlightsSnippet(t *testing.T) {
+	m := NewTestModel(t)
+	m.ready = true
+	m.width, m.height = 120, 40
+	m.applySnapshot(searchTestSnapshot())
+
+	m = pressKey(t, m, "/")
+	if m.search == nil {
+		t.Fatal("expected / to open search")
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("retries")})
+	m = updated.(Model)
+
+	view := m.View()
+	if !strings.Contains(view, "Handoff") || !strings.Contains(view, "body:") || !strings.Contains(view, "webhook retries") {
+		t.Errorf("expected the mail body snippet in results, got:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.search != nil {
+		t.Error("expected search to close")
+	}
+	if m.sidebar.Section != SectionMail || m.sidebar.SelectedItem().ID() != "m-1" {
+		t.Errorf("expected m-1 selected in Mail, got %s", m.sidebar.Section)
+	}
+}
+
+func TestClipRunes(t *testing.T) {
+	text, marks := clipRunes("the webhook\nretries", []int{12, 13}, 4, 10)
+	if text != "…webhook …" {
+		t.Errorf("unexpected clip %q", text)
+	}
+	if len(marks) != 0 {
+		t.Errorf("expected marks past the cut dropped, got %v", marks)
+	}
+
+	text, marks = clipRunes("webhook retries", []int{8, 9}, 0, 20)
+	if text != "webhook retries" || len(marks) != 2 || marks[0] != 8 {
+		t.Errorf("unexpected clip %q %v", text, marks)
+	}
+}
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/config"
+	"github.com/andyrewlee/perch/internal/notify"
+)
+
+// ReloadConfigMsg asks the TUI to reread its config file, e.g. on SIGHUP.
+type ReloadConfigMsg struct{}
+
+// configReloadedMsg carries the result of rereading the config file.
+type configReloadedMsg struct {
+	cfg config.Config
+	err error
+}
+
+// applySettings makes cfg the active settings. The town root is left alone;
+// switching towns goes through the attach dialog. Invalid keybindings leave
+// every setting unchanged.
+func (m *Model) applySettings(cfg config.Config) error {
+	keys, err := NewKeyMap(cfg.Keys)
+	if err != nil {
+		return err
+	}
+	m.keys = keys
+	m.settings = cfg
+	m.refreshInterval = cfg.RefreshInterval
+	if m.store != nil {
+		m.store.SetLifecycleEvents(cfg.LifecycleEvents)
+	}
+	m.applySchedule()
+	if m.sidebar != nil {
+		m.sidebar.ActivityMaxEvents = cfg.ActivityMaxEvents
+		m.sidebar.ReadOnly = cfg.ReadOnly
+		m.sidebar.Town = m.townQualifier()
+		if m.store != nil {
+			m.sidebar.CommandStats = m.store.CommandStats()
+		}
+	}
+	if m.actionRunner != nil {
+		m.actionRunner.ReadOnly = cfg.ReadOnly
+	}
+	m.nudges = nil
+	if len(cfg.Nudges) > 0 {
+		for _, n := range cfg.Nudges {
+			m.nudges = append(m.nudges, PresetNudge{Label: n.Label, Message: n.Message})
+		}
+		m.nudges = append(m.nudges, PresetNudge{"Custom...", ""})
+	}
+	notifyCfg := cfg.Notify
+	notifyCfg.StaleMRThreshold = m.staleMRThreshold()
+	if m.notifier != nil {
+		// Keep the dedup history so a reload doesn't repeat active alerts
+		m.notifier.SetConfig(notifyCfg)
+	} else {
+		m.notifier = newNotifier(notifyCfg)
+	}
+	return nil
+}
+
+// reloadConfigCmd rereads the config file off the UI goroutine.
+func (m Model) reloadConfigCmd() tea.Cmd {
+	if m.reloadConfig == nil {
+		return nil
+	}
+	reload := m.reloadConfig
+	return func() tea.Msg {
+		cfg, err := reload()
+		return configReloadedMsg{cfg: cfg, err: err}
+	}
+}
+
+// handleConfigReloaded applies reloaded settings, keeping the old ones on error.
+func (m Model) handleConfigReloaded(msg configReloadedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.setStatus("Config not reloaded: "+msg.err.Error(), true)
+		return m, statusExpireCmd(8 * time.Second)
+	}
+	if err := m.applySettings(msg.cfg); err != nil {
+		m.setStatus("Config not reloaded: "+err.Error(), true)
+		return m, statusExpireCmd(8 * time.Second)
+	}
+	text := "Config reloaded"
+	if msg.cfg.TownRoot != m.townRoot {
+		text += " (town_root takes effect on restart)"
+	}
+	m.setStatus(text, false)
+	return m, tea.Batch(statusExpireCmd(3*time.Second), m.syncWatchCmd())
+}
+
+// applySchedule sets each source's reload interval around the refresh
+// interval, with the live sources at the store interval. While files are
+// watched, the watched sources only poll at the fallback interval.
+func (m *Model) applySchedule() {
+	schedule := data.DefaultSchedule(m.refreshInterval).WithLiveInterval(m.settings.StoreRefreshInterval)
+	if m.watcher != nil {
+		schedule = schedule.Watching(m.settings.WatchFallbackInterval)
+	}
+	if m.store != nil {
+		m.store.SetSchedule(schedule)
+	}
+	if m.sidebar != nil {
+		m.sidebar.Schedule = schedule
+	}
+}
+
+// defaultKeys backs keyMap for models built without settings.
+var defaultKeys = DefaultKeyMap()
+
+// keyMap returns the active keybindings.
+func (m Model) keyMap() KeyMap {
+	if m.keys.byKey == nil {
+		return defaultKeys
+	}
+	return m.keys
+}
+
+// loadTimeout bounds a full data load.
+func (m Model) loadTimeout() time.Duration {
+	if m.settings.LoadTimeout > 0 {
+		return m.settings.LoadTimeout
+	}
+	return config.Default().LoadTimeout
+}
+
+// staleMRThreshold is the MR age at which an idle refinery counts as stalled.
+func (m Model) staleMRThreshold() time.Duration {
+	if m.settings.StaleMRThreshold > 0 {
+		return m.settings.StaleMRThreshold
+	}
+	return config.Default().StaleMRThreshold
+}
+
+// presetNudges returns the configured nudges, or PresetNudges by default.
+func (m Model) presetNudges() []PresetNudge {
+	if len(m.nudges) > 0 {
+		return m.nudges
+	}
+	return PresetNudges
+}
+
+// newNotifier creates a notifier writing to the terminal.
+// It returns nil when no delivery method is configured.
+func newNotifier(cfg notify.Config) *notify.Notifier {
+	if !cfg.Enabled() {
+		return nil
+	}
+	// Stdout belongs to the renderer; stderr reaches the same terminal
+	return notify.New(cfg, os.Stderr)
+}
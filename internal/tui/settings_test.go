@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/config"
+)
+
+func pressCtrlR(t *testing.T, m Model) Model {
+	t.Helper()
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	return runCmd(t, updated.(Model), cmd)
+}
+
+func TestReloadConfig(t *testing.T) {
+	m := NewTestModel(t)
+
+	cfg := config.Default()
+	cfg.TownRoot = m.townRoot
+	cfg.RefreshInterval = 45 * time.Second
+	cfg.StoreRefreshInterval = 20 * time.Second
+	cfg.LoadTimeout = 2 * time.Minute
+	cfg.ActivityMaxEvents = 5
+	cfg.StaleMRThreshold = 10 * time.Minute
+	cfg.Nudges = []config.Nudge{{Label: "Ping", Message: "Still there?"}}
+	m.reloadConfig = func() (config.Config, error) { return cfg, nil }
+
+	m = pressCtrlR(t, m)
+
+	if m.statusMessage == nil || m.statusMessage.Text != "Config reloaded" {
+		t.Fatalf("expected reload status, got %+v", m.statusMessage)
+	}
+	if m.refreshInterval != 45*time.Second || m.loadTimeout() != 2*time.Minute {
+		t.Errorf("expected new intervals, got refresh %s load %s", m.refreshInterval, m.loadTimeout())
+	}
+	if every := m.store.Schedule().Policy(data.SourceTownStatus).Interval; every != 20*time.Second {
+		t.Errorf("expected town status every store interval, got %s", every)
+	}
+	if m.sidebar.ActivityMaxEvents != 5 || m.staleMRThreshold() != 10*time.Minute {
+		t.Errorf("expected new limits, got %d events, %s threshold", m.sidebar.ActivityMaxEvents, m.staleMRThreshold())
+	}
+
+	nudges := m.presetNudges()
+	if len(nudges) != 2 || nudges[0].Label != "Ping" || nudges[1].Message != "" {
+		t.Errorf("expected configured nudge plus custom entry, got %+v", nudges)
+	}
+}
+
+func TestReloadConfigErrorKeepsSettings(t *testing.T) {
+	m := NewTestModel(t)
+	m.reloadConfig = func() (config.Config, error) {
+		return config.Config{}, errors.New("config.toml: line 3: refresh.intreval: unknown setting")
+	}
+
+	m = pressCtrlR(t, m)
+
+	if m.statusMessage == nil || !m.statusMessage.IsError || !strings.Contains(m.statusMessage.Text, "line 3") {
+		t.Fatalf("expected reload error status, got %+v", m.statusMessage)
+	}
+	if m.refreshInterval != DefaultRefreshInterval || len(m.presetNudges()) != len(PresetNudges) {
+		t.Errorf("expected previous settings kept, got refresh %s", m.refreshInterval)
+	}
+}
+
+func TestReloadConfigTownRootNeedsRestart(t *testing.T) {
+	m := NewTestModel(t)
+	townRoot := m.townRoot
+	cfg := config.Default()
+	cfg.TownRoot = "/elsewhere"
+
+	updated, _ := m.Update(configReloadedMsg{cfg: cfg})
+	m = updated.(Model)
+
+	if m.townRoot != townRoot {
+		t.Errorf("expected town root unchanged until restart, got %q", m.townRoot)
+	}
+	if m.statusMessage == nil || !strings.Contains(m.statusMessage.Text, "restart") {
+		t.Errorf("expected restart hint, got %+v", m.statusMessage)
+	}
+}
+
+func TestReloadConfigWithoutFile(t *testing.T) {
+	m := NewTestModel(t)
+	m = pressCtrlR(t, m)
+	if m.statusMessage == nil || !m.statusMessage.IsError {
+		t.Errorf("expected error status without a reload function, got %+v", m.statusMessage)
+	}
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// sectionSources lists the data sources each section shows. Merge queues
+// also depend on each rig's queue, and convoy history on closed convoys.
+var sectionSources = map[SidebarSection][]string{
+	SectionIdentity:   {data.SourceTownStatus, data.SourceIssues},
+	SectionRigs:       {data.SourceTownStatus},
+	SectionConvoys:    {data.SourceConvoys, data.SourceConvoyStatuses},
+	SectionMergeQueue: {data.SourceTownStatus},
+	SectionAgents:     {data.SourceTownStatus},
+	SectionMail:       {data.SourceMail},
+	SectionLifecycle:  {data.SourceLifecycle},
+	SectionWorktrees:  {data.SourceWorktrees},
+	SectionPlugins:    {data.SourcePlugins},
+	SectionBeads:      {data.SourceIssues},
+	SectionOperator:   {data.SourceTownStatus, data.SourceDoctor},
+}
+
+// StaleSince reports whether a section shows data kept from an earlier
+// load because a source failed, and when the oldest of that data loaded.
+func (s *SidebarState) StaleSince(sec SidebarSection) (time.Time, bool) {
+	sources := sectionSources[sec]
+	switch {
+	case sec == SectionConvoys && s.ShowConvoyHistory:
+		sources = []string{data.SourceClosedConvoys}
+	case sec == SectionMergeQueue:
+		sources = append(sources[:len(sources):len(sources)], data.SourceMergeQueue)
+	}
+	return staleSince(s.Stale, sources...)
+}
+
+// staleSince returns when the oldest data kept for any of sources last
+// loaded. SourceMergeQueue stands for every rig's queue.
+func staleSince(stale map[string]time.Time, sources ...string) (time.Time, bool) {
+	var oldest time.Time
+	for source, at := range stale {
+		relevant := false
+		for _, want := range sources {
+			relevant = relevant || source == want ||
+				want == data.SourceMergeQueue && strings.HasPrefix(source, data.SourceMergeQueue+"_")
+		}
+		if relevant && (oldest.IsZero() || at.Before(oldest)) {
+			oldest = at
+		}
+	}
+	return oldest, !oldest.IsZero()
+}
+
+// renderStaleBadge renders when a stale section's data last loaded.
+func renderStaleBadge(since time.Time) string {
+	label := "stale since " + since.Format("15:04")
+	if now().Sub(since) >= 24*time.Hour {
+		label = "stale since " + since.Format("Jan 2")
+	}
+	return warningStyle.Render("⚠ " + label)
+}
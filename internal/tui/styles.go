@@ -55,6 +55,10 @@ var (
 
 	dimSelectedStyle = lipgloss.NewStyle().
 				Foreground(muted)
+
+	searchMatchStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FFCC00")).
+				Bold(true)
 )
 
 // Help overlay styles
@@ -118,6 +122,22 @@ var (
 
 	hudDisconnectedStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#666666"))
+
+	hudHistoryStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FF9900")).
+			Bold(true)
+
+	hudDryRunStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#000000")).
+			Background(lipgloss.Color("#FF9900")).
+			Bold(true).
+			Padding(0, 1)
+
+	hudReadOnlyStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FFFFFF")).
+				Background(lipgloss.Color("#5F5FAF")).
+				Bold(true).
+				Padding(0, 1)
 )
 
 // Merge queue status styles
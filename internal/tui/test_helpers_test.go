@@ -2,8 +2,12 @@ package tui
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/journal"
 )
 
 // NewTestModel creates a Model with a temporary town root for testing.
@@ -17,5 +21,12 @@ func NewTestModel(t *testing.T) Model {
 	if err := os.MkdirAll(tmpDir+"/mayor", 0755); err != nil {
 		t.Fatalf("failed to create test town: %v", err)
 	}
-	return NewWithTownRoot(tmpDir)
+	m := NewWithTownRoot(tmpDir)
+	// Keep snapshot history out of the real home directory
+	m.store.History = data.NewHistory(filepath.Join(tmpDir, "history"))
+	m.journal = journal.New(filepath.Join(tmpDir, "actions.jsonl"))
+	// Never notify the developer's terminal from tests
+	m.notifier = nil
+	m.statusMessage = nil
+	return m
 }
@@ -4,6 +4,8 @@ import (
 	"os"
 	"testing"
 	"time"
+
+	"github.com/andyrewlee/perch/data"
 )
 
 func TestTickCmdReturnsNilWhenDisabled(t *testing.T) {
@@ -48,6 +50,7 @@ func TestNewWithTownRootHasRefreshInterval(t *testing.T) {
 	}
 
 	m := NewWithTownRoot(tmpDir)
+	m.store.History = data.NewHistory(tmpDir + "/history")
 	// Should not be in setup mode
 	if m.setupWizard != nil {
 		t.Errorf("NewWithTownRoot with existing town should not show setup wizard")
@@ -0,0 +1,220 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// How far { and } and ( and ) move through history. Snapshots are
+// recorded at irregular intervals, so jumps go by time, not entry count.
+const (
+	timeTravelJump     = time.Minute
+	timeTravelHourJump = time.Hour
+)
+
+// timeTravelState tracks browsing through recorded snapshot history.
+// While active, every panel renders the historical snapshot and refreshes
+// only update the live snapshot that is restored on exit.
+type timeTravelState struct {
+	entries []data.HistoryEntry
+	index   int            // Entry currently displayed
+	live    *data.Snapshot // Latest live snapshot, restored on exit
+}
+
+// Current returns the entry being displayed.
+func (t *timeTravelState) Current() data.HistoryEntry {
+	return t.entries[t.index]
+}
+
+// historyEntriesMsg signals that the history index has been loaded.
+type historyEntriesMsg struct {
+	entries []data.HistoryEntry
+	err     error
+}
+
+// historySnapshotMsg signals that a historical snapshot has been reconstructed.
+type historySnapshotMsg struct {
+	index    int
+	snapshot *data.Snapshot
+	err      error
+}
+
+// loadHistoryEntriesCmd lists recorded snapshots.
+func (m Model) loadHistoryEntriesCmd() tea.Cmd {
+	history := m.store.History
+	return func() tea.Msg {
+		entries, err := history.Entries()
+		return historyEntriesMsg{entries: entries, err: err}
+	}
+}
+
+// loadHistorySnapshotCmd reconstructs the snapshot at index.
+func (m Model) loadHistorySnapshotCmd(index int) tea.Cmd {
+	history := m.store.History
+	entry := m.timeTravel.entries[index]
+	return func() tea.Msg {
+		snap, err := history.Load(entry)
+		return historySnapshotMsg{index: index, snapshot: snap, err: err}
+	}
+}
+
+// startTimeTravel enters history browsing, one step behind live.
+func (m Model) startTimeTravel() (tea.Model, tea.Cmd) {
+	if m.store == nil || m.store.History == nil {
+		m.setStatus("Snapshot history is disabled", true)
+		return m, statusExpireCmd(3 * time.Second)
+	}
+	m.setStatus("Loading snapshot history...", false)
+	return m, m.loadHistoryEntriesCmd()
+}
+
+// handleHistoryEntries enters time travel once the history index is loaded.
+func (m Model) handleHistoryEntries(msg historyEntriesMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.setStatus("Loading history failed: "+msg.err.Error(), true)
+		return m, statusExpireCmd(5 * time.Second)
+	}
+	if len(msg.entries) == 0 {
+		m.setStatus("No snapshot history recorded yet", true)
+		return m, statusExpireCmd(3 * time.Second)
+	}
+
+	// The newest entry is the live snapshot, so start one step back
+	index := len(msg.entries) - 1
+	if index > 0 {
+		index--
+	}
+	m.timeTravel = &timeTravelState{
+		entries: msg.entries,
+		index:   index,
+		live:    m.snapshot,
+	}
+	m.setStatus("History: [/] step, {/} minute, (/) hour, esc for live", false)
+	return m, tea.Batch(m.loadHistorySnapshotCmd(index), statusExpireCmd(5*time.Second))
+}
+
+// handleHistorySnapshot renders a reconstructed snapshot if it is still wanted.
+func (m Model) handleHistorySnapshot(msg historySnapshotMsg) (tea.Model, tea.Cmd) {
+	if m.timeTravel == nil || msg.index != m.timeTravel.index {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.setStatus("Loading snapshot failed: "+msg.err.Error(), true)
+		return m, statusExpireCmd(5 * time.Second)
+	}
+	m.applySnapshot(msg.snapshot)
+	return m, nil
+}
+
+// stepTimeTravel moves delta entries through history.
+// Stepping past the newest entry returns to live data.
+func (m Model) stepTimeTravel(delta int) (tea.Model, tea.Cmd) {
+	index := m.timeTravel.index + delta
+	if index >= len(m.timeTravel.entries) {
+		return m.stopTimeTravel()
+	}
+	if index < 0 {
+		index = 0
+	}
+	if index == m.timeTravel.index {
+		m.setStatus("Oldest recorded snapshot", false)
+		return m, statusExpireCmd(2 * time.Second)
+	}
+	m.timeTravel.index = index
+	return m, m.loadHistorySnapshotCmd(index)
+}
+
+// jumpTimeTravel moves to the first entry at least d before (d < 0) or
+// after the displayed one. Jumping past the newest entry returns to live
+// data; jumping before the oldest stops there.
+func (m Model) jumpTimeTravel(d time.Duration) (tea.Model, tea.Cmd) {
+	entries := m.timeTravel.entries
+	index := m.timeTravel.index
+	target := entries[index].At.Add(d)
+	if d < 0 {
+		for index > 0 && entries[index].At.After(target) {
+			index--
+		}
+	} else {
+		for index < len(entries) && entries[index].At.Before(target) {
+			index++
+		}
+	}
+	return m.stepTimeTravel(index - m.timeTravel.index)
+}
+
+// stopTimeTravel leaves history browsing and restores the live snapshot.
+func (m Model) stopTimeTravel() (tea.Model, tea.Cmd) {
+	live := m.timeTravel.live
+	m.timeTravel = nil
+	if live != nil {
+		m.applySnapshot(live)
+	}
+	m.setStatus("Back to live data", false)
+	return m, statusExpireCmd(2 * time.Second)
+}
+
+// handleTimeTravelKey handles keys while browsing history.
+// Navigation keys fall through to the normal handlers; keys that would run
+// actions against historical data are swallowed.
+func (m Model) handleTimeTravelKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	action := m.keyMap().Lookup(msg.String())
+	switch action {
+	case KeyHistoryBack:
+		model, cmd := m.stepTimeTravel(-1)
+		return model, cmd, true
+	case KeyHistoryForward:
+		model, cmd := m.stepTimeTravel(1)
+		return model, cmd, true
+	case KeyHistoryJumpBack:
+		model, cmd := m.jumpTimeTravel(-timeTravelJump)
+		return model, cmd, true
+	case KeyHistoryJumpForward:
+		model, cmd := m.jumpTimeTravel(timeTravelJump)
+		return model, cmd, true
+	case KeyHistoryHourBack:
+		model, cmd := m.jumpTimeTravel(-timeTravelHourJump)
+		return model, cmd, true
+	case KeyHistoryHourForward:
+		model, cmd := m.jumpTimeTravel(timeTravelHourJump)
+		return model, cmd, true
+	}
+
+	// Town map keys are navigation only
+	if m.showTownMap {
+		return m, nil, false
+	}
+
+	if msg.String() == "esc" {
+		model, cmd := m.stopTimeTravel()
+		return model, cmd, true
+	}
+	switch action {
+	case KeyQuit, KeyHelp, KeyNextPanel, KeyPrevPanel, KeyTownMap,
+		KeyUp, KeyDown, KeyLeft, KeyRight,
+		KeySectionIdentity, KeySectionRigs, KeySectionConvoys, KeySectionMergeQueue,
+		KeySectionAgents, KeySectionMail, KeySectionLifecycle, KeySectionWorktrees,
+		KeySectionPlugins, KeySectionAlerts, KeySectionOperator:
+		return m, nil, false
+	}
+
+	m.setStatus("Browsing history (read-only): esc to return to live", true)
+	return m, statusExpireCmd(3 * time.Second), true
+}
+
+// timeTravelLabel describes the displayed history position for the HUD.
+func (m Model) timeTravelLabel() string {
+	if m.timeTravel == nil {
+		return ""
+	}
+	at := m.timeTravel.Current().At
+	format := "15:04:05"
+	if since(at) >= 24*time.Hour {
+		format = "Jan 2 15:04"
+	}
+	return fmt.Sprintf("⏪ %s %d/%d", at.Format(format), m.timeTravel.index+1, len(m.timeTravel.entries))
+}
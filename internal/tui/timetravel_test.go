@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// recordTestHistory records snapshots with 0..n-1 rigs, one minute apart.
+func recordTestHistory(t *testing.T, m Model, n int) {
+	t.Helper()
+	base := time.Date(2026, 1, 8, 16, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		snap := &data.Snapshot{
+			Town:     &data.TownStatus{Name: "test-town"},
+			LoadedAt: base.Add(time.Duration(i) * time.Minute),
+		}
+		for r := 0; r < i; r++ {
+			snap.Town.Rigs = append(snap.Town.Rigs, data.Rig{Name: string(rune('a' + r))})
+		}
+		if err := m.store.History.Record(snap); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+}
+
+// runCmd executes cmd and feeds resulting messages back into the model.
+// Commands that block (status expiry ticks) are skipped.
+func runCmd(t *testing.T, m Model, cmd tea.Cmd) Model {
+	t.Helper()
+	if cmd == nil {
+		return m
+	}
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+	var msg tea.Msg
+	select {
+	case msg = <-done:
+	case <-time.After(50 * time.Millisecond):
+		return m
+	}
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			m = runCmd(t, m, c)
+		}
+		return m
+	}
+	if msg == nil {
+		return m
+	}
+	updated, next := m.Update(msg)
+	return runCmd(t, updated.(Model), next)
+}
+
+func pressKey(t *testing.T, m Model, key string) Model {
+	t.Helper()
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	return runCmd(t, updated.(Model), cmd)
+}
+
+func TestTimeTravel(t *testing.T) {
+	m := NewTestModel(t)
+	m.ready = true
+	m.width, m.height = 120, 40
+	recordTestHistory(t, m, 4)
+
+	live := &data.Snapshot{Town: &data.TownStatus{Name: "live"}}
+	m.applySnapshot(live)
+
+	// [ enters one step behind the newest entry
+	m = pressKey(t, m, "[")
+	if m.timeTravel == nil {
+		t.Fatal("expected time travel mode")
+	}
+	if m.timeTravel.index != 2 {
+		t.Errorf("expected index 2, got %d", m.timeTravel.index)
+	}
+	if got := len(m.snapshot.Town.Rigs); got != 2 {
+		t.Errorf("expected historical snapshot with 2 rigs, got %d", got)
+	}
+	if !strings.Contains(m.renderHUD(), "16:02:00 3/4") {
+		t.Errorf("expected history position in HUD, got %q", m.renderHUD())
+	}
+
+	// { jumps back a minute and clamps to the oldest entry
+	m = pressKey(t, m, "{")
+	if m.timeTravel.index != 1 {
+		t.Errorf("expected a minute back at index 1, got %d", m.timeTravel.index)
+	}
+	m = pressKey(t, m, "{")
+	m = pressKey(t, m, "{")
+	if m.timeTravel.index != 0 || len(m.snapshot.Town.Rigs) != 0 {
+		t.Errorf("expected oldest snapshot, got index %d", m.timeTravel.index)
+	}
+
+	// Refreshes update the live snapshot without leaving history
+	fresh := &data.Snapshot{Town: &data.TownStatus{Name: "fresh"}}
+	updated, _ := m.Update(refreshMsg{snapshot: fresh})
+	m = updated.(Model)
+	if m.snapshot.Town.Name != "test-town" {
+		t.Error("refresh should not replace the historical snapshot")
+	}
+
+	// Action keys are blocked while browsing history
+	m = pressKey(t, m, "w")
+	if m.createWorkForm != nil {
+		t.Error("actions should be blocked in time travel mode")
+	}
+
+	// Jumping past the newest entry returns to the latest live snapshot
+	for i := 0; i < 3; i++ {
+		m = pressKey(t, m, "}")
+	}
+	if m.timeTravel == nil || m.timeTravel.index != 3 {
+		t.Fatal("expected } to jump a minute at a time")
+	}
+	m = pressKey(t, m, "}")
+	if m.timeTravel != nil {
+		t.Fatal("expected to leave time travel after the newest entry")
+	}
+	if m.snapshot != fresh {
+		t.Error("expected latest live snapshot to be restored")
+	}
+}
+
+func TestTimeTravelJumpsByTime(t *testing.T) {
+	base := time.Date(2026, 1, 8, 16, 0, 0, 0, time.UTC)
+	var entries []data.HistoryEntry
+	for _, offset := range []time.Duration{0, 5 * time.Second, 20 * time.Second, 70 * time.Second, 75 * time.Second, 3 * time.Minute, 2 * time.Hour} {
+		entries = append(entries, data.HistoryEntry{At: base.Add(offset)})
+	}
+	m := NewTestModel(t)
+
+	tests := []struct {
+		from int
+		jump time.Duration
+		want int
+	}{
+		{from: 0, jump: time.Minute, want: 3},  // First entry a minute on, not 12 entries on
+		{from: 3, jump: time.Minute, want: 5},  // Gaps jump past several minutes
+		{from: 4, jump: -time.Minute, want: 1}, // First entry at least a minute back
+		{from: 2, jump: -time.Minute, want: 0}, // Clamps to the oldest
+		{from: 6, jump: -time.Hour, want: 5},   // An hour back in one press
+	}
+	for _, tt := range tests {
+		m.timeTravel = &timeTravelState{entries: entries, index: tt.from}
+		updated, _ := m.jumpTimeTravel(tt.jump)
+		if got := updated.(Model).timeTravel.index; got != tt.want {
+			t.Errorf("jump %s from %d: got index %d, want %d", tt.jump, tt.from, got, tt.want)
+		}
+	}
+
+	if m.keyMap().Lookup("(") != KeyHistoryHourBack || m.keyMap().Lookup(")") != KeyHistoryHourForward {
+		t.Error("expected ( and ) to jump by the hour")
+	}
+
+	m.timeTravel = &timeTravelState{entries: entries, index: 6}
+	if updated, _ := m.jumpTimeTravel(time.Minute); updated.(Model).timeTravel != nil {
+		t.Error("expected a jump past the newest entry to return to live data")
+	}
+}
+
+func TestTimeTravelWithoutHistory(t *testing.T) {
+	m := NewTestModel(t)
+
+	m = pressKey(t, m, "[")
+	if m.timeTravel != nil {
+		t.Error("should not enter time travel with no recorded history")
+	}
+	if m.statusMessage == nil || !strings.Contains(m.statusMessage.Text, "No snapshot history") {
+		t.Errorf("expected no-history status, got %+v", m.statusMessage)
+	}
+
+	m.store.History = nil
+	m = pressKey(t, m, "[")
+	if m.statusMessage == nil || !strings.Contains(m.statusMessage.Text, "disabled") {
+		t.Errorf("expected disabled status, got %+v", m.statusMessage)
+	}
+}
@@ -0,0 +1,393 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// townCardWidth is the width of one town's column in the towns overview.
+const townCardWidth = 30
+
+// TownsView is the town switcher and side-by-side overview of every
+// registered town.
+type TownsView struct {
+	Towns     []*data.TownStore
+	Selection int
+	Loading   bool
+}
+
+// townsRefreshedMsg reports that every town in a view has reloaded.
+type townsRefreshedMsg struct{ view *TownsView }
+
+// townsTickMsg schedules the next overview refresh.
+type townsTickMsg struct{ view *TownsView }
+
+// townRegistry returns the registered towns in config order, with the
+// current town first when it is not registered.
+func (m Model) townRegistry() []data.TownStore {
+	var towns []data.TownStore
+	current := false
+	for _, t := range m.settings.Towns {
+		ts := data.TownStore{Name: t.Name, Root: t.Root}
+		if m.remote == "" && filepath.Clean(t.Root) == filepath.Clean(m.townRoot) {
+			// Share the dashboard's store rather than loading it twice
+			ts.Store = m.store
+			current = true
+		}
+		towns = append(towns, ts)
+	}
+	if !current {
+		name := filepath.Base(m.townRoot)
+		if m.snapshot != nil && m.snapshot.Town != nil && m.snapshot.Town.Name != "" {
+			name = m.snapshot.Town.Name
+		}
+		root := m.townRoot
+		if m.remote != "" {
+			root = m.remote + ":" + root
+		}
+		towns = append([]data.TownStore{{Name: name, Root: root, Store: m.store}}, towns...)
+	}
+	return towns
+}
+
+// townQualifier returns the current town's name for qualifying addresses
+// in the dashboard lists, or "" when it is the only town.
+func (m Model) townQualifier() string {
+	towns := m.townRegistry()
+	if len(towns) < 2 {
+		return ""
+	}
+	for _, ts := range towns {
+		if ts.Store == m.store {
+			return ts.Name
+		}
+	}
+	return ""
+}
+
+// openTowns opens the towns overview with the current town selected and
+// starts loading every town.
+func (m *Model) openTowns() tea.Cmd {
+	if m.towns == nil {
+		m.towns = data.NewTowns()
+	}
+	m.towns.Set(m.townRegistry())
+	view := &TownsView{Towns: m.towns.List(), Loading: true}
+	for i, ts := range view.Towns {
+		if ts.Store != m.store {
+			ts.Store.SetLifecycleEvents(m.settings.LifecycleEvents)
+		} else {
+			view.Selection = i
+		}
+	}
+	m.townsView = view
+	return m.refreshTownsCmd(view)
+}
+
+// refreshTownsCmd reloads every town in the view off the UI goroutine.
+func (m Model) refreshTownsCmd(view *TownsView) tea.Cmd {
+	towns := m.towns
+	timeout := m.loadTimeout()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		towns.Refresh(ctx)
+		return townsRefreshedMsg{view: view}
+	}
+}
+
+// handleTownsRefreshed schedules the next refresh while the view is open.
+// Messages from a closed view end its refresh loop.
+func (m Model) handleTownsRefreshed(msg townsRefreshedMsg) (tea.Model, tea.Cmd) {
+	if msg.view != m.townsView {
+		return m, nil
+	}
+	m.townsView.Loading = false
+	view := msg.view
+	return m, tea.Tick(m.refreshInterval, func(time.Time) tea.Msg {
+		return townsTickMsg{view: view}
+	})
+}
+
+// handleTownsKey handles key presses in the towns overview.
+func (m Model) handleTownsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	view := m.townsView
+	switch msg.String() {
+	case "esc", "q":
+		m.townsView = nil
+		return m, nil
+
+	case "l", "right", "j", "down", "tab":
+		if view.Selection < len(view.Towns)-1 {
+			view.Selection++
+		}
+		return m, nil
+
+	case "h", "left", "k", "up", "shift+tab":
+		if view.Selection > 0 {
+			view.Selection--
+		}
+		return m, nil
+
+	case "r":
+		view.Loading = true
+		return m, m.refreshTownsCmd(view)
+
+	case "enter":
+		if view.Selection < 0 || view.Selection >= len(view.Towns) {
+			return m, nil
+		}
+		ts := view.Towns[view.Selection]
+		m.townsView = nil
+		if ts.Store == m.store {
+			return m, nil
+		}
+		load := m.switchTown(ts.Root, ts.Store)
+		// Show the overview's snapshot until the dashboard's first load
+		if snap := ts.Store.Snapshot(); snap != nil {
+			m.applySnapshot(snap)
+		}
+		m.setStatus("Switched to town: "+ts.Name, false)
+		return m, tea.Batch(load, statusExpireCmd(3*time.Second))
+	}
+	return m, nil
+}
+
+// switchTown points the dashboard at another town root and returns the
+// command that loads and watches it. store is the overview's store for the
+// town, if any, which the dashboard takes over. Dry-run and read-only modes carry over
+// to the new action runner. Registered towns are local, so switching away
+// from a remote town drops its SSH connection.
+func (m *Model) switchTown(root string, store *data.Store) tea.Cmd {
+	m.stopWatch()
+	m.remote = ""
+	m.townRoot = root
+	m.store = m.dashboardStore(root, store)
+	_, dry := m.actionRunner.DryRun()
+	m.actionRunner = m.newActionRunner(root)
+	m.actionRunner.SetDryRun(dry)
+	m.snapshot = nil
+	m.sidebar = NewSidebarState()
+	m.applySettings(m.settings)
+	m.selectedRig = ""
+	m.selectedAgent = ""
+	return tea.Batch(m.loadData, m.startWatchCmd())
+}
+
+// townHealth condenses a town's snapshot for the overview.
+type townHealth struct {
+	Loaded    bool
+	Healthy   bool
+	State     string
+	Rigs      int
+	Running   int
+	Working   int
+	Stopped   int
+	MRs       int
+	Conflicts int
+	Unread    int
+	Errors    int
+	LoadedAt  time.Time
+}
+
+// summarizeTown computes a town's health the way `perch status` judges it:
+// the town status loaded and no operational issues.
+func summarizeTown(snap *data.Snapshot) townHealth {
+	var h townHealth
+	if snap == nil {
+		return h
+	}
+	h.Loaded = true
+	h.LoadedAt = snap.LoadedAt
+	h.Errors = len(snap.LoadErrors)
+	h.Unread = snap.UnreadMailCount()
+	h.State = "UNKNOWN"
+	if snap.OperationalState != nil {
+		h.State = snap.OperationalState.Summary()
+	}
+	h.Healthy = snap.Town != nil && (snap.OperationalState == nil || !snap.OperationalState.HasIssues())
+	if snap.Town != nil {
+		h.Rigs = len(snap.Town.Rigs)
+		for _, a := range snap.Town.Agents {
+			switch {
+			case !a.Running:
+				h.Stopped++
+			case a.HasWork:
+				h.Running++
+				h.Working++
+			default:
+				h.Running++
+			}
+		}
+	}
+	for _, mrs := range snap.MergeQueues {
+		h.MRs += len(mrs)
+		for _, mr := range mrs {
+			if mr.HasConflicts || mr.NeedsRebase {
+				h.Conflicts++
+			}
+		}
+	}
+	return h
+}
+
+// townAttention lists what needs a look in a town, with town-qualified
+// addresses.
+func townAttention(town string, snap *data.Snapshot) []string {
+	if snap == nil {
+		return nil
+	}
+	var items []string
+	if snap.Town != nil {
+		for _, a := range snap.Town.Agents {
+			addr := data.QualifyAddress(town, a.Address)
+			switch {
+			case !a.Running && a.HasWork:
+				items = append(items, addr+"  stopped with hooked work")
+			case a.UnreadMail > 0:
+				items = append(items, fmt.Sprintf("%s  %d unread", addr, a.UnreadMail))
+			}
+		}
+	}
+	rigs := make([]string, 0, len(snap.MergeQueues))
+	for rig := range snap.MergeQueues {
+		rigs = append(rigs, rig)
+	}
+	sort.Strings(rigs)
+	for _, rig := range rigs {
+		for _, mr := range snap.MergeQueues[rig] {
+			addr := data.QualifyAddress(town, rig+"/"+mr.ID)
+			switch {
+			case mr.HasConflicts:
+				items = append(items, addr+"  merge conflicts")
+			case mr.NeedsRebase:
+				items = append(items, addr+"  needs rebase")
+			}
+		}
+	}
+	if snap.OperationalState != nil {
+		for _, issue := range snap.OperationalState.Issues {
+			items = append(items, data.QualifyAddress(town, "town")+"  "+issue)
+		}
+	}
+	for _, e := range snap.LoadErrors {
+		items = append(items, data.QualifyAddress(town, e.Source)+"  load failed")
+	}
+	return items
+}
+
+// renderTowns renders the towns overview: one column per town, then the
+// items needing attention across all of them.
+func (m Model) renderTowns() string {
+	view := m.townsView
+	width := max(m.width-4, townCardWidth+4)
+	inner := width - 4
+
+	var b strings.Builder
+	title := "Towns"
+	if view.Loading {
+		title += " (loading...)"
+	}
+	b.WriteString(helpTitleStyle.Render(title))
+	b.WriteString("\n\n")
+
+	perRow := max(inner/townCardWidth, 1)
+	var rows []string
+	for i := 0; i < len(view.Towns); i += perRow {
+		var cards []string
+		for j := i; j < len(view.Towns) && j < i+perRow; j++ {
+			cards = append(cards, m.renderTownCard(view.Towns[j], j == view.Selection))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cards...))
+	}
+	b.WriteString(lipgloss.JoinVertical(lipgloss.Left, rows...))
+	b.WriteString("\n\n")
+
+	var attention []string
+	for _, ts := range view.Towns {
+		attention = append(attention, townAttention(ts.Name, ts.Store.Snapshot())...)
+	}
+	b.WriteString(headerStyle.Render("Needs attention"))
+	b.WriteString("\n")
+	// Leave room for the border, padding, title, cards and footer
+	maxLines := max(m.height-len(rows)*10-12, 3)
+	if len(attention) == 0 {
+		b.WriteString(mutedStyle.Render("Nothing across " + itoa(len(view.Towns)) + " towns"))
+		b.WriteString("\n")
+	}
+	for i, item := range attention {
+		if i == maxLines {
+			b.WriteString(mutedStyle.Render("  ... " + itoa(len(attention)-i) + " more"))
+			b.WriteString("\n")
+			break
+		}
+		b.WriteString(itemStyle.Render(truncate(item, inner)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("h/l: select • enter: switch • r: refresh • esc: close"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(1, 2).
+		Width(width).
+		Render(b.String())
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// renderTownCard renders one town's health column.
+func (m Model) renderTownCard(ts *data.TownStore, selected bool) string {
+	h := summarizeTown(ts.Store.Snapshot())
+	inner := townCardWidth - 4
+
+	name := ts.Name
+	if ts.Store == m.store {
+		name += " (current)"
+	}
+	lines := []string{headerStyle.Render(truncate(name, inner)), mutedStyle.Render(truncate(ts.Root, inner))}
+	switch {
+	case !h.Loaded:
+		lines = append(lines, mutedStyle.Render("Loading..."))
+	case h.Healthy:
+		lines = append(lines, statusStyle.Render("● "+h.State))
+	default:
+		lines = append(lines, statusErrorStyle.Render("✗ "+h.State))
+	}
+	if h.Loaded {
+		lines = append(lines,
+			fmt.Sprintf("Rigs     %d", h.Rigs),
+			fmt.Sprintf("Agents   %d up, %d working", h.Running, h.Working),
+			fmt.Sprintf("Stopped  %d", h.Stopped),
+			fmt.Sprintf("MQ       %d, %d blocked", h.MRs, h.Conflicts),
+			fmt.Sprintf("Unread   %d", h.Unread),
+		)
+		if h.Errors > 0 {
+			lines = append(lines, statusErrorStyle.Render(fmt.Sprintf("Errors   %d", h.Errors)))
+		} else {
+			lines = append(lines, mutedStyle.Render("Loaded "+h.LoadedAt.Format("15:04:05")))
+		}
+	}
+
+	border := lipgloss.Color("240")
+	if selected {
+		border = lipgloss.Color("63")
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(border).
+		Padding(0, 1).
+		Width(townCardWidth - 2).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andyrewlee/perch/data"
+	"github.com/andyrewlee/perch/internal/config"
+	"github.com/andyrewlee/perch/internal/testutil"
+)
+
+func mockStore(root string) *data.Store {
+	mock := testutil.NewMockRunner()
+	mock.On([]string{"gt", "status", "--json"}, testutil.NewFixtures().TownStatusJSON(), nil, nil)
+	return data.NewStoreWithLoader(data.NewLoaderWithRunner(root, mock))
+}
+
+func TestTownsOverviewAndSwitch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m, _ := createTestModel(t)
+	m.store = mockStore(m.townRoot)
+	webRoot := t.TempDir()
+	m.settings.Towns = []config.Town{{Name: "web", Root: webRoot}}
+	// Seed the web town's store so the overview does not shell out
+	m.towns = data.NewTowns()
+	m.towns.Set([]data.TownStore{{Name: "web", Root: webRoot, Store: mockStore(webRoot)}})
+
+	m, cmd := sendKey(m, "W")
+	view := m.townsView
+	if view == nil || len(view.Towns) != 2 || view.Towns[0].Store != m.store || view.Towns[1].Name != "web" {
+		t.Fatalf("expected the current town then web, got %+v", view)
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(Model)
+	if m.townsView.Loading {
+		t.Error("expected the overview to finish loading")
+	}
+	out := m.View()
+	for _, want := range []string{"(current)", "web", "Rigs     2", "web:mayor/  1 unread"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the overview, got:\n%s", want, out)
+		}
+	}
+
+	m, _ = sendKey(m, "l")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.townsView != nil || m.townRoot != webRoot || m.actionRunner.TownRoot != webRoot {
+		t.Fatalf("expected to switch to web, got town %q", m.townRoot)
+	}
+	if m.snapshot == nil || m.snapshot.Town == nil {
+		t.Error("expected the overview's snapshot to show right away")
+	}
+	if m.store != view.Towns[1].Store || m.store.History == nil {
+		t.Error("expected the dashboard to take over the overview's store, recording history")
+	}
+}
+
+func TestTownAttentionQualifiesAddresses(t *testing.T) {
+	snap := &data.Snapshot{
+		Town: &data.TownStatus{Agents: []data.Agent{
+			{Address: "perch/polecats/nux", HasWork: true},
+			{Address: "perch/witness", Running: true},
+		}},
+		MergeQueues: map[string][]data.MergeRequest{"perch": {{ID: "mr-1", HasConflicts: true}}},
+		LoadErrors:  []data.LoadError{{Source: "mail"}},
+	}
+	got := strings.Join(townAttention("web", snap), "\n")
+	for _, want := range []string{"web:perch/polecats/nux  stopped with hooked work", "web:perch/mr-1  merge conflicts", "web:mail  load failed"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "witness") {
+		t.Errorf("expected healthy agents left out, got:\n%s", got)
+	}
+}
+
+func TestDashboardListsQualifyWithSeveralTowns(t *testing.T) {
+	m := NewTestModel(t)
+	snap := &data.Snapshot{
+		Town:        &data.TownStatus{Agents: []data.Agent{{Name: "mayor", Address: "mayor/"}}},
+		MergeQueues: map[string][]data.MergeRequest{"perch": {{ID: "mr-1", Title: "Add auth"}}},
+		Mail:        []data.MailMessage{{ID: "m-1", Subject: "hello"}},
+	}
+	labels := func() string {
+		m.sidebar.UpdateFromSnapshot(snap)
+		return m.sidebar.Agents[0].Label() + "\n" + m.sidebar.MRs[0].Label() + "\n" + m.sidebar.Mail[0].Label()
+	}
+
+	if got := labels(); strings.Contains(got, ":") {
+		t.Errorf("expected plain labels with one town, got:\n%s", got)
+	}
+
+	cfg := m.settings
+	cfg.Towns = []config.Town{{Name: "api", Root: m.townRoot}, {Name: "web", Root: t.TempDir()}}
+	if err := m.applySettings(cfg); err != nil {
+		t.Fatal(err)
+	}
+	got := labels()
+	for _, want := range []string{"api:mayor", "[api:perch] Add auth", "api:hello"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q, got:\n%s", want, got)
+		}
+	}
+}
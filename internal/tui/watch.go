@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// watchStartedMsg carries a new file watcher for a store, or why there is
+// none.
+type watchStartedMsg struct {
+	store   *data.Store
+	watcher *data.Watcher
+	err     error
+}
+
+// filesChangedMsg reports the sources whose files changed.
+type filesChangedMsg struct {
+	watcher *data.Watcher
+	sources []string
+}
+
+// watchStoppedMsg reports that a watcher closed its Changes channel.
+type watchStoppedMsg struct {
+	watcher *data.Watcher
+}
+
+// startWatchCmd starts watching the town's files when enabled. Without a
+// watcher the dashboard polls at the refresh interval.
+func (m Model) startWatchCmd() tea.Cmd {
+	if !m.settings.Watch || m.watcher != nil || m.store == nil {
+		return nil
+	}
+	store := m.store
+	var rigs []string
+	if m.snapshot != nil {
+		rigs = m.snapshot.RigNames()
+	}
+	return func() tea.Msg {
+		w, err := store.Loader().Watch(rigs)
+		return watchStartedMsg{store: store, watcher: w, err: err}
+	}
+}
+
+// stopWatch closes the file watcher, if any, and resumes polling.
+func (m *Model) stopWatch() {
+	if m.watcher != nil {
+		m.watcher.Close()
+		m.watcher = nil
+		m.applySchedule()
+	}
+}
+
+// syncWatchCmd starts or stops the watcher to match the settings.
+func (m *Model) syncWatchCmd() tea.Cmd {
+	if !m.settings.Watch {
+		m.stopWatch()
+		return nil
+	}
+	return m.startWatchCmd()
+}
+
+// handleWatchStarted keeps a watcher for the current store. Towns that
+// can't be watched, like remote ones, keep polling quietly.
+func (m Model) handleWatchStarted(msg watchStartedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		if errors.Is(msg.err, data.ErrWatchUnsupported) {
+			return m, nil
+		}
+		m.setStatus("Not watching files, polling instead: "+msg.err.Error(), true)
+		return m, statusExpireCmd(5 * time.Second)
+	}
+	if msg.store != m.store || m.watcher != nil || !m.settings.Watch {
+		msg.watcher.Close()
+		return m, nil
+	}
+	m.watcher = msg.watcher
+	m.applySchedule()
+	if m.snapshot != nil {
+		m.watcher.SetRigs(m.snapshot.RigNames())
+	}
+	return m, waitForChanges(m.watcher)
+}
+
+// handleFilesChanged reloads the changed sources and waits for the next
+// change. Messages from a replaced watcher are dropped.
+func (m Model) handleFilesChanged(msg filesChangedMsg) (tea.Model, tea.Cmd) {
+	if msg.watcher != m.watcher {
+		return m, nil
+	}
+	return m, tea.Batch(waitForChanges(m.watcher), m.reloadSourcesCmd(msg.sources))
+}
+
+// waitForChanges blocks until the watcher reports changed sources.
+func waitForChanges(w *data.Watcher) tea.Cmd {
+	return func() tea.Msg {
+		sources, ok := <-w.Changes
+		if !ok {
+			return watchStoppedMsg{watcher: w}
+		}
+		return filesChangedMsg{watcher: w, sources: sources}
+	}
+}
+
+// reloadSourcesCmd reloads the sources whose files changed. It runs
+// alongside the scheduled refresh, so it leaves isRefreshing alone.
+func (m Model) reloadSourcesCmd(sources []string) tea.Cmd {
+	load := m.loadSourcesCmd(sources)
+	return func() tea.Msg {
+		msg := load().(refreshMsg)
+		msg.partial = true
+		return msg
+	}
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+func TestWatchReloadsChangedSources(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	for _, dir := range []string{"mayor", "logs"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := NewWithTownRoot(root)
+	// Keep snapshot history out of the real home directory
+	m.store.History = data.NewHistory(t.TempDir())
+	m.refreshInterval = 10 * time.Second
+
+	msg := m.startWatchCmd()().(watchStartedMsg)
+	if errors.Is(msg.err, data.ErrWatchUnsupported) {
+		t.Skip("file watching is not supported here")
+	}
+	if msg.err != nil {
+		t.Fatal(msg.err)
+	}
+	updated, wait := m.Update(msg)
+	m = updated.(Model)
+	if m.watcher == nil || wait == nil {
+		t.Fatal("expected the watcher to be kept")
+	}
+	if every := m.store.Schedule().Policy(data.SourceIssues).Interval; every != m.settings.WatchFallbackInterval {
+		t.Errorf("expected issues to poll every %s, got %s", m.settings.WatchFallbackInterval, every)
+	}
+
+	log := "2026-01-02 07:09:03 [done] perch/nux completed pe-1\n"
+	if err := os.WriteFile(filepath.Join(root, "logs", "town.log"), []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+	changed := make(chan tea.Msg, 1)
+	go func() { changed <- wait() }()
+	select {
+	case msg := <-changed:
+		files, ok := msg.(filesChangedMsg)
+		if !ok || !slices.Contains(files.sources, data.SourceLifecycle) {
+			t.Fatalf("expected the lifecycle source to change, got %#v", msg)
+		}
+		snap := m.reloadSourcesCmd(files.sources)().(refreshMsg)
+		if !snap.partial || snap.snapshot.Lifecycle == nil || len(snap.snapshot.Lifecycle.Events) != 1 {
+			t.Errorf("expected a partial reload with the new event, got %+v", snap)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change reported")
+	}
+
+	// A partial reload leaves a full refresh in flight
+	m.isRefreshing = true
+	updated, _ = m.Update(refreshMsg{snapshot: m.store.Snapshot(), partial: true})
+	if m = updated.(Model); !m.isRefreshing {
+		t.Error("expected the full refresh to still be running")
+	}
+
+	// Switching towns drops the old watcher and its late messages
+	old := m.watcher
+	m.switchTown(t.TempDir(), nil)
+	if m.watcher != nil || m.store.Schedule().Policy(data.SourceIssues).Interval != m.refreshInterval {
+		t.Error("expected polling after switching towns")
+	}
+	if _, cmd := m.Update(filesChangedMsg{watcher: old, sources: data.AllSources}); cmd != nil {
+		t.Error("expected changes from the old watcher to be ignored")
+	}
+}
@@ -0,0 +1,218 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+)
+
+// Default delivery settings for a Rule.
+const (
+	DefaultMaxRetries = 3
+	DefaultBackoff    = time.Second
+	DefaultTimeout    = 10 * time.Second
+)
+
+// Config is the webhook configuration file, ~/.perch/webhooks.json by default.
+//
+//	{
+//	  "rules": [
+//	    {
+//	      "name": "chat",
+//	      "url": "https://chat.example.com/hooks/abc",
+//	      "events": ["alert_firing", "convoy_landed"],
+//	      "template": "{\"text\": {{json .Summary}}}",
+//	      "headers": {"Authorization": "Bearer s3cret"},
+//	      "max_per_minute": 10
+//	    }
+//	  ]
+//	}
+type Config struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule routes matching events to one URL.
+type Rule struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+
+	// Events lists event types (alert_firing, mr_merged, ...) or alert names
+	// (watchdog_down, mr_conflict, ...) to send. Empty sends everything.
+	Events []string `json:"events,omitempty"`
+
+	// Template is a text/template rendered with an Event to produce the body.
+	// The json function encodes a value as JSON. Empty sends the Event as JSON.
+	Template string `json:"template,omitempty"`
+
+	// Headers are added to every request. Content-Type defaults to application/json.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// MaxRetries is how many times a failed delivery is retried (default 3).
+	// Retries back off exponentially from Backoff (default 1s).
+	MaxRetries *int     `json:"max_retries,omitempty"`
+	Backoff    Duration `json:"backoff,omitempty"`
+
+	// Timeout bounds a single request (default 10s).
+	Timeout Duration `json:"timeout,omitempty"`
+
+	// MaxPerMinute drops events beyond this many per minute. Zero is unlimited.
+	MaxPerMinute int `json:"max_per_minute,omitempty"`
+
+	tmpl *template.Template
+}
+
+// Duration is a time.Duration that unmarshals from strings like "30s".
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"30s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// DefaultConfigPath returns ~/.perch/webhooks.json.
+func DefaultConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".perch", "webhooks.json"), nil
+}
+
+// LoadConfig reads and validates a webhook configuration file.
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Validate checks every rule and compiles payload templates.
+func (c *Config) Validate() error {
+	for i := range c.Rules {
+		r := &c.Rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		u, err := url.Parse(r.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("rule %q: url must be an absolute http(s) URL", r.Name)
+		}
+		for _, ev := range r.Events {
+			if !knownEvent(ev) {
+				return fmt.Errorf("rule %q: unknown event %q", r.Name, ev)
+			}
+		}
+		if r.MaxRetries != nil && *r.MaxRetries < 0 {
+			return fmt.Errorf("rule %q: max_retries must not be negative", r.Name)
+		}
+		if r.MaxPerMinute < 0 {
+			return fmt.Errorf("rule %q: max_per_minute must not be negative", r.Name)
+		}
+		if r.Template != "" {
+			tmpl, err := template.New(r.Name).Funcs(templateFuncs).Option("missingkey=error").Parse(r.Template)
+			if err != nil {
+				return fmt.Errorf("rule %q: %w", r.Name, err)
+			}
+			r.tmpl = tmpl
+		}
+	}
+	return nil
+}
+
+// knownEvents lists every value accepted in Rule.Events.
+var knownEvents = []string{
+	EventAlertFiring, EventAlertResolved, EventMRMerged, EventConvoyLanded,
+	AlertUnreadMail, AlertMRConflict, AlertMRRebase, AlertWatchdogDown, AlertAgentStopped, AlertLoadFailed,
+}
+
+func knownEvent(name string) bool {
+	for _, known := range knownEvents {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+// templateFuncs are available to payload templates.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// matches reports whether the rule wants e.
+func (r *Rule) matches(e Event) bool {
+	if len(r.Events) == 0 {
+		return true
+	}
+	for _, want := range r.Events {
+		if want == e.Type || (e.Alert != "" && want == e.Alert) {
+			return true
+		}
+	}
+	return false
+}
+
+// payload renders the request body for e.
+func (r *Rule) payload(e Event) ([]byte, error) {
+	if r.tmpl == nil {
+		return json.Marshal(e)
+	}
+	var buf bytes.Buffer
+	if err := r.tmpl.Execute(&buf, e); err != nil {
+		return nil, fmt.Errorf("rendering template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (r *Rule) maxRetries() int {
+	if r.MaxRetries == nil {
+		return DefaultMaxRetries
+	}
+	return *r.MaxRetries
+}
+
+func (r *Rule) backoff() time.Duration {
+	if r.Backoff <= 0 {
+		return DefaultBackoff
+	}
+	return time.Duration(r.Backoff)
+}
+
+func (r *Rule) timeout() time.Duration {
+	if r.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return time.Duration(r.Timeout)
+}
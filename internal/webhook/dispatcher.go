@@ -0,0 +1,245 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// queueSize is how many events a rule may have pending before new ones are dropped.
+const queueSize = 64
+
+// Dispatcher delivers events to webhook rules.
+// Each rule has its own queue and worker, so a slow endpoint only delays
+// its own deliveries.
+type Dispatcher struct {
+	// Client sends requests. If nil, uses http.DefaultClient.
+	Client *http.Client
+
+	// OnError is called when a delivery fails after all retries, or an
+	// event is dropped. It may be called from any goroutine.
+	OnError func(rule string, err error)
+
+	// Sleep waits between retries. If nil, waits on a timer; tests replace it.
+	Sleep func(ctx context.Context, d time.Duration) error
+
+	// Now returns the current time for rate limiting. If nil, uses time.Now.
+	Now func() time.Time
+
+	rules  []*ruleWorker
+	mu     sync.Mutex
+	last   *data.Snapshot
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+}
+
+// ruleWorker is a rule with its queue and rate limit window.
+type ruleWorker struct {
+	rule  *Rule
+	queue chan Event
+	sent  []time.Time // Delivery times within the last minute
+}
+
+// NewDispatcher creates a dispatcher for cfg's rules.
+func NewDispatcher(cfg *Config) *Dispatcher {
+	d := &Dispatcher{}
+	for i := range cfg.Rules {
+		d.rules = append(d.rules, &ruleWorker{rule: &cfg.Rules[i], queue: make(chan Event, queueSize)})
+	}
+	return d
+}
+
+// Attach chains the dispatcher onto store.OnRefresh.
+func (d *Dispatcher) Attach(store *data.Store) {
+	prev := store.OnRefresh
+	store.OnRefresh = func(snap *data.Snapshot) {
+		if prev != nil {
+			prev(snap)
+		}
+		d.Observe(snap)
+	}
+}
+
+// Start launches one delivery worker per rule until ctx is done or Stop is called.
+func (d *Dispatcher) Start(ctx context.Context) {
+	ctx, d.cancel = context.WithCancel(ctx)
+	for _, w := range d.rules {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.run(ctx, w)
+		}()
+	}
+}
+
+// Stop cancels pending deliveries and waits for workers to exit.
+func (d *Dispatcher) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.wg.Wait()
+}
+
+// Observe detects events against the previous snapshot and queues them.
+func (d *Dispatcher) Observe(snap *data.Snapshot) {
+	if snap == nil {
+		return
+	}
+	d.mu.Lock()
+	events := Detect(d.last, snap)
+	d.last = snap
+	d.mu.Unlock()
+
+	for _, e := range events {
+		d.Enqueue(e)
+	}
+}
+
+// Enqueue queues e for every matching rule, dropping it for rules whose queue is full.
+func (d *Dispatcher) Enqueue(e Event) {
+	for _, w := range d.rules {
+		if !w.rule.matches(e) {
+			continue
+		}
+		select {
+		case w.queue <- e:
+		default:
+			d.reportError(w.rule.Name, fmt.Errorf("queue full, dropped %s %s", e.Type, e.Key))
+		}
+	}
+}
+
+// run delivers queued events for one rule.
+func (d *Dispatcher) run(ctx context.Context, w *ruleWorker) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-w.queue:
+			if !d.allow(w) {
+				d.reportError(w.rule.Name, fmt.Errorf("rate limited, dropped %s %s", e.Type, e.Key))
+				continue
+			}
+			if err := d.deliver(ctx, w.rule, e); err != nil && ctx.Err() == nil {
+				d.reportError(w.rule.Name, err)
+			}
+		}
+	}
+}
+
+// allow applies the rule's per-minute limit. Only the rule's worker calls it.
+func (d *Dispatcher) allow(w *ruleWorker) bool {
+	if w.rule.MaxPerMinute == 0 {
+		return true
+	}
+	now := d.now()
+	kept := w.sent[:0]
+	for _, t := range w.sent {
+		if now.Sub(t) < time.Minute {
+			kept = append(kept, t)
+		}
+	}
+	w.sent = kept
+	if len(w.sent) >= w.rule.MaxPerMinute {
+		return false
+	}
+	w.sent = append(w.sent, now)
+	return true
+}
+
+// deliver POSTs e to the rule's URL, retrying transient failures with exponential backoff.
+func (d *Dispatcher) deliver(ctx context.Context, rule *Rule, e Event) error {
+	body, err := rule.payload(e)
+	if err != nil {
+		return err
+	}
+
+	backoff := rule.backoff()
+	for attempt := 0; ; attempt++ {
+		retry, err := d.post(ctx, rule, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= rule.maxRetries() {
+			return fmt.Errorf("delivering %s %s: %w", e.Type, e.Key, err)
+		}
+		if err := d.sleep(ctx, backoff); err != nil {
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// post sends one request. It reports whether a failure is worth retrying:
+// network errors, 429 and 5xx are; other 4xx responses are not.
+func (d *Dispatcher) post(ctx context.Context, rule *Rule, body []byte) (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, rule.timeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rule.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "perch-webhook")
+	for k, v := range rule.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The URL is left out of errors since webhook URLs often embed secrets
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = fmt.Errorf("%s request: %w", uerr.Op, uerr.Err)
+		}
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("endpoint returned %s", resp.Status)
+}
+
+func (d *Dispatcher) sleep(ctx context.Context, dur time.Duration) error {
+	if d.Sleep != nil {
+		return d.Sleep(ctx, dur)
+	}
+	t := time.NewTimer(dur)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func (d *Dispatcher) now() time.Time {
+	if d.Now != nil {
+		return d.Now()
+	}
+	return time.Now()
+}
+
+func (d *Dispatcher) reportError(rule string, err error) {
+	if d.OnError != nil {
+		d.OnError(rule, err)
+	}
+}
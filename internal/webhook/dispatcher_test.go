@@ -0,0 +1,285 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receiver is an httptest stand-in that records request bodies and
+// answers with scripted status codes.
+type receiver struct {
+	mu       sync.Mutex
+	bodies   []string
+	headers  []http.Header
+	statuses []int // Responses in order; 200 once exhausted
+	got      chan struct{}
+}
+
+func newReceiver(t *testing.T, statuses ...int) (*receiver, *httptest.Server) {
+	t.Helper()
+	r := &receiver{statuses: statuses, got: make(chan struct{}, 100)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.bodies = append(r.bodies, string(body))
+		r.headers = append(r.headers, req.Header.Clone())
+		status := http.StatusOK
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		r.mu.Unlock()
+		w.WriteHeader(status)
+		r.got <- struct{}{}
+	}))
+	t.Cleanup(srv.Close)
+	return r, srv
+}
+
+// wait blocks until n more requests arrive.
+func (r *receiver) wait(t *testing.T, n int) {
+	t.Helper()
+	for range n {
+		select {
+		case <-r.got:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for webhook request")
+		}
+	}
+}
+
+func (r *receiver) requests() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.bodies...)
+}
+
+// startDispatcher validates cfg and starts a dispatcher with instant backoff.
+func startDispatcher(t *testing.T, cfg *Config) (*Dispatcher, *[]time.Duration, chan error) {
+	t.Helper()
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	d := NewDispatcher(cfg)
+	var mu sync.Mutex
+	var sleeps []time.Duration
+	d.Sleep = func(ctx context.Context, dur time.Duration) error {
+		mu.Lock()
+		sleeps = append(sleeps, dur)
+		mu.Unlock()
+		return nil
+	}
+	errs := make(chan error, 100)
+	d.OnError = func(rule string, err error) { errs <- err }
+	d.Start(context.Background())
+	t.Cleanup(d.Stop)
+	return d, &sleeps, errs
+}
+
+func testEvent(typ, alert string) Event {
+	return Event{Type: typ, Alert: alert, Key: alert + ":x", At: t0, Town: "test-town", Summary: "Watchdog down: \"deacon\" stopped"}
+}
+
+func TestDispatcherDefaultPayload(t *testing.T) {
+	recv, srv := newReceiver(t)
+	d, _, _ := startDispatcher(t, &Config{Rules: []Rule{{
+		URL:     srv.URL,
+		Headers: map[string]string{"Authorization": "Bearer s3cret"},
+	}}})
+
+	d.Enqueue(testEvent(EventAlertFiring, AlertWatchdogDown))
+	recv.wait(t, 1)
+
+	var got Event
+	if err := json.Unmarshal([]byte(recv.requests()[0]), &got); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if got.Type != EventAlertFiring || got.Alert != AlertWatchdogDown || got.Town != "test-town" {
+		t.Errorf("unexpected payload %+v", got)
+	}
+	h := recv.headers[0]
+	if h.Get("Content-Type") != "application/json" || h.Get("Authorization") != "Bearer s3cret" {
+		t.Errorf("unexpected headers %v", h)
+	}
+}
+
+func TestDispatcherTemplate(t *testing.T) {
+	recv, srv := newReceiver(t)
+	d, _, _ := startDispatcher(t, &Config{Rules: []Rule{{
+		URL:      srv.URL,
+		Template: `{"text": {{json .Summary}}, "channel": "#ops"}`,
+	}}})
+
+	d.Enqueue(testEvent(EventAlertFiring, AlertWatchdogDown))
+	recv.wait(t, 1)
+
+	var got map[string]string
+	if err := json.Unmarshal([]byte(recv.requests()[0]), &got); err != nil {
+		t.Fatalf("templated payload is not JSON: %v\n%s", err, recv.requests()[0])
+	}
+	if got["text"] != `Watchdog down: "deacon" stopped` || got["channel"] != "#ops" {
+		t.Errorf("unexpected payload %v", got)
+	}
+}
+
+func TestDispatcherEventFilter(t *testing.T) {
+	recv, srv := newReceiver(t)
+	d, _, _ := startDispatcher(t, &Config{Rules: []Rule{{
+		URL:    srv.URL,
+		Events: []string{AlertWatchdogDown, EventConvoyLanded},
+	}}})
+
+	d.Enqueue(testEvent(EventAlertFiring, AlertUnreadMail))
+	d.Enqueue(testEvent(EventMRMerged, ""))
+	d.Enqueue(testEvent(EventAlertResolved, AlertWatchdogDown))
+	d.Enqueue(testEvent(EventConvoyLanded, ""))
+	recv.wait(t, 2)
+
+	reqs := recv.requests()
+	if len(reqs) != 2 || !strings.Contains(reqs[0], EventAlertResolved) || !strings.Contains(reqs[1], EventConvoyLanded) {
+		t.Errorf("expected watchdog and convoy events only, got %v", reqs)
+	}
+}
+
+func TestDispatcherRetriesWithBackoff(t *testing.T) {
+	recv, srv := newReceiver(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	d, sleeps, errs := startDispatcher(t, &Config{Rules: []Rule{{URL: srv.URL, Backoff: Duration(time.Second)}}})
+
+	d.Enqueue(testEvent(EventAlertFiring, AlertWatchdogDown))
+	recv.wait(t, 3)
+
+	if got := *sleeps; len(got) != 2 || got[0] != time.Second || got[1] != 2*time.Second {
+		t.Errorf("expected backoff 1s then 2s, got %v", got)
+	}
+	select {
+	case err := <-errs:
+		t.Errorf("expected eventual success, got %v", err)
+	default:
+	}
+}
+
+func TestDispatcherGivesUp(t *testing.T) {
+	recv, srv := newReceiver(t, 500, 500, 500)
+	retries := 2
+	d, _, errs := startDispatcher(t, &Config{Rules: []Rule{{URL: srv.URL, MaxRetries: &retries}}})
+
+	d.Enqueue(testEvent(EventAlertFiring, AlertWatchdogDown))
+	recv.wait(t, 3)
+
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "500") || strings.Contains(err.Error(), srv.URL) {
+			t.Errorf("expected status in error without the URL, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a delivery error")
+	}
+}
+
+func TestDispatcherDoesNotRetryClientErrors(t *testing.T) {
+	recv, srv := newReceiver(t, http.StatusBadRequest)
+	d, sleeps, errs := startDispatcher(t, &Config{Rules: []Rule{{URL: srv.URL}}})
+
+	d.Enqueue(testEvent(EventAlertFiring, AlertWatchdogDown))
+	recv.wait(t, 1)
+	select {
+	case <-errs:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a delivery error")
+	}
+	if len(*sleeps) != 0 || len(recv.requests()) != 1 {
+		t.Errorf("expected no retries for 400, got %d requests", len(recv.requests()))
+	}
+}
+
+func TestDispatcherRateLimit(t *testing.T) {
+	recv, srv := newReceiver(t)
+	cfg := &Config{Rules: []Rule{{URL: srv.URL, MaxPerMinute: 2}}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	d := NewDispatcher(cfg)
+	now := t0
+	d.Now = func() time.Time { return now }
+	errs := make(chan error, 10)
+	d.OnError = func(rule string, err error) { errs <- err }
+
+	// Queue before starting so all three are seen in the same minute
+	for range 3 {
+		d.Enqueue(testEvent(EventAlertFiring, AlertWatchdogDown))
+	}
+	d.Start(context.Background())
+	t.Cleanup(d.Stop)
+
+	recv.wait(t, 2)
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "rate limited") {
+			t.Errorf("expected rate limit error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected third event to be rate limited")
+	}
+}
+
+func TestDispatcherObserve(t *testing.T) {
+	recv, srv := newReceiver(t)
+	d, _, _ := startDispatcher(t, &Config{Rules: []Rule{{URL: srv.URL}}})
+
+	healthy := healthySnapshot(t0)
+	down := healthySnapshot(t0.Add(5 * time.Second))
+	down.OperationalState.WatchdogHealthy = false
+
+	d.Observe(healthy)
+	d.Observe(down)
+	recv.wait(t, 1)
+	if !strings.Contains(recv.requests()[0], `"alert":"watchdog_down"`) {
+		t.Errorf("expected watchdog alert, got %s", recv.requests()[0])
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+		want string
+	}{
+		{"bad url", Rule{URL: "ftp://x"}, "url must be"},
+		{"unknown event", Rule{URL: "http://x", Events: []string{"meteor"}}, "unknown event"},
+		{"bad template", Rule{URL: "http://x", Template: "{{.Nope"}, "unclosed action"},
+		{"negative rate", Rule{URL: "http://x", MaxPerMinute: -1}, "max_per_minute"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Rules: []Rule{tt.rule}}
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	cfg := &Config{Rules: []Rule{{URL: "https://chat.example.com/hook"}}}
+	if err := cfg.Validate(); err != nil || cfg.Rules[0].Name != "rule-1" {
+		t.Errorf("expected default name, got %q, %v", cfg.Rules[0].Name, err)
+	}
+}
+
+func TestDurationJSON(t *testing.T) {
+	var r Rule
+	if err := json.Unmarshal([]byte(`{"url": "http://x", "backoff": "250ms", "timeout": "5s"}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.backoff() != 250*time.Millisecond || r.timeout() != 5*time.Second {
+		t.Errorf("unexpected durations %v %v", r.backoff(), r.timeout())
+	}
+	if err := json.Unmarshal([]byte(`{"backoff": 5}`), &r); err == nil {
+		t.Error("expected error for numeric duration")
+	}
+}
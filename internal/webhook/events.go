@@ -0,0 +1,166 @@
+// Package webhook POSTs JSON payloads to configured URLs when town alerts
+// fire or resolve, when MRs merge, and when convoys land.
+//
+// A Dispatcher attaches to the data Store's refresh hook, derives events by
+// comparing consecutive snapshots, and delivers them per Rule with
+// templated payloads, retries with exponential backoff, and a rate limit.
+package webhook
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+// Event types.
+const (
+	EventAlertFiring   = "alert_firing"   // An overview alert condition started
+	EventAlertResolved = "alert_resolved" // An overview alert condition cleared
+	EventMRMerged      = "mr_merged"      // An MR left the merge queue
+	EventConvoyLanded  = "convoy_landed"  // An open convoy closed
+)
+
+// Alert names, matching the conditions on the overview's alert list.
+const (
+	AlertUnreadMail   = "unread_mail"
+	AlertMRConflict   = "mr_conflict"
+	AlertMRRebase     = "mr_needs_rebase"
+	AlertWatchdogDown = "watchdog_down"
+	AlertAgentStopped = "agent_stopped"
+	AlertLoadFailed   = "load_failed"
+)
+
+// Event is a single webhook-worthy occurrence. It is the default payload
+// and the data passed to payload templates.
+type Event struct {
+	Type    string    `json:"type"`
+	Alert   string    `json:"alert,omitempty"` // Alert name for alert_* events
+	Key     string    `json:"key"`             // Stable identity, e.g. "mr_conflict:perch/mr-1"
+	At      time.Time `json:"at"`
+	Town    string    `json:"town,omitempty"`
+	Rig     string    `json:"rig,omitempty"`
+	Target  string    `json:"target,omitempty"` // Agent address, MR, convoy or load source
+	Summary string    `json:"summary"`
+}
+
+// alertState is one active alert condition.
+type alertState struct {
+	name, rig, target, summary string
+}
+
+// alerts returns the alert conditions active in snap, keyed by identity.
+// The conditions mirror the overview alert list, without its display limit.
+func alerts(snap *data.Snapshot) map[string]alertState {
+	active := make(map[string]alertState)
+	if snap == nil || snap.Town == nil {
+		return active
+	}
+	add := func(a alertState) {
+		active[a.name+":"+a.target] = a
+	}
+
+	for _, agent := range snap.Town.Agents {
+		if agent.UnreadMail > 0 {
+			add(alertState{AlertUnreadMail, "", agent.Address,
+				fmt.Sprintf("%s has %d unread mail", agent.Name, agent.UnreadMail)})
+		}
+	}
+
+	for rig, mrs := range snap.MergeQueues {
+		for _, mr := range mrs {
+			target := rig + "/" + mr.ID
+			if mr.HasConflicts {
+				add(alertState{AlertMRConflict, rig, target, fmt.Sprintf("[%s] %s has conflicts", rig, mr.Title)})
+			} else if mr.NeedsRebase {
+				add(alertState{AlertMRRebase, rig, target, fmt.Sprintf("[%s] %s needs rebase", rig, mr.Title)})
+			}
+		}
+	}
+
+	if state := snap.OperationalState; state != nil && !state.WatchdogHealthy {
+		reason := "deacon stopped"
+		if state.WatchdogReason != "" {
+			reason = state.WatchdogReason
+		}
+		add(alertState{AlertWatchdogDown, "", "deacon", "Watchdog down: " + reason})
+	}
+
+	for _, rig := range snap.Town.Rigs {
+		for _, agent := range rig.Agents {
+			if !agent.Running && (agent.Role == "witness" || agent.Role == "refinery") {
+				add(alertState{AlertAgentStopped, rig.Name, agent.Address,
+					fmt.Sprintf("[%s] %s stopped", rig.Name, agent.Role)})
+			}
+		}
+	}
+
+	for _, e := range snap.LoadErrors {
+		add(alertState{AlertLoadFailed, "", e.Source, fmt.Sprintf("%s failed: %s", e.SourceLabel(), e.Error)})
+	}
+	return active
+}
+
+// Detect returns the events between two snapshots, sorted by key within type.
+// A nil prev is a baseline and produces nothing, so alerts already active
+// at startup do not fire.
+func Detect(prev, next *data.Snapshot) []Event {
+	if prev == nil || next == nil {
+		return nil
+	}
+	var town string
+	if next.Town != nil {
+		town = next.Town.Name
+	}
+	event := func(typ, alert, key, rig, target, summary string) Event {
+		return Event{Type: typ, Alert: alert, Key: key, At: next.LoadedAt, Town: town, Rig: rig, Target: target, Summary: summary}
+	}
+
+	var events []Event
+
+	// Alerts are only compared when town status loaded both times;
+	// otherwise every alert would appear to resolve and refire.
+	if prev.Town != nil && next.Town != nil {
+		before, after := alerts(prev), alerts(next)
+		for key, a := range after {
+			if _, ok := before[key]; !ok {
+				events = append(events, event(EventAlertFiring, a.name, key, a.rig, a.target, a.summary))
+			}
+		}
+		for key, a := range before {
+			if _, ok := after[key]; !ok {
+				events = append(events, event(EventAlertResolved, a.name, key, a.rig, a.target, a.summary))
+			}
+		}
+	}
+
+	for _, c := range data.DiffSnapshots(prev, next) {
+		if c.Type == data.ChangeMRLeft {
+			target := c.Source + "/" + c.ID
+			events = append(events, event(EventMRMerged, "", "mr_merged:"+target, c.Source, target,
+				fmt.Sprintf("[%s] %s merged", c.Source, c.Summary)))
+		}
+	}
+
+	if prev.SourceLoaded("convoys") && next.SourceLoaded("closed_convoys") {
+		closed := make(map[string]data.Convoy, len(next.ClosedConvoys))
+		for _, c := range next.ClosedConvoys {
+			closed[c.ID] = c
+		}
+		for _, c := range prev.Convoys {
+			if landed, ok := closed[c.ID]; ok {
+				events = append(events, event(EventConvoyLanded, "", "convoy_landed:"+c.ID, "", c.ID,
+					fmt.Sprintf("Convoy %s landed", landed.Title)))
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Type != events[j].Type {
+			return events[i].Type < events[j].Type
+		}
+		return events[i].Key < events[j].Key
+	})
+	return events
+}
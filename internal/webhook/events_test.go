@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/perch/data"
+)
+
+var t0 = time.Date(2026, 1, 8, 17, 0, 0, 0, time.UTC)
+
+// healthySnapshot returns a town with no active alerts.
+func healthySnapshot(at time.Time) *data.Snapshot {
+	return &data.Snapshot{
+		LoadedAt: at,
+		Town: &data.TownStatus{
+			Name:   "test-town",
+			Agents: []data.Agent{{Name: "mayor", Address: "mayor/", Role: "mayor", Running: true}},
+			Rigs: []data.Rig{{
+				Name:   "perch",
+				Agents: []data.Agent{{Address: "perch/refinery", Role: "refinery", Running: true}},
+			}},
+		},
+		MergeQueues:      map[string][]data.MergeRequest{"perch": {{ID: "mr-1", Title: "Add auth"}}},
+		Convoys:          []data.Convoy{{ID: "cv-1", Title: "Auth"}},
+		OperationalState: &data.OperationalState{WatchdogHealthy: true},
+	}
+}
+
+func types(events []Event) []string {
+	out := make([]string, len(events))
+	for i, e := range events {
+		out[i] = e.Type + "/" + e.Key
+	}
+	return out
+}
+
+func TestDetectBaseline(t *testing.T) {
+	next := healthySnapshot(t0)
+	next.OperationalState.WatchdogHealthy = false
+	if events := Detect(nil, next); events != nil {
+		t.Errorf("expected no events for baseline, got %v", types(events))
+	}
+}
+
+func TestDetectAlerts(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(s *data.Snapshot)
+		alert  string
+		key    string
+	}{
+		{"unread mail", func(s *data.Snapshot) { s.Town.Agents[0].UnreadMail = 2 }, AlertUnreadMail, "unread_mail:mayor/"},
+		{"conflict", func(s *data.Snapshot) { s.MergeQueues["perch"][0].HasConflicts = true }, AlertMRConflict, "mr_conflict:perch/mr-1"},
+		{"rebase", func(s *data.Snapshot) { s.MergeQueues["perch"][0].NeedsRebase = true }, AlertMRRebase, "mr_needs_rebase:perch/mr-1"},
+		{"watchdog", func(s *data.Snapshot) { s.OperationalState.WatchdogHealthy = false }, AlertWatchdogDown, "watchdog_down:deacon"},
+		{"refinery stopped", func(s *data.Snapshot) { s.Town.Rigs[0].Agents[0].Running = false }, AlertAgentStopped, "agent_stopped:perch/refinery"},
+		{"load failed", func(s *data.Snapshot) {
+			s.LoadErrors = []data.LoadError{{Source: "mail", Error: "boom"}}
+		}, AlertLoadFailed, "load_failed:mail"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthy, alerting := healthySnapshot(t0), healthySnapshot(t0)
+			tt.mutate(alerting)
+
+			fired := Detect(healthy, alerting)
+			if len(fired) != 1 || fired[0].Type != EventAlertFiring || fired[0].Alert != tt.alert || fired[0].Key != tt.key {
+				t.Fatalf("expected %s firing with key %s, got %v", tt.alert, tt.key, types(fired))
+			}
+			if fired[0].Town != "test-town" || fired[0].Summary == "" {
+				t.Errorf("expected town and summary, got %+v", fired[0])
+			}
+
+			if again := Detect(alerting, alerting); len(again) != 0 {
+				t.Errorf("expected ongoing alert not to refire, got %v", types(again))
+			}
+
+			resolved := Detect(alerting, healthy)
+			if len(resolved) != 1 || resolved[0].Type != EventAlertResolved || resolved[0].Key != tt.key {
+				t.Errorf("expected %s resolved, got %v", tt.alert, types(resolved))
+			}
+		})
+	}
+}
+
+func TestDetectSkipsAlertsWithoutTownStatus(t *testing.T) {
+	prev := healthySnapshot(t0)
+	prev.OperationalState.WatchdogHealthy = false
+	next := healthySnapshot(t0.Add(5 * time.Second))
+	next.Town = nil
+	if events := Detect(prev, next); len(events) != 0 {
+		t.Errorf("expected no events when town status failed, got %v", types(events))
+	}
+}
+
+func TestDetectMRMerged(t *testing.T) {
+	prev, next := healthySnapshot(t0), healthySnapshot(t0.Add(5*time.Second))
+	next.MergeQueues["perch"] = nil
+	events := Detect(prev, next)
+	if len(events) != 1 || events[0].Type != EventMRMerged || events[0].Rig != "perch" || events[0].Target != "perch/mr-1" {
+		t.Fatalf("expected mr_merged for perch/mr-1, got %v", types(events))
+	}
+}
+
+func TestDetectConvoyLanded(t *testing.T) {
+	prev, next := healthySnapshot(t0), healthySnapshot(t0.Add(5*time.Second))
+	next.Convoys = nil
+	next.ClosedConvoys = []data.Convoy{{ID: "cv-1", Title: "Auth", Status: "closed"}}
+	events := Detect(prev, next)
+	if len(events) != 1 || events[0].Type != EventConvoyLanded || events[0].Target != "cv-1" {
+		t.Fatalf("expected convoy_landed for cv-1, got %v", types(events))
+	}
+
+	// Closed convoys that failed to load should not look like landings
+	next.LastSuccess = map[string]time.Time{"convoys": t0}
+	if events := Detect(prev, next); len(events) != 0 {
+		t.Errorf("expected no events without closed convoys, got %v", types(events))
+	}
+}